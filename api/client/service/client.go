@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 	proto "github.com/harmony-one/harmony/api/client/service/proto"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
 
 	"google.golang.org/grpc"
 )
@@ -19,6 +24,17 @@ type Client struct {
 	conn                *grpc.ClientConn
 }
 
+// TransactionError is the error of a transaction rejected by the node, with the
+// error code reported by the node.
+type TransactionError struct {
+	Code    proto.TransactionErrorCode
+	Message string
+}
+
+func (e *TransactionError) Error() string {
+	return fmt.Sprintf("transaction rejected with %v: %s", e.Code, e.Message)
+}
+
 // NewClient setups a Client given ip and port.
 func NewClient(ip, port string) *Client {
 	client := Client{}
@@ -74,3 +90,143 @@ func (client *Client) GetStakingContractInfo(address common.Address) *proto.Stak
 	}
 	return response
 }
+
+// GetUnbondingInfo gets the withdrawn stakes of an account which are still locked.
+func (client *Client) GetUnbondingInfo(address common.Address) (*proto.UnbondingInfoResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return client.clientServiceClient.GetUnbondingInfo(ctx, &proto.UnbondingInfoRequest{Address: address.Bytes()})
+}
+
+// GetProof gets the Merkle proof of an account and some of its storage at the
+// given block number, LatestBlockNumber meaning the current block.
+func (client *Client) GetProof(address common.Address, storageKeys []common.Hash, blockNumber uint64) *proto.GetProofResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &proto.GetProofRequest{Address: address.Bytes(), BlockNumber: blockNumber}
+	for _, key := range storageKeys {
+		request.StorageKeys = append(request.StorageKeys, key.Bytes())
+	}
+	response, err := client.clientServiceClient.GetProof(ctx, request)
+	if err != nil {
+		log.Fatalf("Error getting proof: %s", err)
+	}
+	return response
+}
+
+// SendTransaction sends a signed transaction to the transaction pool of the
+// node. A rejected transaction is returned as a *TransactionError.
+func (client *Client) SendTransaction(tx *types.Transaction) error {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	response, err := client.clientServiceClient.SendTransaction(ctx, &proto.SendTransactionRequest{Transaction: data})
+	if err != nil {
+		return err
+	}
+	if response.ErrorCode != proto.TransactionErrorCode_TX_OK {
+		return &TransactionError{Code: response.ErrorCode, Message: response.ErrorMessage}
+	}
+	return nil
+}
+
+// GetTransaction gets the status of a transaction, and its block position if it
+// has been included in the chain.
+func (client *Client) GetTransaction(hash common.Hash) *proto.GetTransactionResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	response, err := client.clientServiceClient.GetTransaction(ctx, &proto.GetTransactionRequest{TxId: hash.Bytes()})
+	if err != nil {
+		log.Fatalf("Error getting transaction: %s", err)
+	}
+	return response
+}
+
+// GetTransactionReceipt gets the receipt of a transaction. Transactions not yet
+// included in the chain only have their pool status set.
+func (client *Client) GetTransactionReceipt(hash common.Hash) *proto.GetTransactionReceiptResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	response, err := client.clientServiceClient.GetTransactionReceipt(ctx, &proto.GetTransactionReceiptRequest{TxId: hash.Bytes()})
+	if err != nil {
+		log.Fatalf("Error getting transaction receipt: %s", err)
+	}
+	return response
+}
+
+// Call executes a message call against the state of the given block number, zero
+// meaning the current block, without creating a transaction.
+func (client *Client) Call(msg types.Message, blockNumber uint64) (*proto.CallResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return client.clientServiceClient.Call(ctx, callRequest(msg, blockNumber))
+}
+
+// EstimateGas returns the lowest gas limit with which a message call succeeds
+// against the current state. The gas of the message caps the estimation if set.
+func (client *Client) EstimateGas(msg types.Message) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	response, err := client.clientServiceClient.EstimateGas(ctx, callRequest(msg, 0))
+	if err != nil {
+		return 0, err
+	}
+	return response.Gas, nil
+}
+
+func callRequest(msg types.Message, blockNumber uint64) *proto.CallRequest {
+	request := &proto.CallRequest{
+		From:        msg.From().Bytes(),
+		Gas:         msg.Gas(),
+		Data:        msg.Data(),
+		BlockNumber: blockNumber,
+	}
+	if msg.To() != nil {
+		request.To = msg.To().Bytes()
+	}
+	if msg.GasPrice() != nil {
+		request.GasPrice = msg.GasPrice().Bytes()
+	}
+	if msg.Value() != nil {
+		request.Value = msg.Value().Bytes()
+	}
+	return request
+}
+
+// VerifyProofResponse checks the commit signature of the header in a GetProof
+// response against the committee of its shard known by the given engine, then
+// the account proof against the state root of the header, and returns the
+// header and the proven account state.
+func VerifyProofResponse(address common.Address, response *proto.GetProofResponse, engine consensus.Engine) (*types.Header, *state.AccountProof, error) {
+	header := &types.Header{}
+	if err := rlp.DecodeBytes(response.Header, header); err != nil {
+		return nil, nil, err
+	}
+	// The client keeps no chain, the header is checked against the committee
+	// the engine holds for its shard.
+	if err := engine.VerifySeal(nil, header); err != nil {
+		return nil, nil, err
+	}
+	proof := &state.AccountProof{
+		Address:      address,
+		Balance:      new(big.Int).SetBytes(response.Balance),
+		Nonce:        response.Nonce,
+		CodeHash:     common.BytesToHash(response.CodeHash),
+		StorageHash:  common.BytesToHash(response.StorageHash),
+		AccountProof: response.AccountProof,
+	}
+	for _, storageProof := range response.StorageProof {
+		proof.StorageProof = append(proof.StorageProof, state.StorageProof{
+			Key:   common.BytesToHash(storageProof.Key),
+			Value: common.BytesToHash(storageProof.Value),
+			Proof: storageProof.Proof,
+		})
+	}
+	if err := state.VerifyAccountProof(header.Root, proof); err != nil {
+		return nil, nil, err
+	}
+	return header, proof, nil
+}
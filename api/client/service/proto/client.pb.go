@@ -22,6 +22,108 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// TransactionErrorCode is the reason a transaction was rejected by the node.
+type TransactionErrorCode int32
+
+const (
+	TransactionErrorCode_TX_OK                  TransactionErrorCode = 0
+	TransactionErrorCode_TX_UNKNOWN_ERROR       TransactionErrorCode = 1
+	TransactionErrorCode_TX_INVALID_ENCODING    TransactionErrorCode = 2
+	TransactionErrorCode_TX_INVALID_SHARD       TransactionErrorCode = 3
+	TransactionErrorCode_TX_INVALID_SENDER      TransactionErrorCode = 4
+	TransactionErrorCode_TX_NONCE_TOO_LOW       TransactionErrorCode = 5
+	TransactionErrorCode_TX_UNDERPRICED         TransactionErrorCode = 6
+	TransactionErrorCode_TX_REPLACE_UNDERPRICED TransactionErrorCode = 7
+	TransactionErrorCode_TX_INSUFFICIENT_FUNDS  TransactionErrorCode = 8
+	TransactionErrorCode_TX_INTRINSIC_GAS       TransactionErrorCode = 9
+	TransactionErrorCode_TX_GAS_LIMIT           TransactionErrorCode = 10
+	TransactionErrorCode_TX_NEGATIVE_VALUE      TransactionErrorCode = 11
+	TransactionErrorCode_TX_OVERSIZED_DATA      TransactionErrorCode = 12
+	TransactionErrorCode_TX_KNOWN_TRANSACTION   TransactionErrorCode = 13
+)
+
+var TransactionErrorCode_name = map[int32]string{
+	0:  "TX_OK",
+	1:  "TX_UNKNOWN_ERROR",
+	2:  "TX_INVALID_ENCODING",
+	3:  "TX_INVALID_SHARD",
+	4:  "TX_INVALID_SENDER",
+	5:  "TX_NONCE_TOO_LOW",
+	6:  "TX_UNDERPRICED",
+	7:  "TX_REPLACE_UNDERPRICED",
+	8:  "TX_INSUFFICIENT_FUNDS",
+	9:  "TX_INTRINSIC_GAS",
+	10: "TX_GAS_LIMIT",
+	11: "TX_NEGATIVE_VALUE",
+	12: "TX_OVERSIZED_DATA",
+	13: "TX_KNOWN_TRANSACTION",
+}
+
+var TransactionErrorCode_value = map[string]int32{
+	"TX_OK":                  0,
+	"TX_UNKNOWN_ERROR":       1,
+	"TX_INVALID_ENCODING":    2,
+	"TX_INVALID_SHARD":       3,
+	"TX_INVALID_SENDER":      4,
+	"TX_NONCE_TOO_LOW":       5,
+	"TX_UNDERPRICED":         6,
+	"TX_REPLACE_UNDERPRICED": 7,
+	"TX_INSUFFICIENT_FUNDS":  8,
+	"TX_INTRINSIC_GAS":       9,
+	"TX_GAS_LIMIT":           10,
+	"TX_NEGATIVE_VALUE":      11,
+	"TX_OVERSIZED_DATA":      12,
+	"TX_KNOWN_TRANSACTION":   13,
+}
+
+func (x TransactionErrorCode) String() string {
+	return proto.EnumName(TransactionErrorCode_name, int32(x))
+}
+
+func (TransactionErrorCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{0}
+}
+
+// TransactionStatus is the status of a transaction on the node.
+type TransactionStatus int32
+
+const (
+	// The transaction is neither in the chain nor in the transaction pool.
+	TransactionStatus_TX_STATUS_UNKNOWN TransactionStatus = 0
+	// The transaction is in the transaction pool but can't be executed yet (nonce gap).
+	TransactionStatus_TX_STATUS_QUEUED TransactionStatus = 1
+	// The transaction is in the transaction pool and can be included in the next block.
+	TransactionStatus_TX_STATUS_PENDING TransactionStatus = 2
+	// The transaction is included in a block and was executed successfully.
+	TransactionStatus_TX_STATUS_INCLUDED TransactionStatus = 3
+	// The transaction is included in a block but its execution failed.
+	TransactionStatus_TX_STATUS_FAILED TransactionStatus = 4
+)
+
+var TransactionStatus_name = map[int32]string{
+	0: "TX_STATUS_UNKNOWN",
+	1: "TX_STATUS_QUEUED",
+	2: "TX_STATUS_PENDING",
+	3: "TX_STATUS_INCLUDED",
+	4: "TX_STATUS_FAILED",
+}
+
+var TransactionStatus_value = map[string]int32{
+	"TX_STATUS_UNKNOWN":  0,
+	"TX_STATUS_QUEUED":   1,
+	"TX_STATUS_PENDING":  2,
+	"TX_STATUS_INCLUDED": 3,
+	"TX_STATUS_FAILED":   4,
+}
+
+func (x TransactionStatus) String() string {
+	return proto.EnumName(TransactionStatus_name, int32(x))
+}
+
+func (TransactionStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{1}
+}
+
 // FetchAccountStateRequest is the request to fetch an account's balance and nonce.
 type FetchAccountStateRequest struct {
 	// The account address
@@ -238,12 +340,15 @@ func (m *StakingContractInfoRequest) GetAddress() []byte {
 
 // StakingContractInfoResponse is the response of GetStakingContractInfo.
 type StakingContractInfoResponse struct {
-	// Contract address.
+	// The address the staking transactions are sent to.
 	ContractAddress string `protobuf:"bytes,1,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
 	// The balance of the staking account.
 	Balance []byte `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
 	// The nonce of the staking account.
-	Nonce                uint64   `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Nonce uint64 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// The total stake of the validator run by the account (big.Int), empty if the
+	// account is not a validator.
+	Stake                []byte   `protobuf:"bytes,4,opt,name=stake,proto3" json:"stake,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -295,162 +400,1479 @@ func (m *StakingContractInfoResponse) GetNonce() uint64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*FetchAccountStateRequest)(nil), "client.FetchAccountStateRequest")
-	proto.RegisterType((*FetchAccountStateResponse)(nil), "client.FetchAccountStateResponse")
-	proto.RegisterType((*GetFreeTokenRequest)(nil), "client.GetFreeTokenRequest")
-	proto.RegisterType((*GetFreeTokenResponse)(nil), "client.GetFreeTokenResponse")
-	proto.RegisterType((*StakingContractInfoRequest)(nil), "client.StakingContractInfoRequest")
-	proto.RegisterType((*StakingContractInfoResponse)(nil), "client.StakingContractInfoResponse")
+func (m *StakingContractInfoResponse) GetStake() []byte {
+	if m != nil {
+		return m.Stake
+	}
+	return nil
 }
 
-func init() { proto.RegisterFile("client.proto", fileDescriptor_014de31d7ac8c57c) }
+// GetProofRequest is the request to get the Merkle proof of an account and some of its storage.
+type GetProofRequest struct {
+	// The account address
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// The storage keys to prove
+	StorageKeys [][]byte `protobuf:"bytes,2,rep,name=storage_keys,json=storageKeys,proto3" json:"storage_keys,omitempty"`
+	// The block number of the state. The maximum uint64 value means the current
+	// block.
+	BlockNumber          uint64   `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
-var fileDescriptor_014de31d7ac8c57c = []byte{
-	// 302 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0x41, 0x4f, 0xf2, 0x40,
-	0x14, 0xfc, 0xda, 0x0f, 0x31, 0xbe, 0xd4, 0xa8, 0x2b, 0x31, 0xb5, 0x78, 0xa8, 0xeb, 0x05, 0x3d,
-	0x60, 0xa2, 0xc6, 0x3b, 0x21, 0x81, 0x10, 0x6e, 0xad, 0x27, 0x2f, 0x66, 0xd9, 0x3e, 0xb5, 0x81,
-	0xec, 0x62, 0xfb, 0x30, 0xfc, 0x19, 0xff, 0xab, 0xa1, 0xdd, 0x35, 0x35, 0xb6, 0x70, 0xdb, 0x99,
-	0x7d, 0xf3, 0x26, 0x3b, 0xb3, 0xe0, 0xc9, 0x45, 0x8a, 0x8a, 0xfa, 0xcb, 0x4c, 0x93, 0x66, 0xed,
-	0x12, 0xf1, 0x07, 0xf0, 0x47, 0x48, 0xf2, 0x7d, 0x20, 0xa5, 0x5e, 0x29, 0x8a, 0x49, 0x10, 0x46,
-	0xf8, 0xb1, 0xc2, 0x9c, 0x98, 0x0f, 0xfb, 0x22, 0x49, 0x32, 0xcc, 0x73, 0xdf, 0x09, 0x9d, 0x9e,
-	0x17, 0x59, 0xc8, 0xa7, 0x70, 0x5e, 0xa3, 0xca, 0x97, 0x5a, 0xe5, 0xb8, 0x91, 0xcd, 0xc4, 0x42,
-	0x28, 0x89, 0x56, 0x66, 0x20, 0xeb, 0xc0, 0x9e, 0xd2, 0x1b, 0xde, 0x0d, 0x9d, 0x5e, 0x2b, 0x2a,
-	0x01, 0xbf, 0x85, 0xd3, 0x31, 0xd2, 0x28, 0x43, 0x7c, 0xd2, 0x73, 0x54, 0xbb, 0xdd, 0x6f, 0xa0,
-	0xf3, 0x5b, 0x60, 0x8c, 0x19, 0xb4, 0x68, 0x3d, 0x49, 0xcc, 0x78, 0x71, 0xe6, 0x8f, 0x10, 0xc4,
-	0x24, 0xe6, 0xa9, 0x7a, 0x1b, 0x6a, 0x45, 0x99, 0x90, 0x34, 0x51, 0xaf, 0x7a, 0xb7, 0xc7, 0x1a,
-	0xba, 0xb5, 0x3a, 0x63, 0x75, 0x0d, 0xc7, 0xd2, 0xf0, 0x2f, 0xd5, 0x0d, 0x07, 0xd1, 0x91, 0xe5,
-	0x07, 0x25, 0x5d, 0x8d, 0xc3, 0x6d, 0x88, 0xe3, 0x7f, 0x25, 0x8e, 0xbb, 0x2f, 0x17, 0x0e, 0x87,
-	0x45, 0x39, 0x31, 0x66, 0x9f, 0xa9, 0x44, 0xf6, 0x0c, 0x27, 0x7f, 0xd2, 0x66, 0x61, 0xdf, 0xf4,
-	0xd9, 0x54, 0x5f, 0x70, 0xb9, 0x65, 0xa2, 0x7c, 0x06, 0xff, 0xc7, 0xa6, 0xe0, 0x55, 0xb3, 0x64,
-	0x5d, 0x2b, 0xaa, 0xa9, 0x24, 0xb8, 0xa8, 0xbf, 0xfc, 0x59, 0x26, 0xe1, 0x6c, 0x8c, 0x54, 0x93,
-	0x1b, 0xe3, 0x56, 0xd9, 0x5c, 0x46, 0x70, 0xb5, 0x75, 0xc6, 0x9a, 0xcc, 0xda, 0xc5, 0x07, 0xbe,
-	0xff, 0x0e, 0x00, 0x00, 0xff, 0xff, 0xe9, 0x3e, 0x0b, 0x3e, 0xd0, 0x02, 0x00, 0x00,
+func (m *GetProofRequest) Reset()         { *m = GetProofRequest{} }
+func (m *GetProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProofRequest) ProtoMessage()    {}
+func (*GetProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{6}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *GetProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProofRequest.Unmarshal(m, b)
+}
+func (m *GetProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProofRequest.Merge(m, src)
+}
+func (m *GetProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProofRequest.Size(m)
+}
+func (m *GetProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProofRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_GetProofRequest proto.InternalMessageInfo
 
-// ClientServiceClient is the client API for ClientService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ClientServiceClient interface {
-	FetchAccountState(ctx context.Context, in *FetchAccountStateRequest, opts ...grpc.CallOption) (*FetchAccountStateResponse, error)
-	GetFreeToken(ctx context.Context, in *GetFreeTokenRequest, opts ...grpc.CallOption) (*GetFreeTokenResponse, error)
-	GetStakingContractInfo(ctx context.Context, in *StakingContractInfoRequest, opts ...grpc.CallOption) (*StakingContractInfoResponse, error)
+func (m *GetProofRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
 }
 
-type clientServiceClient struct {
-	cc *grpc.ClientConn
+func (m *GetProofRequest) GetStorageKeys() [][]byte {
+	if m != nil {
+		return m.StorageKeys
+	}
+	return nil
 }
 
-func NewClientServiceClient(cc *grpc.ClientConn) ClientServiceClient {
-	return &clientServiceClient{cc}
+func (m *GetProofRequest) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
 }
 
-func (c *clientServiceClient) FetchAccountState(ctx context.Context, in *FetchAccountStateRequest, opts ...grpc.CallOption) (*FetchAccountStateResponse, error) {
-	out := new(FetchAccountStateResponse)
-	err := c.cc.Invoke(ctx, "/client.ClientService/FetchAccountState", in, out, opts...)
-	if err != nil {
-		return nil, err
+// StorageProof is the Merkle proof of one storage slot.
+type StorageProof struct {
+	// The storage key
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// The storage value
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// The trie nodes from the storage root to the value
+	Proof                [][]byte `protobuf:"bytes,3,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageProof) Reset()         { *m = StorageProof{} }
+func (m *StorageProof) String() string { return proto.CompactTextString(m) }
+func (*StorageProof) ProtoMessage()    {}
+func (*StorageProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{7}
+}
+
+func (m *StorageProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageProof.Unmarshal(m, b)
+}
+func (m *StorageProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageProof.Marshal(b, m, deterministic)
+}
+func (m *StorageProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageProof.Merge(m, src)
+}
+func (m *StorageProof) XXX_Size() int {
+	return xxx_messageInfo_StorageProof.Size(m)
+}
+func (m *StorageProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageProof proto.InternalMessageInfo
+
+func (m *StorageProof) GetKey() []byte {
+	if m != nil {
+		return m.Key
 	}
-	return out, nil
+	return nil
 }
 
-func (c *clientServiceClient) GetFreeToken(ctx context.Context, in *GetFreeTokenRequest, opts ...grpc.CallOption) (*GetFreeTokenResponse, error) {
-	out := new(GetFreeTokenResponse)
-	err := c.cc.Invoke(ctx, "/client.ClientService/GetFreeToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *StorageProof) GetValue() []byte {
+	if m != nil {
+		return m.Value
 	}
-	return out, nil
+	return nil
 }
 
-func (c *clientServiceClient) GetStakingContractInfo(ctx context.Context, in *StakingContractInfoRequest, opts ...grpc.CallOption) (*StakingContractInfoResponse, error) {
-	out := new(StakingContractInfoResponse)
-	err := c.cc.Invoke(ctx, "/client.ClientService/GetStakingContractInfo", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *StorageProof) GetProof() [][]byte {
+	if m != nil {
+		return m.Proof
 	}
-	return out, nil
+	return nil
 }
 
-// ClientServiceServer is the server API for ClientService service.
-type ClientServiceServer interface {
-	FetchAccountState(context.Context, *FetchAccountStateRequest) (*FetchAccountStateResponse, error)
-	GetFreeToken(context.Context, *GetFreeTokenRequest) (*GetFreeTokenResponse, error)
-	GetStakingContractInfo(context.Context, *StakingContractInfoRequest) (*StakingContractInfoResponse, error)
+// GetProofResponse is the response of GetProofRequest.
+type GetProofResponse struct {
+	// The RLP encoded header of the block, with the committee signatures.
+	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// The balance of the account (big.Int)
+	Balance []byte `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// The nonce of the account
+	Nonce uint64 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// The code hash of the account
+	CodeHash []byte `protobuf:"bytes,4,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+	// The storage root of the account
+	StorageHash []byte `protobuf:"bytes,5,opt,name=storage_hash,json=storageHash,proto3" json:"storage_hash,omitempty"`
+	// The trie nodes from the state root to the account
+	AccountProof [][]byte `protobuf:"bytes,6,rep,name=account_proof,json=accountProof,proto3" json:"account_proof,omitempty"`
+	// The proofs of the requested storage slots
+	StorageProof         []*StorageProof `protobuf:"bytes,7,rep,name=storage_proof,json=storageProof,proto3" json:"storage_proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func RegisterClientServiceServer(s *grpc.Server, srv ClientServiceServer) {
-	s.RegisterService(&_ClientService_serviceDesc, srv)
+func (m *GetProofResponse) Reset()         { *m = GetProofResponse{} }
+func (m *GetProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetProofResponse) ProtoMessage()    {}
+func (*GetProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{8}
 }
 
-func _ClientService_FetchAccountState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FetchAccountStateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *GetProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProofResponse.Unmarshal(m, b)
+}
+func (m *GetProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProofResponse.Merge(m, src)
+}
+func (m *GetProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetProofResponse.Size(m)
+}
+func (m *GetProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProofResponse proto.InternalMessageInfo
+
+func (m *GetProofResponse) GetHeader() []byte {
+	if m != nil {
+		return m.Header
 	}
-	if interceptor == nil {
-		return srv.(ClientServiceServer).FetchAccountState(ctx, in)
+	return nil
+}
+
+func (m *GetProofResponse) GetBalance() []byte {
+	if m != nil {
+		return m.Balance
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/client.ClientService/FetchAccountState",
+	return nil
+}
+
+func (m *GetProofResponse) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ClientServiceServer).FetchAccountState(ctx, req.(*FetchAccountStateRequest))
+	return 0
+}
+
+func (m *GetProofResponse) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _ClientService_GetFreeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetFreeTokenRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *GetProofResponse) GetStorageHash() []byte {
+	if m != nil {
+		return m.StorageHash
 	}
-	if interceptor == nil {
-		return srv.(ClientServiceServer).GetFreeToken(ctx, in)
+	return nil
+}
+
+func (m *GetProofResponse) GetAccountProof() [][]byte {
+	if m != nil {
+		return m.AccountProof
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/client.ClientService/GetFreeToken",
+	return nil
+}
+
+func (m *GetProofResponse) GetStorageProof() []*StorageProof {
+	if m != nil {
+		return m.StorageProof
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ClientServiceServer).GetFreeToken(ctx, req.(*GetFreeTokenRequest))
+	return nil
+}
+
+// SendTransactionRequest is the request to add a signed transaction to the transaction pool.
+type SendTransactionRequest struct {
+	// The RLP encoded signed transaction
+	Transaction          []byte   `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendTransactionRequest) Reset()         { *m = SendTransactionRequest{} }
+func (m *SendTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*SendTransactionRequest) ProtoMessage()    {}
+func (*SendTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{9}
+}
+
+func (m *SendTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendTransactionRequest.Unmarshal(m, b)
+}
+func (m *SendTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendTransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *SendTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendTransactionRequest.Merge(m, src)
+}
+func (m *SendTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_SendTransactionRequest.Size(m)
+}
+func (m *SendTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendTransactionRequest proto.InternalMessageInfo
+
+func (m *SendTransactionRequest) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _ClientService_GetStakingContractInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StakingContractInfoRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+// SendTransactionResponse is the response of SendTransactionRequest.
+type SendTransactionResponse struct {
+	// The transaction Id
+	TxId []byte `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	// The reason the transaction was rejected, TX_OK if it was accepted
+	ErrorCode TransactionErrorCode `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3,enum=client.TransactionErrorCode" json:"error_code,omitempty"`
+	// The error message of a rejected transaction
+	ErrorMessage         string   `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendTransactionResponse) Reset()         { *m = SendTransactionResponse{} }
+func (m *SendTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*SendTransactionResponse) ProtoMessage()    {}
+func (*SendTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{10}
+}
+
+func (m *SendTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendTransactionResponse.Unmarshal(m, b)
+}
+func (m *SendTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendTransactionResponse.Marshal(b, m, deterministic)
+}
+func (m *SendTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendTransactionResponse.Merge(m, src)
+}
+func (m *SendTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_SendTransactionResponse.Size(m)
+}
+func (m *SendTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendTransactionResponse proto.InternalMessageInfo
+
+func (m *SendTransactionResponse) GetTxId() []byte {
+	if m != nil {
+		return m.TxId
 	}
-	if interceptor == nil {
-		return srv.(ClientServiceServer).GetStakingContractInfo(ctx, in)
+	return nil
+}
+
+func (m *SendTransactionResponse) GetErrorCode() TransactionErrorCode {
+	if m != nil {
+		return m.ErrorCode
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/client.ClientService/GetStakingContractInfo",
+	return TransactionErrorCode_TX_OK
+}
+
+func (m *SendTransactionResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ClientServiceServer).GetStakingContractInfo(ctx, req.(*StakingContractInfoRequest))
+	return ""
+}
+
+// GetTransactionRequest is the request to get a transaction and its status.
+type GetTransactionRequest struct {
+	// The transaction Id
+	TxId                 []byte   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()    {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{11}
+}
+
+func (m *GetTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionRequest.Merge(m, src)
+}
+func (m *GetTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionRequest.Size(m)
+}
+func (m *GetTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionRequest proto.InternalMessageInfo
+
+func (m *GetTransactionRequest) GetTxId() []byte {
+	if m != nil {
+		return m.TxId
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _ClientService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "client.ClientService",
-	HandlerType: (*ClientServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
+// GetTransactionResponse is the response of GetTransactionRequest.
+type GetTransactionResponse struct {
+	// The status of the transaction
+	Status TransactionStatus `protobuf:"varint,1,opt,name=status,proto3,enum=client.TransactionStatus" json:"status,omitempty"`
+	// The RLP encoded transaction, empty if the status is unknown
+	Transaction []byte `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// The hash of the block including the transaction
+	BlockHash []byte `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// The number of the block including the transaction
+	BlockNumber uint64 `protobuf:"varint,4,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// The index of the transaction in the block
+	Index                uint64   `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionResponse) Reset()         { *m = GetTransactionResponse{} }
+func (m *GetTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionResponse) ProtoMessage()    {}
+func (*GetTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{12}
+}
+
+func (m *GetTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionResponse.Unmarshal(m, b)
+}
+func (m *GetTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionResponse.Merge(m, src)
+}
+func (m *GetTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionResponse.Size(m)
+}
+func (m *GetTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionResponse proto.InternalMessageInfo
+
+func (m *GetTransactionResponse) GetStatus() TransactionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return TransactionStatus_TX_STATUS_UNKNOWN
+}
+
+func (m *GetTransactionResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+func (m *GetTransactionResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *GetTransactionResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *GetTransactionResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+// GetTransactionReceiptRequest is the request to get the receipt of a transaction.
+type GetTransactionReceiptRequest struct {
+	// The transaction Id
+	TxId                 []byte   `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionReceiptRequest) Reset()         { *m = GetTransactionReceiptRequest{} }
+func (m *GetTransactionReceiptRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionReceiptRequest) ProtoMessage()    {}
+func (*GetTransactionReceiptRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{13}
+}
+
+func (m *GetTransactionReceiptRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionReceiptRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionReceiptRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionReceiptRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionReceiptRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionReceiptRequest.Merge(m, src)
+}
+func (m *GetTransactionReceiptRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionReceiptRequest.Size(m)
+}
+func (m *GetTransactionReceiptRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionReceiptRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionReceiptRequest proto.InternalMessageInfo
+
+func (m *GetTransactionReceiptRequest) GetTxId() []byte {
+	if m != nil {
+		return m.TxId
+	}
+	return nil
+}
+
+// Log is a log emitted by a contract during the execution of a transaction.
+type Log struct {
+	// The address of the contract
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// The topics of the log
+	Topics [][]byte `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	// The data of the log
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// The index of the log in the block
+	Index                uint64   `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Log) Reset()         { *m = Log{} }
+func (m *Log) String() string { return proto.CompactTextString(m) }
+func (*Log) ProtoMessage()    {}
+func (*Log) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{14}
+}
+
+func (m *Log) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Log.Unmarshal(m, b)
+}
+func (m *Log) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Log.Marshal(b, m, deterministic)
+}
+func (m *Log) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Log.Merge(m, src)
+}
+func (m *Log) XXX_Size() int {
+	return xxx_messageInfo_Log.Size(m)
+}
+func (m *Log) XXX_DiscardUnknown() {
+	xxx_messageInfo_Log.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Log proto.InternalMessageInfo
+
+func (m *Log) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Log) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Log) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Log) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+// GetTransactionReceiptResponse is the response of GetTransactionReceiptRequest.
+type GetTransactionReceiptResponse struct {
+	// The status of the transaction. Only included and failed transactions have a receipt.
+	Status TransactionStatus `protobuf:"varint,1,opt,name=status,proto3,enum=client.TransactionStatus" json:"status,omitempty"`
+	// The hash of the block including the transaction
+	BlockHash []byte `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// The number of the block including the transaction
+	BlockNumber uint64 `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// The index of the transaction in the block
+	Index uint64 `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	// The gas used by the transaction
+	GasUsed uint64 `protobuf:"varint,5,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// The gas used by the block up to and including the transaction
+	CumulativeGasUsed uint64 `protobuf:"varint,6,opt,name=cumulative_gas_used,json=cumulativeGasUsed,proto3" json:"cumulative_gas_used,omitempty"`
+	// The address of the created contract, if any
+	ContractAddress []byte `protobuf:"bytes,7,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	// The logs emitted by the transaction
+	Logs                 []*Log   `protobuf:"bytes,8,rep,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionReceiptResponse) Reset()         { *m = GetTransactionReceiptResponse{} }
+func (m *GetTransactionReceiptResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionReceiptResponse) ProtoMessage()    {}
+func (*GetTransactionReceiptResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{15}
+}
+
+func (m *GetTransactionReceiptResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionReceiptResponse.Unmarshal(m, b)
+}
+func (m *GetTransactionReceiptResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionReceiptResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTransactionReceiptResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionReceiptResponse.Merge(m, src)
+}
+func (m *GetTransactionReceiptResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionReceiptResponse.Size(m)
+}
+func (m *GetTransactionReceiptResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionReceiptResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionReceiptResponse proto.InternalMessageInfo
+
+func (m *GetTransactionReceiptResponse) GetStatus() TransactionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return TransactionStatus_TX_STATUS_UNKNOWN
+}
+
+func (m *GetTransactionReceiptResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *GetTransactionReceiptResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *GetTransactionReceiptResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *GetTransactionReceiptResponse) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *GetTransactionReceiptResponse) GetCumulativeGasUsed() uint64 {
+	if m != nil {
+		return m.CumulativeGasUsed
+	}
+	return 0
+}
+
+func (m *GetTransactionReceiptResponse) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *GetTransactionReceiptResponse) GetLogs() []*Log {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+// CallRequest is the request to execute a message call without creating a transaction.
+type CallRequest struct {
+	// The sender address
+	From []byte `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	// The recipient address. Empty means a contract creation.
+	To []byte `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// The gas of the call. Zero means the maximum gas allowed.
+	Gas uint64 `protobuf:"varint,3,opt,name=gas,proto3" json:"gas,omitempty"`
+	// The gas price of the call (big.Int)
+	GasPrice []byte `protobuf:"bytes,4,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	// The value sent with the call (big.Int)
+	Value []byte `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// The input data of the call
+	Data []byte `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	// The block number of the state. Zero means the current block.
+	BlockNumber          uint64   `protobuf:"varint,7,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CallRequest) Reset()         { *m = CallRequest{} }
+func (m *CallRequest) String() string { return proto.CompactTextString(m) }
+func (*CallRequest) ProtoMessage()    {}
+func (*CallRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{16}
+}
+
+func (m *CallRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallRequest.Unmarshal(m, b)
+}
+func (m *CallRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallRequest.Marshal(b, m, deterministic)
+}
+func (m *CallRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallRequest.Merge(m, src)
+}
+func (m *CallRequest) XXX_Size() int {
+	return xxx_messageInfo_CallRequest.Size(m)
+}
+func (m *CallRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CallRequest proto.InternalMessageInfo
+
+func (m *CallRequest) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *CallRequest) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *CallRequest) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *CallRequest) GetGasPrice() []byte {
+	if m != nil {
+		return m.GasPrice
+	}
+	return nil
+}
+
+func (m *CallRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CallRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CallRequest) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// CallResponse is the response of Call.
+type CallResponse struct {
+	// The return data of the call
+	Result []byte `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// The gas used by the call
+	GasUsed uint64 `protobuf:"varint,2,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// Whether the execution of the call failed
+	Failed               bool     `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CallResponse) Reset()         { *m = CallResponse{} }
+func (m *CallResponse) String() string { return proto.CompactTextString(m) }
+func (*CallResponse) ProtoMessage()    {}
+func (*CallResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{17}
+}
+
+func (m *CallResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallResponse.Unmarshal(m, b)
+}
+func (m *CallResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallResponse.Marshal(b, m, deterministic)
+}
+func (m *CallResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallResponse.Merge(m, src)
+}
+func (m *CallResponse) XXX_Size() int {
+	return xxx_messageInfo_CallResponse.Size(m)
+}
+func (m *CallResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CallResponse proto.InternalMessageInfo
+
+func (m *CallResponse) GetResult() []byte {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *CallResponse) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *CallResponse) GetFailed() bool {
+	if m != nil {
+		return m.Failed
+	}
+	return false
+}
+
+// EstimateGasResponse is the response of EstimateGas.
+type EstimateGasResponse struct {
+	// The lowest gas limit with which the call succeeds
+	Gas                  uint64   `protobuf:"varint,1,opt,name=gas,proto3" json:"gas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EstimateGasResponse) Reset()         { *m = EstimateGasResponse{} }
+func (m *EstimateGasResponse) String() string { return proto.CompactTextString(m) }
+func (*EstimateGasResponse) ProtoMessage()    {}
+func (*EstimateGasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{18}
+}
+
+func (m *EstimateGasResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateGasResponse.Unmarshal(m, b)
+}
+func (m *EstimateGasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateGasResponse.Marshal(b, m, deterministic)
+}
+func (m *EstimateGasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateGasResponse.Merge(m, src)
+}
+func (m *EstimateGasResponse) XXX_Size() int {
+	return xxx_messageInfo_EstimateGasResponse.Size(m)
+}
+func (m *EstimateGasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateGasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EstimateGasResponse proto.InternalMessageInfo
+
+func (m *EstimateGasResponse) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+// UnbondingInfoRequest is the request to get the withdrawn stakes of an account
+// which are still locked.
+type UnbondingInfoRequest struct {
+	// The account address
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnbondingInfoRequest) Reset()         { *m = UnbondingInfoRequest{} }
+func (m *UnbondingInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*UnbondingInfoRequest) ProtoMessage()    {}
+func (*UnbondingInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{19}
+}
+
+func (m *UnbondingInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnbondingInfoRequest.Unmarshal(m, b)
+}
+func (m *UnbondingInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnbondingInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *UnbondingInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnbondingInfoRequest.Merge(m, src)
+}
+func (m *UnbondingInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_UnbondingInfoRequest.Size(m)
+}
+func (m *UnbondingInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnbondingInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnbondingInfoRequest proto.InternalMessageInfo
+
+func (m *UnbondingInfoRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+// Unbonding is a withdrawn stake locked until the end of the unbonding period.
+type Unbonding struct {
+	// The address of the validator the stake was withdrawn from
+	Validator []byte `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`
+	// The amount withdrawn (big.Int)
+	Amount []byte `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The number of the block the stake was withdrawn in
+	WithdrawBlock uint64 `protobuf:"varint,3,opt,name=withdraw_block,json=withdrawBlock,proto3" json:"withdraw_block,omitempty"`
+	// The number of the block the stake is released at
+	ReleaseBlock uint64 `protobuf:"varint,4,opt,name=release_block,json=releaseBlock,proto3" json:"release_block,omitempty"`
+	// The number of blocks left until the release
+	RemainingBlocks      uint64   `protobuf:"varint,5,opt,name=remaining_blocks,json=remainingBlocks,proto3" json:"remaining_blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Unbonding) Reset()         { *m = Unbonding{} }
+func (m *Unbonding) String() string { return proto.CompactTextString(m) }
+func (*Unbonding) ProtoMessage()    {}
+func (*Unbonding) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{20}
+}
+
+func (m *Unbonding) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Unbonding.Unmarshal(m, b)
+}
+func (m *Unbonding) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Unbonding.Marshal(b, m, deterministic)
+}
+func (m *Unbonding) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Unbonding.Merge(m, src)
+}
+func (m *Unbonding) XXX_Size() int {
+	return xxx_messageInfo_Unbonding.Size(m)
+}
+func (m *Unbonding) XXX_DiscardUnknown() {
+	xxx_messageInfo_Unbonding.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Unbonding proto.InternalMessageInfo
+
+func (m *Unbonding) GetValidator() []byte {
+	if m != nil {
+		return m.Validator
+	}
+	return nil
+}
+
+func (m *Unbonding) GetAmount() []byte {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *Unbonding) GetWithdrawBlock() uint64 {
+	if m != nil {
+		return m.WithdrawBlock
+	}
+	return 0
+}
+
+func (m *Unbonding) GetReleaseBlock() uint64 {
+	if m != nil {
+		return m.ReleaseBlock
+	}
+	return 0
+}
+
+func (m *Unbonding) GetRemainingBlocks() uint64 {
+	if m != nil {
+		return m.RemainingBlocks
+	}
+	return 0
+}
+
+// UnbondingInfoResponse is the response of GetUnbondingInfo.
+type UnbondingInfoResponse struct {
+	// The withdrawn stakes of the account, oldest first
+	Unbondings []*Unbonding `protobuf:"bytes,1,rep,name=unbondings,proto3" json:"unbondings,omitempty"`
+	// The total amount locked (big.Int)
+	Locked []byte `protobuf:"bytes,2,opt,name=locked,proto3" json:"locked,omitempty"`
+	// The number of the current block
+	BlockNumber          uint64   `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnbondingInfoResponse) Reset()         { *m = UnbondingInfoResponse{} }
+func (m *UnbondingInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*UnbondingInfoResponse) ProtoMessage()    {}
+func (*UnbondingInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_014de31d7ac8c57c, []int{21}
+}
+
+func (m *UnbondingInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnbondingInfoResponse.Unmarshal(m, b)
+}
+func (m *UnbondingInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnbondingInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *UnbondingInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnbondingInfoResponse.Merge(m, src)
+}
+func (m *UnbondingInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_UnbondingInfoResponse.Size(m)
+}
+func (m *UnbondingInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnbondingInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnbondingInfoResponse proto.InternalMessageInfo
+
+func (m *UnbondingInfoResponse) GetUnbondings() []*Unbonding {
+	if m != nil {
+		return m.Unbondings
+	}
+	return nil
+}
+
+func (m *UnbondingInfoResponse) GetLocked() []byte {
+	if m != nil {
+		return m.Locked
+	}
+	return nil
+}
+
+func (m *UnbondingInfoResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("client.TransactionErrorCode", TransactionErrorCode_name, TransactionErrorCode_value)
+	proto.RegisterEnum("client.TransactionStatus", TransactionStatus_name, TransactionStatus_value)
+	proto.RegisterType((*FetchAccountStateRequest)(nil), "client.FetchAccountStateRequest")
+	proto.RegisterType((*FetchAccountStateResponse)(nil), "client.FetchAccountStateResponse")
+	proto.RegisterType((*GetFreeTokenRequest)(nil), "client.GetFreeTokenRequest")
+	proto.RegisterType((*GetFreeTokenResponse)(nil), "client.GetFreeTokenResponse")
+	proto.RegisterType((*StakingContractInfoRequest)(nil), "client.StakingContractInfoRequest")
+	proto.RegisterType((*StakingContractInfoResponse)(nil), "client.StakingContractInfoResponse")
+	proto.RegisterType((*GetProofRequest)(nil), "client.GetProofRequest")
+	proto.RegisterType((*StorageProof)(nil), "client.StorageProof")
+	proto.RegisterType((*GetProofResponse)(nil), "client.GetProofResponse")
+	proto.RegisterType((*SendTransactionRequest)(nil), "client.SendTransactionRequest")
+	proto.RegisterType((*SendTransactionResponse)(nil), "client.SendTransactionResponse")
+	proto.RegisterType((*GetTransactionRequest)(nil), "client.GetTransactionRequest")
+	proto.RegisterType((*GetTransactionResponse)(nil), "client.GetTransactionResponse")
+	proto.RegisterType((*GetTransactionReceiptRequest)(nil), "client.GetTransactionReceiptRequest")
+	proto.RegisterType((*Log)(nil), "client.Log")
+	proto.RegisterType((*GetTransactionReceiptResponse)(nil), "client.GetTransactionReceiptResponse")
+	proto.RegisterType((*CallRequest)(nil), "client.CallRequest")
+	proto.RegisterType((*CallResponse)(nil), "client.CallResponse")
+	proto.RegisterType((*EstimateGasResponse)(nil), "client.EstimateGasResponse")
+	proto.RegisterType((*UnbondingInfoRequest)(nil), "client.UnbondingInfoRequest")
+	proto.RegisterType((*Unbonding)(nil), "client.Unbonding")
+	proto.RegisterType((*UnbondingInfoResponse)(nil), "client.UnbondingInfoResponse")
+}
+
+func init() { proto.RegisterFile("client.proto", fileDescriptor_014de31d7ac8c57c) }
+
+var fileDescriptor_014de31d7ac8c57c = []byte{
+	// 1439 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x5f, 0x53, 0xdb, 0x46,
+	0x10, 0x8f, 0xff, 0x60, 0xf0, 0xda, 0x80, 0x38, 0x0c, 0x31, 0x0e, 0x24, 0x44, 0x69, 0xa6, 0x24,
+	0xd3, 0x49, 0x5b, 0xd2, 0xe9, 0x4c, 0xdb, 0x87, 0x8e, 0x6a, 0x0b, 0xa2, 0xc1, 0x91, 0x89, 0x24,
+	0x13, 0x9a, 0x17, 0xcd, 0x21, 0x1d, 0x46, 0x83, 0x91, 0x88, 0x74, 0x26, 0xc9, 0x07, 0xc8, 0x6b,
+	0xa7, 0x5f, 0xa4, 0xef, 0x9d, 0xbe, 0xf5, 0xa1, 0xaf, 0xfd, 0x40, 0x7d, 0xea, 0xdc, 0xe9, 0x24,
+	0xcb, 0x58, 0x86, 0xb4, 0x6f, 0xb7, 0xbf, 0xdb, 0xdd, 0xdb, 0xfd, 0xdd, 0x6a, 0x6f, 0x05, 0x75,
+	0x67, 0xe8, 0x11, 0x9f, 0x3e, 0xbb, 0x0c, 0x03, 0x1a, 0xa0, 0x4a, 0x2c, 0xc9, 0xdf, 0x40, 0x73,
+	0x8f, 0x50, 0xe7, 0x4c, 0x71, 0x9c, 0x60, 0xe4, 0x53, 0x93, 0x62, 0x4a, 0x0c, 0xf2, 0x76, 0x44,
+	0x22, 0x8a, 0x9a, 0x30, 0x8f, 0x5d, 0x37, 0x24, 0x51, 0xd4, 0x2c, 0x6c, 0x17, 0x76, 0xea, 0x46,
+	0x22, 0xca, 0x07, 0xb0, 0x91, 0x63, 0x15, 0x5d, 0x06, 0x7e, 0x44, 0x98, 0xd9, 0x09, 0x1e, 0x62,
+	0xdf, 0x21, 0x89, 0x99, 0x10, 0x51, 0x03, 0xe6, 0xfc, 0x80, 0xe1, 0xc5, 0xed, 0xc2, 0x4e, 0xd9,
+	0x88, 0x05, 0xf9, 0x4b, 0x58, 0xdd, 0x27, 0x74, 0x2f, 0x24, 0xc4, 0x0a, 0xce, 0x89, 0x7f, 0xfb,
+	0xe9, 0x4f, 0xa1, 0x31, 0x69, 0x20, 0x0e, 0x46, 0x50, 0xa6, 0xef, 0x35, 0x57, 0xa8, 0xf3, 0xb5,
+	0xfc, 0x2d, 0xb4, 0x4c, 0x8a, 0xcf, 0x3d, 0x7f, 0xd0, 0x0e, 0x7c, 0x1a, 0x62, 0x87, 0x6a, 0xfe,
+	0x69, 0x70, 0xfb, 0x19, 0xbf, 0x14, 0xe0, 0x5e, 0xae, 0xa1, 0x38, 0xeb, 0x09, 0x48, 0x8e, 0xc0,
+	0xed, 0xac, 0x8b, 0xaa, 0xb1, 0x9c, 0xe0, 0x4a, 0x0c, 0x67, 0xf9, 0x28, 0xce, 0xe0, 0xa3, 0x94,
+	0xe1, 0x83, 0xa1, 0x11, 0xc5, 0xe7, 0xa4, 0x59, 0xe6, 0xda, 0xb1, 0x20, 0xbf, 0x85, 0xe5, 0x7d,
+	0x42, 0x0f, 0xc3, 0x20, 0x38, 0xbd, 0x35, 0x7a, 0xf4, 0x10, 0xea, 0x11, 0x0d, 0x42, 0x3c, 0x20,
+	0xf6, 0x39, 0xf9, 0x10, 0x35, 0x8b, 0xdb, 0xa5, 0x9d, 0xba, 0x51, 0x13, 0xd8, 0x01, 0xf9, 0xc0,
+	0x55, 0x4e, 0x86, 0x81, 0x73, 0x6e, 0xfb, 0xa3, 0x8b, 0x13, 0x12, 0x8a, 0x10, 0x6a, 0x1c, 0xd3,
+	0x39, 0x24, 0x77, 0xa1, 0x6e, 0xc6, 0x16, 0xfc, 0x58, 0x24, 0x41, 0xe9, 0x9c, 0x7c, 0x10, 0x67,
+	0xb1, 0x25, 0x0b, 0xf5, 0x0a, 0x0f, 0x47, 0x49, 0x62, 0xb1, 0xc0, 0xd0, 0x4b, 0x66, 0xd0, 0x2c,
+	0xf1, 0x63, 0x63, 0x41, 0xfe, 0xa7, 0x00, 0xd2, 0x38, 0x03, 0x41, 0xe3, 0x3a, 0x54, 0xce, 0x08,
+	0x76, 0x49, 0x28, 0xbc, 0x0a, 0xe9, 0x3f, 0x73, 0x76, 0x0f, 0xaa, 0x4e, 0xe0, 0x12, 0xfb, 0x0c,
+	0x47, 0x67, 0x82, 0xb7, 0x05, 0x06, 0xbc, 0xc0, 0xd1, 0x59, 0x96, 0x0d, 0xbe, 0x3f, 0xb7, 0x5d,
+	0xc8, 0xb0, 0xc1, 0x55, 0x1e, 0xc1, 0x22, 0x8e, 0x6b, 0xd9, 0x8e, 0x43, 0xaf, 0xf0, 0xd0, 0xeb,
+	0x02, 0x8c, 0xf3, 0xff, 0x0e, 0x16, 0x13, 0x3f, 0xb1, 0xd2, 0xfc, 0x76, 0x69, 0xa7, 0xb6, 0xdb,
+	0x78, 0x26, 0xbe, 0xac, 0x2c, 0x59, 0x46, 0x3d, 0xca, 0x48, 0xf2, 0xf7, 0xb0, 0x6e, 0x12, 0xdf,
+	0xb5, 0x42, 0xec, 0x47, 0xd8, 0xa1, 0x5e, 0x90, 0x96, 0xf9, 0x36, 0xd4, 0xe8, 0x18, 0x15, 0x34,
+	0x64, 0x21, 0xf9, 0xd7, 0x02, 0xdc, 0x9d, 0x32, 0x16, 0xfc, 0xad, 0xc2, 0x1c, 0x7d, 0x6f, 0x7b,
+	0x13, 0x35, 0x8f, 0x7e, 0x00, 0x20, 0x61, 0x18, 0x84, 0x36, 0x63, 0x80, 0xf3, 0xb7, 0xb4, 0xbb,
+	0x99, 0x04, 0x99, 0xf1, 0xa2, 0x32, 0xa5, 0x76, 0xe0, 0x12, 0xa3, 0x4a, 0x92, 0x25, 0x63, 0x22,
+	0x36, 0xbe, 0x20, 0x51, 0x84, 0x07, 0x31, 0xcf, 0x55, 0xa3, 0xce, 0xc1, 0x97, 0x31, 0x26, 0x7f,
+	0x01, 0x6b, 0xfb, 0x84, 0xe6, 0x64, 0x93, 0x17, 0x8f, 0xfc, 0x57, 0x01, 0xd6, 0xaf, 0xab, 0x8b,
+	0xf8, 0xbf, 0x86, 0x4a, 0x44, 0x31, 0x1d, 0xc5, 0x15, 0xbc, 0xb4, 0xbb, 0x91, 0x13, 0xa6, 0xc9,
+	0x15, 0x0c, 0xa1, 0x78, 0x9d, 0xb0, 0xe2, 0x14, 0x61, 0x68, 0x0b, 0x20, 0x2e, 0x6d, 0x7e, 0xdb,
+	0x25, 0xae, 0x50, 0xe5, 0x48, 0x52, 0x0e, 0x13, 0x95, 0x5f, 0x9e, 0xaa, 0x7c, 0x56, 0x64, 0x9e,
+	0xef, 0x92, 0xf7, 0xbc, 0x54, 0xca, 0x46, 0x2c, 0xc8, 0xcf, 0x61, 0xf3, 0x7a, 0x1a, 0x0e, 0xf1,
+	0x2e, 0xe9, 0x8d, 0xc9, 0x63, 0x28, 0x75, 0x83, 0xc1, 0x0d, 0xdf, 0xea, 0x3a, 0x54, 0x68, 0x70,
+	0xe9, 0x39, 0xc9, 0x57, 0x2a, 0x24, 0xd6, 0xcd, 0x5c, 0x4c, 0xb1, 0x88, 0x9f, 0xaf, 0xc7, 0x71,
+	0x95, 0xb3, 0x71, 0xfd, 0x59, 0x84, 0xad, 0x19, 0x81, 0xfd, 0x7f, 0x9a, 0x27, 0x49, 0x2c, 0xde,
+	0x46, 0x62, 0xe9, 0x06, 0x12, 0xb3, 0xc1, 0xa2, 0x0d, 0x58, 0x18, 0xe0, 0xc8, 0x1e, 0x45, 0xc4,
+	0x15, 0xec, 0xce, 0x0f, 0x70, 0xd4, 0x8f, 0x88, 0x8b, 0x9e, 0xc1, 0xaa, 0x33, 0xba, 0x18, 0x0d,
+	0x31, 0xf5, 0xae, 0x88, 0x9d, 0x6a, 0x55, 0xb8, 0xd6, 0xca, 0x78, 0x6b, 0x5f, 0xe8, 0xe7, 0xf5,
+	0xe0, 0x79, 0x1e, 0xe8, 0x54, 0x0f, 0x7e, 0x00, 0xe5, 0x61, 0x30, 0x88, 0x9a, 0x0b, 0xfc, 0x8b,
+	0xad, 0x25, 0xe9, 0x77, 0x83, 0x81, 0xc1, 0x37, 0xe4, 0xdf, 0x0a, 0x50, 0x6b, 0xe3, 0xe1, 0x30,
+	0xb9, 0x4b, 0x04, 0xe5, 0xd3, 0x30, 0xb8, 0x48, 0xae, 0x92, 0xad, 0xd1, 0x12, 0x14, 0x69, 0x20,
+	0xa8, 0x28, 0xd2, 0x80, 0xf5, 0xc3, 0x01, 0x8e, 0x44, 0xea, 0x6c, 0xc9, 0xda, 0x10, 0x0b, 0xfb,
+	0x32, 0xf4, 0x9c, 0xa4, 0x7d, 0xb3, 0x6c, 0x0f, 0x99, 0x3c, 0x6e, 0x96, 0x73, 0xd9, 0x66, 0x99,
+	0x5c, 0x73, 0x25, 0x73, 0xcd, 0xd7, 0xc9, 0x9d, 0x9f, 0xee, 0xcd, 0x3f, 0x43, 0x3d, 0x0e, 0x77,
+	0xdc, 0x48, 0x43, 0x12, 0x8d, 0x86, 0x34, 0x69, 0xa4, 0xb1, 0x34, 0x41, 0x77, 0x71, 0x92, 0xee,
+	0x75, 0xa8, 0x9c, 0x62, 0x6f, 0x48, 0x5c, 0x9e, 0xc1, 0x82, 0x21, 0x24, 0xf9, 0x73, 0x58, 0x55,
+	0x23, 0xea, 0x5d, 0x60, 0xca, 0x98, 0x4e, 0x4f, 0x10, 0xd9, 0x16, 0xd2, 0x6c, 0xe5, 0xaf, 0xa0,
+	0xd1, 0xf7, 0x4f, 0x02, 0xdf, 0xf5, 0xfc, 0xc1, 0xa7, 0xbd, 0xaa, 0xbf, 0x17, 0xa0, 0x9a, 0x9a,
+	0xa0, 0x4d, 0xa8, 0x5e, 0xe1, 0xa1, 0xe7, 0x62, 0x1a, 0x24, 0xfd, 0x7f, 0x0c, 0xb0, 0xf0, 0xf0,
+	0x05, 0x6b, 0xbe, 0x82, 0x71, 0x21, 0xa1, 0xc7, 0xb0, 0xf4, 0xce, 0xa3, 0x67, 0x6e, 0x88, 0xdf,
+	0xd9, 0x9c, 0x11, 0x71, 0x01, 0x8b, 0x09, 0xfa, 0x13, 0x03, 0x59, 0x1f, 0x0b, 0xc9, 0x90, 0xe0,
+	0x88, 0x08, 0xad, 0xb8, 0x0a, 0xeb, 0x02, 0x8c, 0x95, 0x9e, 0x80, 0x14, 0x92, 0x0b, 0xec, 0xf9,
+	0x9e, 0x3f, 0x88, 0xd5, 0x22, 0x51, 0x94, 0xcb, 0x29, 0xce, 0x35, 0x23, 0xf9, 0x63, 0x01, 0xd6,
+	0xae, 0x65, 0x9b, 0x7e, 0x5c, 0x30, 0x4a, 0x36, 0x58, 0xc6, 0xac, 0xc2, 0x56, 0x92, 0x0a, 0x4b,
+	0x4d, 0x8c, 0x8c, 0x12, 0xcb, 0x8d, 0x79, 0x15, 0x77, 0x52, 0x37, 0x84, 0xf4, 0x09, 0x5f, 0xd5,
+	0xd3, 0xbf, 0x8b, 0xd0, 0xc8, 0xeb, 0xe1, 0xa8, 0x0a, 0x73, 0xd6, 0xb1, 0xdd, 0x3b, 0x90, 0xee,
+	0xa0, 0x06, 0x48, 0xd6, 0xb1, 0xdd, 0xd7, 0x0f, 0xf4, 0xde, 0x6b, 0xdd, 0x56, 0x0d, 0xa3, 0x67,
+	0x48, 0x05, 0x74, 0x17, 0x56, 0xad, 0x63, 0x5b, 0xd3, 0x8f, 0x94, 0xae, 0xd6, 0xb1, 0x55, 0xbd,
+	0xdd, 0xeb, 0x68, 0xfa, 0xbe, 0x54, 0x14, 0xea, 0xc9, 0x86, 0xf9, 0x42, 0x31, 0x3a, 0x52, 0x09,
+	0xad, 0xc1, 0x4a, 0x16, 0x55, 0xf5, 0x8e, 0x6a, 0x48, 0x65, 0xa1, 0xac, 0xf7, 0xf4, 0xb6, 0x6a,
+	0x5b, 0xbd, 0x9e, 0xdd, 0xed, 0xbd, 0x96, 0xe6, 0x10, 0x82, 0x25, 0x7e, 0x62, 0x47, 0x35, 0x0e,
+	0x0d, 0xad, 0xad, 0x76, 0xa4, 0x0a, 0x6a, 0xc1, 0xba, 0x75, 0x6c, 0x1b, 0xea, 0x61, 0x57, 0x69,
+	0xab, 0x13, 0x7b, 0xf3, 0x68, 0x03, 0xd6, 0xb8, 0x73, 0xb3, 0xbf, 0xb7, 0xa7, 0xb5, 0x35, 0x55,
+	0xb7, 0xec, 0xbd, 0xbe, 0xde, 0x31, 0xa5, 0x85, 0x34, 0x1a, 0xcb, 0xd0, 0x74, 0x53, 0x6b, 0xdb,
+	0xfb, 0x8a, 0x29, 0x55, 0x91, 0x04, 0x75, 0xeb, 0x98, 0xad, 0xed, 0xae, 0xf6, 0x52, 0xb3, 0x24,
+	0x10, 0xf1, 0xe9, 0xea, 0xbe, 0x62, 0x69, 0x47, 0xaa, 0x7d, 0xa4, 0x74, 0xfb, 0xaa, 0x54, 0x13,
+	0x70, 0xef, 0x48, 0x35, 0x4c, 0xed, 0x8d, 0xda, 0xb1, 0x3b, 0x8a, 0xa5, 0x48, 0x75, 0xd4, 0x84,
+	0x86, 0x75, 0x6c, 0xc7, 0x84, 0x58, 0x86, 0xa2, 0x9b, 0x4a, 0xdb, 0xd2, 0x7a, 0xba, 0xb4, 0xf8,
+	0xf4, 0x63, 0x01, 0x56, 0xa6, 0xda, 0xa0, 0x70, 0x63, 0x5a, 0x8a, 0xd5, 0x37, 0x13, 0x26, 0x53,
+	0x66, 0x05, 0xfc, 0xaa, 0xaf, 0xf6, 0xd5, 0x8e, 0x54, 0x98, 0x54, 0x3e, 0x54, 0x75, 0xc1, 0xeb,
+	0x3a, 0xa0, 0x31, 0xac, 0xe9, 0xed, 0x6e, 0xbf, 0xa3, 0x32, 0x66, 0x27, 0x9c, 0xec, 0x29, 0x5a,
+	0x57, 0xed, 0x48, 0xe5, 0xdd, 0x3f, 0x2a, 0xb0, 0xd8, 0xe6, 0x45, 0x63, 0x92, 0xf0, 0x8a, 0x35,
+	0x8c, 0x37, 0xb0, 0x32, 0x35, 0x65, 0xa3, 0xed, 0xa4, 0xb2, 0x66, 0x8d, 0xed, 0xad, 0x87, 0x37,
+	0x68, 0xc4, 0x25, 0x2b, 0xdf, 0x41, 0x07, 0x50, 0xcf, 0xce, 0xd0, 0xe8, 0x5e, 0x62, 0x94, 0x33,
+	0x8a, 0xb7, 0x36, 0xf3, 0x37, 0x53, 0x67, 0x0e, 0x7f, 0xdf, 0x73, 0xc6, 0x65, 0x24, 0x8f, 0x67,
+	0xa3, 0x59, 0x43, 0x78, 0xeb, 0xd1, 0x8d, 0x3a, 0xe9, 0x21, 0x3f, 0xc2, 0x42, 0x32, 0x3e, 0xa2,
+	0xbb, 0x99, 0x80, 0xb2, 0x23, 0x71, 0xab, 0x39, 0xbd, 0x91, 0x3a, 0xb0, 0x60, 0xf9, 0xda, 0x18,
+	0x85, 0xee, 0xa7, 0x47, 0xe7, 0x0e, 0x67, 0xad, 0x07, 0x33, 0xf7, 0x53, 0xaf, 0xaf, 0x60, 0x69,
+	0xf2, 0xed, 0x45, 0x5b, 0x99, 0x18, 0x72, 0x7c, 0xde, 0x9f, 0xb5, 0x9d, 0xba, 0x3c, 0x85, 0xb5,
+	0xdc, 0xe7, 0x1c, 0x7d, 0x36, 0xcb, 0x34, 0x3b, 0x86, 0xb4, 0x1e, 0xdf, 0xa2, 0x95, 0x9e, 0xf3,
+	0x1c, 0xca, 0xec, 0x0d, 0x41, 0xab, 0x89, 0x41, 0xe6, 0x01, 0x6c, 0x35, 0x26, 0xc1, 0xd4, 0x48,
+	0x81, 0x5a, 0xe6, 0x75, 0xc8, 0xb7, 0x4d, 0x8b, 0x29, 0xe7, 0x1d, 0xe1, 0x94, 0xb1, 0x1f, 0x81,
+	0x89, 0x66, 0x8a, 0x36, 0xa7, 0x1a, 0x66, 0xb6, 0x44, 0xb6, 0x66, 0xec, 0x26, 0x2e, 0x4f, 0x2a,
+	0xfc, 0xaf, 0xf6, 0xf9, 0xbf, 0x03, 0x00, 0x74, 0x5a, 0xbf, 0x8b, 0xe5, 0x0e, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ClientServiceClient is the client API for ClientService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ClientServiceClient interface {
+	FetchAccountState(ctx context.Context, in *FetchAccountStateRequest, opts ...grpc.CallOption) (*FetchAccountStateResponse, error)
+	GetFreeToken(ctx context.Context, in *GetFreeTokenRequest, opts ...grpc.CallOption) (*GetFreeTokenResponse, error)
+	GetStakingContractInfo(ctx context.Context, in *StakingContractInfoRequest, opts ...grpc.CallOption) (*StakingContractInfoResponse, error)
+	GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error)
+	SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
+	GetTransactionReceipt(ctx context.Context, in *GetTransactionReceiptRequest, opts ...grpc.CallOption) (*GetTransactionReceiptResponse, error)
+	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error)
+	EstimateGas(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*EstimateGasResponse, error)
+	GetUnbondingInfo(ctx context.Context, in *UnbondingInfoRequest, opts ...grpc.CallOption) (*UnbondingInfoResponse, error)
+}
+
+type clientServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewClientServiceClient(cc *grpc.ClientConn) ClientServiceClient {
+	return &clientServiceClient{cc}
+}
+
+func (c *clientServiceClient) FetchAccountState(ctx context.Context, in *FetchAccountStateRequest, opts ...grpc.CallOption) (*FetchAccountStateResponse, error) {
+	out := new(FetchAccountStateResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/FetchAccountState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetFreeToken(ctx context.Context, in *GetFreeTokenRequest, opts ...grpc.CallOption) (*GetFreeTokenResponse, error) {
+	out := new(GetFreeTokenResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetFreeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetStakingContractInfo(ctx context.Context, in *StakingContractInfoRequest, opts ...grpc.CallOption) (*StakingContractInfoResponse, error) {
+	out := new(StakingContractInfoResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetStakingContractInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetProof(ctx context.Context, in *GetProofRequest, opts ...grpc.CallOption) (*GetProofResponse, error) {
+	out := new(GetProofResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error) {
+	out := new(SendTransactionResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/SendTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetTransactionReceipt(ctx context.Context, in *GetTransactionReceiptRequest, opts ...grpc.CallOption) (*GetTransactionReceiptResponse, error) {
+	out := new(GetTransactionReceiptResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetTransactionReceipt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error) {
+	out := new(CallResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/Call", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) EstimateGas(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*EstimateGasResponse, error) {
+	out := new(EstimateGasResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/EstimateGas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientServiceClient) GetUnbondingInfo(ctx context.Context, in *UnbondingInfoRequest, opts ...grpc.CallOption) (*UnbondingInfoResponse, error) {
+	out := new(UnbondingInfoResponse)
+	err := c.cc.Invoke(ctx, "/client.ClientService/GetUnbondingInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClientServiceServer is the server API for ClientService service.
+type ClientServiceServer interface {
+	FetchAccountState(context.Context, *FetchAccountStateRequest) (*FetchAccountStateResponse, error)
+	GetFreeToken(context.Context, *GetFreeTokenRequest) (*GetFreeTokenResponse, error)
+	GetStakingContractInfo(context.Context, *StakingContractInfoRequest) (*StakingContractInfoResponse, error)
+	GetProof(context.Context, *GetProofRequest) (*GetProofResponse, error)
+	SendTransaction(context.Context, *SendTransactionRequest) (*SendTransactionResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
+	GetTransactionReceipt(context.Context, *GetTransactionReceiptRequest) (*GetTransactionReceiptResponse, error)
+	Call(context.Context, *CallRequest) (*CallResponse, error)
+	EstimateGas(context.Context, *CallRequest) (*EstimateGasResponse, error)
+	GetUnbondingInfo(context.Context, *UnbondingInfoRequest) (*UnbondingInfoResponse, error)
+}
+
+func RegisterClientServiceServer(s *grpc.Server, srv ClientServiceServer) {
+	s.RegisterService(&_ClientService_serviceDesc, srv)
+}
+
+func _ClientService_FetchAccountState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchAccountStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).FetchAccountState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/FetchAccountState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).FetchAccountState(ctx, req.(*FetchAccountStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetFreeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFreeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetFreeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetFreeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetFreeToken(ctx, req.(*GetFreeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetStakingContractInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StakingContractInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetStakingContractInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetStakingContractInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetStakingContractInfo(ctx, req.(*StakingContractInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetProof(ctx, req.(*GetProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_SendTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).SendTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/SendTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).SendTransaction(ctx, req.(*SendTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetTransactionReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetTransactionReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetTransactionReceipt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetTransactionReceipt(ctx, req.(*GetTransactionReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).Call(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_EstimateGas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).EstimateGas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/EstimateGas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).EstimateGas(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientService_GetUnbondingInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbondingInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientServiceServer).GetUnbondingInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/client.ClientService/GetUnbondingInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientServiceServer).GetUnbondingInfo(ctx, req.(*UnbondingInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ClientService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "client.ClientService",
+	HandlerType: (*ClientServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
 			MethodName: "FetchAccountState",
 			Handler:    _ClientService_FetchAccountState_Handler,
 		},
@@ -462,6 +1884,34 @@ var _ClientService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStakingContractInfo",
 			Handler:    _ClientService_GetStakingContractInfo_Handler,
 		},
+		{
+			MethodName: "GetProof",
+			Handler:    _ClientService_GetProof_Handler,
+		},
+		{
+			MethodName: "SendTransaction",
+			Handler:    _ClientService_SendTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _ClientService_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransactionReceipt",
+			Handler:    _ClientService_GetTransactionReceipt_Handler,
+		},
+		{
+			MethodName: "Call",
+			Handler:    _ClientService_Call_Handler,
+		},
+		{
+			MethodName: "EstimateGas",
+			Handler:    _ClientService_EstimateGas_Handler,
+		},
+		{
+			MethodName: "GetUnbondingInfo",
+			Handler:    _ClientService_GetUnbondingInfo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "client.proto",
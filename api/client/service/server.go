@@ -3,19 +3,67 @@ package client
 import (
 	"context"
 	"log"
+	"math"
+	"math/big"
 	"net"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 	proto "github.com/harmony-one/harmony/api/client/service/proto"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
 	"google.golang.org/grpc"
 )
 
 // Server is the Server struct for client service package.
 type Server struct {
-	stateReader                       func() (*state.DB, error)
-	callFaucetContract                func(common.Address) common.Hash
-	getDeployedStakingContractAddress func() common.Address
+	stateReader        func() (*state.DB, error)
+	callFaucetContract func(common.Address) common.Hash
+	getProof           func(common.Address, []common.Hash, uint64) (*types.Header, *state.AccountProof, error)
+	currentBlockNumber func() uint64
+	sendTransaction    func(*types.Transaction) error
+	getTransaction     func(common.Hash) (*types.Transaction, common.Hash, uint64, uint64)
+	getReceipt         func(common.Hash) (*types.Receipt, common.Hash, uint64, uint64)
+	getPoolTransaction func(common.Hash) (*types.Transaction, core.TxStatus)
+	call               func(types.Message, uint64) ([]byte, uint64, bool, error)
+	estimateGas        func(types.Message, uint64) (uint64, error)
+	// unbondingReleaseBlock returns the block a stake withdrawn in a block is released at.
+	unbondingReleaseBlock func(uint64) uint64
+}
+
+// txErrorCodes maps the errors of the transaction pool to the error codes
+// reported to the clients.
+// LatestBlockNumber is the block number of a GetProof request asking for the
+// state of the current block.
+const LatestBlockNumber = math.MaxUint64
+
+var txErrorCodes = map[error]proto.TransactionErrorCode{
+	core.ErrInvalidShard:       proto.TransactionErrorCode_TX_INVALID_SHARD,
+	core.ErrInvalidSender:      proto.TransactionErrorCode_TX_INVALID_SENDER,
+	core.ErrNonceTooLow:        proto.TransactionErrorCode_TX_NONCE_TOO_LOW,
+	core.ErrUnderpriced:        proto.TransactionErrorCode_TX_UNDERPRICED,
+	core.ErrReplaceUnderpriced: proto.TransactionErrorCode_TX_REPLACE_UNDERPRICED,
+	core.ErrInsufficientFunds:  proto.TransactionErrorCode_TX_INSUFFICIENT_FUNDS,
+	core.ErrIntrinsicGas:       proto.TransactionErrorCode_TX_INTRINSIC_GAS,
+	core.ErrGasLimit:           proto.TransactionErrorCode_TX_GAS_LIMIT,
+	core.ErrNegativeValue:      proto.TransactionErrorCode_TX_NEGATIVE_VALUE,
+	core.ErrOversizedData:      proto.TransactionErrorCode_TX_OVERSIZED_DATA,
+	core.ErrKnownTransaction:   proto.TransactionErrorCode_TX_KNOWN_TRANSACTION,
+	staking.ErrWrongShard:      proto.TransactionErrorCode_TX_INVALID_SHARD,
+}
+
+// TransactionErrorCode returns the error code reported to the clients for an
+// error of the transaction pool.
+func TransactionErrorCode(err error) proto.TransactionErrorCode {
+	if err == nil {
+		return proto.TransactionErrorCode_TX_OK
+	}
+	if code, ok := txErrorCodes[err]; ok {
+		return code
+	}
+	return proto.TransactionErrorCode_TX_UNKNOWN_ERROR
 }
 
 // FetchAccountState implements the FetchAccountState interface to return account state.
@@ -46,11 +94,234 @@ func (s *Server) GetStakingContractInfo(ctx context.Context, request *proto.Stak
 	if err != nil {
 		return nil, err
 	}
-	return &proto.StakingContractInfoResponse{
-		ContractAddress: s.getDeployedStakingContractAddress().Hex(),
+	response := &proto.StakingContractInfoResponse{
+		ContractAddress: staking.Address.Hex(),
 		Balance:         state.GetBalance(address).Bytes(),
 		Nonce:           state.GetNonce(address),
-	}, nil
+	}
+	if validator := staking.GetValidator(state, address); validator != nil {
+		response.Stake = validator.Stake.Bytes()
+	}
+	return response, nil
+}
+
+// GetUnbondingInfo implements the GetUnbondingInfo interface to return the
+// withdrawn stakes of an account which are still locked.
+func (s *Server) GetUnbondingInfo(ctx context.Context, request *proto.UnbondingInfoRequest) (*proto.UnbondingInfoResponse, error) {
+	var address common.Address
+	address.SetBytes(request.Address)
+	state, err := s.stateReader()
+	if err != nil {
+		return nil, err
+	}
+	response := &proto.UnbondingInfoResponse{BlockNumber: s.currentBlockNumber()}
+	locked := new(big.Int)
+	for _, unbonding := range staking.GetUnbondings(state, address) {
+		releaseBlock := s.unbondingReleaseBlock(unbonding.Block)
+		entry := &proto.Unbonding{
+			Validator:     unbonding.Validator.Bytes(),
+			Amount:        unbonding.Amount.Bytes(),
+			WithdrawBlock: unbonding.Block,
+			ReleaseBlock:  releaseBlock,
+		}
+		if releaseBlock > response.BlockNumber {
+			entry.RemainingBlocks = releaseBlock - response.BlockNumber
+		}
+		locked.Add(locked, unbonding.Amount)
+		response.Unbondings = append(response.Unbondings, entry)
+	}
+	response.Locked = locked.Bytes()
+	return response, nil
+}
+
+// GetProof implements the GetProof interface to return the Merkle proof of an
+// account and its storage, with the header the proof can be checked against.
+func (s *Server) GetProof(ctx context.Context, request *proto.GetProofRequest) (*proto.GetProofResponse, error) {
+	var address common.Address
+	address.SetBytes(request.Address)
+	storageKeys := make([]common.Hash, 0, len(request.StorageKeys))
+	for _, key := range request.StorageKeys {
+		storageKeys = append(storageKeys, common.BytesToHash(key))
+	}
+	blockNumber := request.BlockNumber
+	if blockNumber == LatestBlockNumber {
+		blockNumber = s.currentBlockNumber()
+	}
+	log.Println("Returning GetProofResponse for address: ", address.Hex(), " block: ", blockNumber)
+	header, proof, err := s.getProof(address, storageKeys, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	headerBytes, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	response := &proto.GetProofResponse{
+		Header:       headerBytes,
+		Balance:      proof.Balance.Bytes(),
+		Nonce:        proof.Nonce,
+		CodeHash:     proof.CodeHash.Bytes(),
+		StorageHash:  proof.StorageHash.Bytes(),
+		AccountProof: proof.AccountProof,
+	}
+	for _, storageProof := range proof.StorageProof {
+		response.StorageProof = append(response.StorageProof, &proto.StorageProof{
+			Key:   storageProof.Key.Bytes(),
+			Value: storageProof.Value.Bytes(),
+			Proof: storageProof.Proof,
+		})
+	}
+	return response, nil
+}
+
+// SendTransaction implements the SendTransaction interface to add a signed
+// transaction to the transaction pool. A rejected transaction is reported with
+// its error code in the response rather than as an RPC error.
+func (s *Server) SendTransaction(ctx context.Context, request *proto.SendTransactionRequest) (*proto.SendTransactionResponse, error) {
+	tx := &types.Transaction{}
+	if err := rlp.DecodeBytes(request.Transaction, tx); err != nil {
+		return &proto.SendTransactionResponse{
+			ErrorCode:    proto.TransactionErrorCode_TX_INVALID_ENCODING,
+			ErrorMessage: err.Error(),
+		}, nil
+	}
+	log.Println("Returning SendTransactionResponse for transaction: ", tx.Hash().Hex())
+	response := &proto.SendTransactionResponse{TxId: tx.Hash().Bytes()}
+	if err := s.sendTransaction(tx); err != nil {
+		response.ErrorCode = TransactionErrorCode(err)
+		response.ErrorMessage = err.Error()
+	}
+	return response, nil
+}
+
+// GetTransaction implements the GetTransaction interface to return a transaction
+// from the chain or the transaction pool, with its status.
+func (s *Server) GetTransaction(ctx context.Context, request *proto.GetTransactionRequest) (*proto.GetTransactionResponse, error) {
+	hash := common.BytesToHash(request.TxId)
+	log.Println("Returning GetTransactionResponse for transaction: ", hash.Hex())
+	response := &proto.GetTransactionResponse{}
+	tx, blockHash, blockNumber, index := s.getTransaction(hash)
+	if tx != nil {
+		response.Status = proto.TransactionStatus_TX_STATUS_INCLUDED
+		if receipt, _, _, _ := s.getReceipt(hash); receipt != nil {
+			response.Status = receiptStatus(receipt)
+		}
+		response.BlockHash = blockHash.Bytes()
+		response.BlockNumber = blockNumber
+		response.Index = index
+	} else {
+		var status core.TxStatus
+		tx, status = s.getPoolTransaction(hash)
+		response.Status = poolStatus(status)
+	}
+	if tx != nil {
+		data, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return nil, err
+		}
+		response.Transaction = data
+	}
+	return response, nil
+}
+
+// GetTransactionReceipt implements the GetTransactionReceipt interface to return
+// the receipt of an included transaction, or the status of a transaction not
+// included yet.
+func (s *Server) GetTransactionReceipt(ctx context.Context, request *proto.GetTransactionReceiptRequest) (*proto.GetTransactionReceiptResponse, error) {
+	hash := common.BytesToHash(request.TxId)
+	log.Println("Returning GetTransactionReceiptResponse for transaction: ", hash.Hex())
+	receipt, blockHash, blockNumber, index := s.getReceipt(hash)
+	if receipt == nil {
+		_, status := s.getPoolTransaction(hash)
+		return &proto.GetTransactionReceiptResponse{Status: poolStatus(status)}, nil
+	}
+	response := &proto.GetTransactionReceiptResponse{
+		Status:            receiptStatus(receipt),
+		BlockHash:         blockHash.Bytes(),
+		BlockNumber:       blockNumber,
+		Index:             index,
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		response.ContractAddress = receipt.ContractAddress.Bytes()
+	}
+	for _, txLog := range receipt.Logs {
+		protoLog := &proto.Log{Address: txLog.Address.Bytes(), Data: txLog.Data, Index: uint64(txLog.Index)}
+		for _, topic := range txLog.Topics {
+			protoLog.Topics = append(protoLog.Topics, topic.Bytes())
+		}
+		response.Logs = append(response.Logs, protoLog)
+	}
+	return response, nil
+}
+
+// Call implements the Call interface to execute a message call against the state
+// of a block without creating a transaction.
+func (s *Server) Call(ctx context.Context, request *proto.CallRequest) (*proto.CallResponse, error) {
+	msg, blockNumber := s.callMessage(request)
+	log.Println("Returning CallResponse for address: ", msg.From().Hex(), " block: ", blockNumber)
+	result, gasUsed, failed, err := s.call(msg, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.CallResponse{Result: result, GasUsed: gasUsed, Failed: failed}, nil
+}
+
+// EstimateGas implements the EstimateGas interface to return the lowest gas
+// limit with which a message call succeeds against the state of a block.
+func (s *Server) EstimateGas(ctx context.Context, request *proto.CallRequest) (*proto.EstimateGasResponse, error) {
+	msg, blockNumber := s.callMessage(request)
+	log.Println("Returning EstimateGasResponse for address: ", msg.From().Hex(), " block: ", blockNumber)
+	gas, err := s.estimateGas(msg, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.EstimateGasResponse{Gas: gas}, nil
+}
+
+// callMessage returns the message of a call request and the number of the block
+// to execute it on.
+func (s *Server) callMessage(request *proto.CallRequest) (types.Message, uint64) {
+	var to *common.Address
+	if len(request.To) > 0 {
+		address := common.BytesToAddress(request.To)
+		to = &address
+	}
+	msg := types.NewMessage(
+		common.BytesToAddress(request.From),
+		to,
+		0,
+		new(big.Int).SetBytes(request.Value),
+		request.Gas,
+		new(big.Int).SetBytes(request.GasPrice),
+		request.Data,
+		false)
+	blockNumber := request.BlockNumber
+	if blockNumber == 0 {
+		blockNumber = s.currentBlockNumber()
+	}
+	return msg, blockNumber
+}
+
+// receiptStatus returns the status of an included transaction from its receipt.
+// Receipts with a post state root instead of a status are considered successful.
+func receiptStatus(receipt *types.Receipt) proto.TransactionStatus {
+	if len(receipt.PostState) == 0 && receipt.Status == types.ReceiptStatusFailed {
+		return proto.TransactionStatus_TX_STATUS_FAILED
+	}
+	return proto.TransactionStatus_TX_STATUS_INCLUDED
+}
+
+// poolStatus returns the status of a transaction in the transaction pool.
+func poolStatus(status core.TxStatus) proto.TransactionStatus {
+	switch status {
+	case core.TxStatusQueued:
+		return proto.TransactionStatus_TX_STATUS_QUEUED
+	case core.TxStatusPending:
+		return proto.TransactionStatus_TX_STATUS_PENDING
+	}
+	return proto.TransactionStatus_TX_STATUS_UNKNOWN
 }
 
 // Start starts the Server on given ip and port.
@@ -72,11 +343,28 @@ func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 func NewServer(
 	stateReader func() (*state.DB, error),
 	callFaucetContract func(common.Address) common.Hash,
-	getDeployedStakingContractAddress func() common.Address) *Server {
+	getProof func(common.Address, []common.Hash, uint64) (*types.Header, *state.AccountProof, error),
+	currentBlockNumber func() uint64,
+	sendTransaction func(*types.Transaction) error,
+	getTransaction func(common.Hash) (*types.Transaction, common.Hash, uint64, uint64),
+	getReceipt func(common.Hash) (*types.Receipt, common.Hash, uint64, uint64),
+	getPoolTransaction func(common.Hash) (*types.Transaction, core.TxStatus),
+	call func(types.Message, uint64) ([]byte, uint64, bool, error),
+	estimateGas func(types.Message, uint64) (uint64, error),
+	unbondingReleaseBlock func(uint64) uint64) *Server {
 	s := &Server{
-		stateReader:                       stateReader,
-		callFaucetContract:                callFaucetContract,
-		getDeployedStakingContractAddress: getDeployedStakingContractAddress,
+		stateReader:        stateReader,
+		callFaucetContract: callFaucetContract,
+		getProof:           getProof,
+		currentBlockNumber: currentBlockNumber,
+		sendTransaction:    sendTransaction,
+		getTransaction:     getTransaction,
+		getReceipt:         getReceipt,
+		getPoolTransaction: getPoolTransaction,
+		call:               call,
+		estimateGas:        estimateGas,
+
+		unbondingReleaseBlock: unbondingReleaseBlock,
 	}
 	return s
 }
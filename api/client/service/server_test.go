@@ -7,8 +7,12 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	client "github.com/harmony-one/harmony/api/client/service/proto"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/crypto/pki"
 
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
@@ -33,7 +37,7 @@ func TestGetFreeToken(test *testing.T) {
 		return nil, nil
 	}, func(common.Address) common.Hash {
 		return hash
-	}, nil)
+	}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	testBankKey, _ := crypto.GenerateKey()
 	testBankAddress := crypto.PubkeyToAddress(testBankKey.PublicKey)
@@ -67,7 +71,7 @@ func TestFetchAccountState(test *testing.T) {
 		return chain.State()
 	}, func(common.Address) common.Hash {
 		return hash
-	}, nil)
+	}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	response, err := server.FetchAccountState(nil, &client.FetchAccountStateRequest{Address: testBankAddress.Bytes()})
 
@@ -83,3 +87,296 @@ func TestFetchAccountState(test *testing.T) {
 		test.Errorf("Wrong nonce is returned")
 	}
 }
+
+// testSealEngine accepts the seals of all headers.
+type testSealEngine struct {
+	consensus.Engine
+}
+
+func (testSealEngine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	return nil
+}
+
+func TestGetProof(test *testing.T) {
+	var (
+		database = ethdb.NewMemDatabase()
+		gspec    = core.Genesis{
+			Config:  chainConfig,
+			Alloc:   core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			ShardID: 10,
+		}
+	)
+
+	genesis := gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, consensus.NewFaker(), vm.Config{}, nil)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, consensus.NewFaker(), database, 1, nil)
+	if _, err := chain.InsertChain(blocks); err != nil {
+		test.Fatalf("Failed to insert block: %v", err)
+	}
+
+	server := NewServer(chain.State, nil, chain.GetProof, func() uint64 {
+		return chain.CurrentBlock().NumberU64()
+	}, nil, nil, nil, nil, nil, nil, nil)
+
+	// The state of the genesis block can be proven.
+	response, err := server.GetProof(nil, &client.GetProofRequest{Address: testBankAddress.Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get genesis proof: %v", err)
+	}
+	if header, _, err := VerifyProofResponse(testBankAddress, response, testSealEngine{}); err != nil || header.Hash() != genesis.Hash() {
+		test.Errorf("Proof should be against the genesis block: %v", err)
+	}
+
+	response, err = server.GetProof(nil, &client.GetProofRequest{Address: testBankAddress.Bytes(), BlockNumber: LatestBlockNumber})
+	if err != nil {
+		test.Fatalf("Failed to get proof: %v", err)
+	}
+	if _, _, err := VerifyProofResponse(testBankAddress, response, consensus.NewFaker()); err != consensus.ErrUnknownCommittee {
+		test.Errorf("Header without known committee should not verify: %v", err)
+	}
+	header, proof, err := VerifyProofResponse(testBankAddress, response, testSealEngine{})
+	if err != nil {
+		test.Fatalf("Failed to verify proof: %v", err)
+	}
+	if header.Hash() != chain.CurrentBlock().Hash() {
+		test.Errorf("Proof should be against the current block")
+	}
+	if proof.Balance.Cmp(testBankFunds) != 0 {
+		test.Errorf("Wrong balance is proven")
+	}
+
+	response.Balance = big.NewInt(1).Bytes()
+	if _, _, err := VerifyProofResponse(testBankAddress, response, testSealEngine{}); err == nil {
+		test.Errorf("Tampered balance should not verify")
+	}
+}
+
+func TestSendTransaction(test *testing.T) {
+	var (
+		database = ethdb.NewMemDatabase()
+		gspec    = core.Genesis{
+			Config: chainConfig,
+			Alloc:  core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+		}
+	)
+
+	gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, consensus.NewFaker(), vm.Config{}, nil)
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	txPool := core.NewTxPool(txPoolConfig, chainConfig, chain)
+	defer txPool.Stop()
+
+	server := NewServer(chain.State, nil, nil, nil, txPool.AddRemote, nil, nil, nil, nil, nil, nil)
+	send := func(tx *types.Transaction) *client.SendTransactionResponse {
+		data, _ := rlp.EncodeToBytes(tx)
+		response, err := server.SendTransaction(nil, &client.SendTransactionRequest{Transaction: data})
+		if err != nil {
+			test.Fatalf("Failed to send transaction: %v", err)
+		}
+		return response
+	}
+
+	shardID := chain.ShardID()
+	tx, _ := types.SignTx(types.NewTransaction(0, testBankAddress, shardID, big.NewInt(1), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testBankKey)
+	if response := send(tx); response.ErrorCode != client.TransactionErrorCode_TX_OK || !bytes.Equal(response.TxId, tx.Hash().Bytes()) {
+		test.Errorf("Transaction should be accepted, got %v: %s", response.ErrorCode, response.ErrorMessage)
+	}
+	if response := send(tx); response.ErrorCode != client.TransactionErrorCode_TX_KNOWN_TRANSACTION {
+		test.Errorf("Expected %v, got %v", client.TransactionErrorCode_TX_KNOWN_TRANSACTION, response.ErrorCode)
+	}
+
+	tx, _ = types.SignTx(types.NewTransaction(1, testBankAddress, shardID+1, big.NewInt(1), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testBankKey)
+	if response := send(tx); response.ErrorCode != client.TransactionErrorCode_TX_INVALID_SHARD {
+		test.Errorf("Expected %v, got %v", client.TransactionErrorCode_TX_INVALID_SHARD, response.ErrorCode)
+	}
+
+	response, err := server.SendTransaction(nil, &client.SendTransactionRequest{Transaction: []byte{1, 2, 3}})
+	if err != nil || response.ErrorCode != client.TransactionErrorCode_TX_INVALID_ENCODING {
+		test.Errorf("Expected %v, got %v", client.TransactionErrorCode_TX_INVALID_ENCODING, response.ErrorCode)
+	}
+}
+
+func TestGetTransactionAndReceipt(test *testing.T) {
+	var (
+		database = ethdb.NewMemDatabase()
+		gspec    = core.Genesis{
+			Config: chainConfig,
+			Alloc:  core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+		}
+	)
+
+	genesis := gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, consensus.NewFaker(), vm.Config{}, nil)
+	shardID := chain.ShardID()
+	signTx := func(nonce uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, testBankAddress, shardID, big.NewInt(1), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testBankKey)
+		return tx
+	}
+	included, pending, queued := signTx(0), signTx(1), signTx(3)
+
+	genDB := ethdb.NewMemDatabase()
+	gspec.MustCommit(genDB)
+	blocks, _ := core.GenerateChain(chainConfig, genesis, consensus.NewFaker(), genDB, 1, func(i int, gen *core.BlockGen) {
+		gen.AddTx(included)
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		test.Fatalf("Failed to insert chain: %v", err)
+	}
+
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	txPool := core.NewTxPool(txPoolConfig, chainConfig, chain)
+	defer txPool.Stop()
+	txPool.AddRemotes([]*types.Transaction{pending, queued})
+
+	server := NewServer(chain.State, nil, nil, nil, txPool.AddRemote, chain.GetTransaction, chain.GetReceipt, func(hash common.Hash) (*types.Transaction, core.TxStatus) {
+		return txPool.Get(hash), txPool.Status([]common.Hash{hash})[0]
+	}, nil, nil, nil)
+
+	response, err := server.GetTransaction(nil, &client.GetTransactionRequest{TxId: included.Hash().Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get transaction: %v", err)
+	}
+	if response.Status != client.TransactionStatus_TX_STATUS_INCLUDED || response.BlockNumber != 1 || !bytes.Equal(response.BlockHash, blocks[0].Hash().Bytes()) {
+		test.Errorf("Unexpected included transaction: %v", response)
+	}
+	receipt, err := server.GetTransactionReceipt(nil, &client.GetTransactionReceiptRequest{TxId: included.Hash().Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get receipt: %v", err)
+	}
+	if receipt.Status != client.TransactionStatus_TX_STATUS_INCLUDED || receipt.BlockNumber != 1 || receipt.GasUsed != params.TxGas {
+		test.Errorf("Unexpected receipt: %v", receipt)
+	}
+
+	for tx, status := range map[*types.Transaction]client.TransactionStatus{
+		pending:      client.TransactionStatus_TX_STATUS_PENDING,
+		queued:       client.TransactionStatus_TX_STATUS_QUEUED,
+		signTx(1000): client.TransactionStatus_TX_STATUS_UNKNOWN,
+	} {
+		response, err := server.GetTransaction(nil, &client.GetTransactionRequest{TxId: tx.Hash().Bytes()})
+		if err != nil || response.Status != status {
+			test.Errorf("Expected transaction status %v, got %v", status, response.Status)
+		}
+		receipt, err := server.GetTransactionReceipt(nil, &client.GetTransactionReceiptRequest{TxId: tx.Hash().Bytes()})
+		if err != nil || receipt.Status != status {
+			test.Errorf("Expected receipt status %v, got %v", status, receipt.Status)
+		}
+	}
+}
+
+func TestCallAndEstimateGas(test *testing.T) {
+	var (
+		database        = ethdb.NewMemDatabase()
+		contractAddress = common.HexToAddress("0x1000")
+		gspec           = core.Genesis{
+			Config: chainConfig,
+			Alloc: core.GenesisAlloc{
+				testBankAddress: {Balance: testBankFunds},
+				// The contract returns 42 whatever the input.
+				contractAddress: {Balance: new(big.Int), Code: common.FromHex("602a60005260206000f3")},
+			},
+		}
+	)
+
+	gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, consensus.NewFaker(), vm.Config{}, nil)
+	currentBlockNumber := func() uint64 {
+		return chain.CurrentBlock().NumberU64()
+	}
+	server := NewServer(chain.State, nil, nil, currentBlockNumber, nil, nil, nil, nil, chain.Call, chain.EstimateGas, nil)
+
+	response, err := server.Call(nil, &client.CallRequest{From: testBankAddress.Bytes(), To: contractAddress.Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to call contract: %v", err)
+	}
+	if response.Failed || new(big.Int).SetBytes(response.Result).Int64() != 42 {
+		test.Errorf("Unexpected call result: %v", response)
+	}
+
+	estimate, err := server.EstimateGas(nil, &client.CallRequest{From: testBankAddress.Bytes(), To: testBankAddress.Bytes(), Value: big.NewInt(1).Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to estimate gas: %v", err)
+	}
+	if estimate.Gas != params.TxGas {
+		test.Errorf("Expected gas %v, got %v", params.TxGas, estimate.Gas)
+	}
+	if _, err := server.EstimateGas(nil, &client.CallRequest{From: testBankAddress.Bytes(), To: testBankAddress.Bytes(), Value: testBankFunds.Bytes(), GasPrice: big.NewInt(1).Bytes()}); err == nil {
+		test.Errorf("Estimation of an unaffordable transfer should fail")
+	}
+	if _, err := server.Call(nil, &client.CallRequest{From: testBankAddress.Bytes(), BlockNumber: 10}); err == nil {
+		test.Errorf("Call on an unknown block should fail")
+	}
+}
+
+func TestGetStakingContractInfo(test *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(testBankAddress, testBankFunds)
+	msg := &staking.Message{
+		Directive:    staking.CreateValidator,
+		BLSPublicKey: pki.GetBLSPrivateKeyFromInt(1).GetPublicKey().Serialize(),
+	}
+	if err := staking.Apply(statedb, 0, testBankAddress, big.NewInt(42), msg); err != nil {
+		test.Fatalf("Failed to create validator: %v", err)
+	}
+	server := NewServer(func() (*state.DB, error) {
+		return statedb, nil
+	}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	info, err := server.GetStakingContractInfo(nil, &client.StakingContractInfoRequest{Address: testBankAddress.Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get staking contract info: %v", err)
+	}
+	if info.ContractAddress != staking.Address.Hex() {
+		test.Errorf("Expected staking address %v, got %v", staking.Address.Hex(), info.ContractAddress)
+	}
+	if new(big.Int).SetBytes(info.Stake).Int64() != 42 {
+		test.Errorf("Expected stake 42, got %v", info.Stake)
+	}
+
+	info, err = server.GetStakingContractInfo(nil, &client.StakingContractInfoRequest{Address: common.HexToAddress("0x1000").Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get staking contract info: %v", err)
+	}
+	if len(info.Stake) != 0 {
+		test.Errorf("Expected no stake, got %v", info.Stake)
+	}
+}
+
+func TestGetUnbondingInfo(test *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(testBankAddress, testBankFunds)
+	msg := &staking.Message{
+		Directive:    staking.CreateValidator,
+		BLSPublicKey: pki.GetBLSPrivateKeyFromInt(1).GetPublicKey().Serialize(),
+	}
+	if err := staking.Apply(statedb, 0, testBankAddress, big.NewInt(42), msg); err != nil {
+		test.Fatalf("Failed to create validator: %v", err)
+	}
+	msg = &staking.Message{Directive: staking.Undelegate, Validator: testBankAddress, Amount: big.NewInt(10)}
+	if err := staking.Apply(statedb, 3, testBankAddress, new(big.Int), msg); err != nil {
+		test.Fatalf("Failed to undelegate: %v", err)
+	}
+	server := NewServer(func() (*state.DB, error) {
+		return statedb, nil
+	}, nil, nil, func() uint64 {
+		return 7
+	}, nil, nil, nil, nil, nil, nil, func(block uint64) uint64 {
+		return block + 10
+	})
+
+	info, err := server.GetUnbondingInfo(nil, &client.UnbondingInfoRequest{Address: testBankAddress.Bytes()})
+	if err != nil {
+		test.Fatalf("Failed to get unbonding info: %v", err)
+	}
+	if len(info.Unbondings) != 1 || new(big.Int).SetBytes(info.Locked).Int64() != 10 || info.BlockNumber != 7 {
+		test.Fatalf("Unexpected unbonding info: %v", info)
+	}
+	unbonding := info.Unbondings[0]
+	if unbonding.WithdrawBlock != 3 || unbonding.ReleaseBlock != 13 || unbonding.RemainingBlocks != 6 {
+		test.Errorf("Unexpected unbonding: %v", unbonding)
+	}
+	if !bytes.Equal(unbonding.Validator, testBankAddress.Bytes()) {
+		test.Errorf("Unexpected validator %x", unbonding.Validator)
+	}
+}
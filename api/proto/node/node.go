@@ -23,8 +23,9 @@ const (
 	Block
 	Client
 	Control
-	PING // node send ip/pki to register with leader
-	PONG // node broadcast pubK
+	PING    // node send ip/pki to register with leader
+	PONG    // node broadcast pubK
+	Receipt // cross-shard receipts proof sent to the destination shard
 	// TODO: add more types
 )
 
@@ -52,8 +53,17 @@ const (
 	Send TransactionMessageType = iota
 	Request
 	Unlock
+	Rejected // error codes of the transactions rejected by a node, sent back to their sender
 )
 
+// RejectedTransaction is a transaction rejected by a node, with the error code
+// the client service reports for it.
+type RejectedTransaction struct {
+	TxHash       common.Hash
+	ErrorCode    uint32
+	ErrorMessage string
+}
+
 // RoleType defines the role of the node
 type RoleType int
 
@@ -153,6 +163,22 @@ func ConstructRequestTransactionsMessage(transactionIds [][]byte) []byte {
 	return byteBuffer.Bytes()
 }
 
+// ConstructRejectedTransactionsMessage constructs the message sending the error
+// codes of rejected transactions back to their sender.
+func ConstructRejectedTransactionsMessage(rejected []RejectedTransaction) []byte {
+	byteBuffer := bytes.NewBuffer([]byte{byte(proto.Node)})
+	byteBuffer.WriteByte(byte(Transaction))
+	byteBuffer.WriteByte(byte(Rejected))
+
+	data, err := rlp.EncodeToBytes(rejected)
+	if err != nil {
+		log.Fatal(err)
+		return []byte{}
+	}
+	byteBuffer.Write(data)
+	return byteBuffer.Bytes()
+}
+
 // ConstructStopMessage constructs STOP message for node to stop
 func ConstructStopMessage() []byte {
 	byteBuffer := bytes.NewBuffer([]byte{byte(proto.Node)})
@@ -161,6 +187,21 @@ func ConstructStopMessage() []byte {
 	return byteBuffer.Bytes()
 }
 
+// ConstructCXReceiptsProofMessage constructs the message sending cross-shard
+// receipts with their proof to the destination shard
+func ConstructCXReceiptsProofMessage(cxp *types.CXReceiptsProof) []byte {
+	byteBuffer := bytes.NewBuffer([]byte{byte(proto.Node)})
+	byteBuffer.WriteByte(byte(Receipt))
+
+	cxpData, err := rlp.EncodeToBytes(cxp)
+	if err != nil {
+		log.Fatal(err)
+		return []byte{}
+	}
+	byteBuffer.Write(cxpData)
+	return byteBuffer.Bytes()
+}
+
 // ConstructBlocksSyncMessage constructs blocks sync message to send blocks to other nodes
 func ConstructBlocksSyncMessage(blocks []*types.Block) []byte {
 	byteBuffer := bytes.NewBuffer([]byte{byte(proto.Node)})
@@ -92,7 +92,7 @@ func TestConstructBlocksSyncMessage(t *testing.T) {
 	statedb.Commit(false)
 	statedb.Database().TrieDB().Commit(root, true)
 
-	block1 := types.NewBlock(head, nil, nil)
+	block1 := types.NewBlock(head, nil, nil, nil, nil)
 
 	blocks := []*types.Block{
 		block1,
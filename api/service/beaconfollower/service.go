@@ -0,0 +1,259 @@
+package beaconfollower
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/api/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// Constants of the beacon header follower.
+const (
+	// FollowInterval is the time between two downloads of the new beacon
+	// headers from the beacon peers.
+	FollowInterval = 10 * time.Second
+	// HeadersPerRequest is the number of beacon headers asked per request.
+	HeadersPerRequest = 128
+)
+
+// errMissingShardStates is returned when a beacon peer doesn't serve the shard
+// states of the epoch blocks it sent the headers of.
+var errMissingShardStates = errors.New("missing beacon shard states")
+
+// Service follows the beacon chain on a shard node. The headers of the beacon
+// blocks broadcast to the beacon group are stored as they come, and the missing
+// headers, along with the shard states of the epoch blocks, are downloaded from
+// the syncing servers of the beacon peers. The headers are verified against the
+// beacon committees before they are stored in the beacon headers of the chain.
+type Service struct {
+	host        p2p.Host
+	beacon      *core.BeaconHeaders
+	peers       []p2p.Peer // beacon peers, with the ports of their syncing servers
+	clients     []*downloader.Client
+	headers     chan *types.Header
+	cancel      context.CancelFunc
+	stopChan    chan struct{}
+	stoppedChan chan struct{}
+}
+
+// New returns the beacon header follower service storing the beacon headers in
+// beacon. The missing headers are downloaded from the syncing servers of the
+// given peers.
+func New(host p2p.Host, beacon *core.BeaconHeaders, peers []p2p.Peer) *Service {
+	return &Service{
+		host:    host,
+		beacon:  beacon,
+		peers:   peers,
+		headers: make(chan *types.Header, HeadersPerRequest),
+	}
+}
+
+// StartService starts the beacon header follower service.
+func (s *Service) StartService() {
+	utils.GetLogInstance().Info("Starting beacon header follower service.", "next", s.beacon.NextNumber(), "peers", len(s.peers))
+	s.stopChan = make(chan struct{})
+	s.stoppedChan = make(chan struct{})
+	s.clients = s.clients[:0]
+	for _, peer := range s.peers {
+		if client := downloader.ClientSetup(peer.IP, peer.Port); client != nil {
+			s.clients = append(s.clients, client)
+		}
+	}
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	if s.host != nil && utils.UseLibP2P {
+		go s.receiveBlocks(ctx)
+	}
+	go s.run()
+}
+
+// StopService stops the beacon header follower service.
+func (s *Service) StopService() {
+	utils.GetLogInstance().Info("Stopping beacon header follower service.")
+	s.cancel()
+	s.stopChan <- struct{}{}
+	<-s.stoppedChan
+	for _, client := range s.clients {
+		client.Close()
+	}
+	utils.GetLogInstance().Info("Beacon header follower stopped.")
+}
+
+// run stores the beacon headers received from the beacon group and downloads
+// the missing ones every FollowInterval.
+func (s *Service) run() {
+	defer close(s.stoppedChan)
+	s.follow()
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case header := <-s.headers:
+			s.addHeader(header)
+		case <-ticker.C:
+			s.follow()
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+// addHeader stores a beacon header received from the beacon group if it
+// follows the latest one, or downloads the missing headers up to it. The shard
+// state of an epoch block is only downloaded with the missing headers.
+func (s *Service) addHeader(header *types.Header) {
+	next := s.beacon.NextNumber()
+	switch number := header.Number.Uint64(); {
+	case number < next:
+		return
+	case number == next && header.ShardStateHash == (common.Hash{}):
+		if err := s.beacon.InsertHeader(header, nil); err != nil {
+			utils.GetLogInstance().Debug("[BEACON] unable to store beacon header", "number", number, "error", err)
+		}
+	default:
+		s.follow()
+	}
+}
+
+// follow downloads and stores the beacon headers following the latest one,
+// asking the beacon peers in turn until one of them has no more headers.
+func (s *Service) follow() {
+	for _, client := range s.clients {
+		for {
+			next := s.beacon.NextNumber()
+			response := client.GetHeaders(next, HeadersPerRequest)
+			if response == nil {
+				break
+			}
+			if response.BlockNumber < next {
+				return
+			}
+			headers, err := decodeHeaders(response.Payload)
+			if err != nil || len(headers) == 0 {
+				break
+			}
+			if err := s.insertHeaders(client, headers); err != nil {
+				utils.GetLogInstance().Warn("[BEACON] invalid beacon headers", "number", next, "error", err)
+				break
+			}
+			if len(headers) < HeadersPerRequest {
+				return
+			}
+		}
+	}
+}
+
+// insertHeaders stores the beacon headers downloaded from the given client,
+// along with the shard states of their epoch blocks.
+func (s *Service) insertHeaders(client *downloader.Client, headers []*types.Header) error {
+	hashes := [][]byte{}
+	for _, header := range headers {
+		if header.ShardStateHash != (common.Hash{}) {
+			hash := header.Hash()
+			hashes = append(hashes, hash[:])
+		}
+	}
+	shardStates := make(map[common.Hash]types.ShardState, len(hashes))
+	if len(hashes) > 0 {
+		response := client.GetShardStates(hashes)
+		if response == nil || len(response.Payload) != len(hashes) {
+			return errMissingShardStates
+		}
+		for i, payload := range response.Payload {
+			var shardState types.ShardState
+			if err := rlp.DecodeBytes(payload, &shardState); err != nil {
+				return err
+			}
+			shardStates[common.BytesToHash(hashes[i])] = shardState
+		}
+	}
+	for _, header := range headers {
+		if err := s.beacon.InsertHeader(header, shardStates[header.Hash()]); err != nil {
+			return err
+		}
+	}
+	utils.GetLogInstance().Info("[BEACON] stored beacon headers", "number", headers[len(headers)-1].Number, "shardStates", len(shardStates))
+	return nil
+}
+
+// receiveBlocks passes the headers of the beacon blocks broadcast to the
+// beacon group to run, until ctx is cancelled.
+func (s *Service) receiveBlocks(ctx context.Context) {
+	receiver, err := s.host.GroupReceiver(p2p.GroupIDBeacon)
+	if err != nil {
+		utils.GetLogInstance().Error("[BEACON] unable to join the beacon group", "error", err)
+		return
+	}
+	defer receiver.Close()
+	for {
+		msg, _, err := receiver.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		for _, header := range beaconHeaders(msg) {
+			select {
+			case s.headers <- header:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// beaconHeaders returns the headers of the beacon blocks carried by a block
+// sync message sent to the beacon group, or nil for any other message.
+func beaconHeaders(msg []byte) []*types.Header {
+	// skip the first 5 bytes, 1 byte is p2p type, 4 bytes are message size
+	if len(msg) < 5 {
+		return nil
+	}
+	content := msg[5:]
+	category, err := proto.GetMessageCategory(content)
+	if err != nil || category != proto.Node {
+		return nil
+	}
+	msgType, err := proto.GetMessageType(content)
+	if err != nil || proto_node.MessageType(msgType) != proto_node.Block {
+		return nil
+	}
+	payload, err := proto.GetMessagePayload(content)
+	if err != nil || len(payload) == 0 || proto_node.BlockMessageType(payload[0]) != proto_node.Sync {
+		return nil
+	}
+	var blocks []*types.Block
+	if err := rlp.DecodeBytes(payload[1:], &blocks); err != nil {
+		return nil
+	}
+	headers := make([]*types.Header, 0, len(blocks))
+	for _, block := range blocks {
+		if block.ShardID() == core.BeaconShardID {
+			headers = append(headers, block.Header())
+		}
+	}
+	return headers
+}
+
+// decodeHeaders decodes the RLP encoded headers of a HEADERS response.
+func decodeHeaders(payload [][]byte) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, len(payload))
+	for _, data := range payload {
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(data, header); err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
@@ -0,0 +1,27 @@
+package beaconfollower
+
+import (
+	"math/big"
+	"testing"
+
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/p2p/host"
+)
+
+func TestBeaconHeaders(t *testing.T) {
+	beaconBlock := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7)})
+	shardBlock := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(8), ShardID: types.EncodeShardID(1)})
+	msg := host.ConstructP2pMessage(byte(0), proto_node.ConstructBlocksSyncMessage([]*types.Block{beaconBlock, shardBlock}))
+
+	headers := beaconHeaders(msg)
+	if len(headers) != 1 || headers[0].Hash() != beaconBlock.Hash() {
+		t.Fatalf("wrong beacon headers: %v", headers)
+	}
+	if headers := beaconHeaders(host.ConstructP2pMessage(byte(0), proto_node.ConstructStopMessage())); headers != nil {
+		t.Errorf("beacon headers read from a control message: %v", headers)
+	}
+	if headers := beaconHeaders(msg[:3]); headers != nil {
+		t.Errorf("beacon headers read from a truncated message: %v", headers)
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	clientService "github.com/harmony-one/harmony/api/client/service"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
 	"google.golang.org/grpc"
 )
 
@@ -25,11 +27,19 @@ type Service struct {
 // New returns new client support service.
 func New(stateReader func() (*state.DB, error),
 	callFaucetContract func(common.Address) common.Hash,
-	getDeployedStakingContract func() common.Address,
+	getProof func(common.Address, []common.Hash, uint64) (*types.Header, *state.AccountProof, error),
+	currentBlockNumber func() uint64,
+	sendTransaction func(*types.Transaction) error,
+	getTransaction func(common.Hash) (*types.Transaction, common.Hash, uint64, uint64),
+	getReceipt func(common.Hash) (*types.Receipt, common.Hash, uint64, uint64),
+	getPoolTransaction func(common.Hash) (*types.Transaction, core.TxStatus),
+	call func(types.Message, uint64) ([]byte, uint64, bool, error),
+	estimateGas func(types.Message, uint64) (uint64, error),
+	unbondingReleaseBlock func(uint64) uint64,
 	ip, nodePort string) *Service {
 	port, _ := strconv.Atoi(nodePort)
 	return &Service{
-		server: clientService.NewServer(stateReader, callFaucetContract, getDeployedStakingContract),
+		server: clientService.NewServer(stateReader, callFaucetContract, getProof, currentBlockNumber, sendTransaction, getTransaction, getReceipt, getPoolTransaction, call, estimateGas, unbondingReleaseBlock),
 		ip:     ip,
 		port:   strconv.Itoa(port + ClientServicePortDiff)}
 }
@@ -0,0 +1,112 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Constants for JSON-RPC service.
+const (
+	// HTTPPortDiff is the positive port diff of the HTTP JSON-RPC endpoint.
+	HTTPPortDiff = 500
+	// WSPortDiff is the positive port diff of the WebSocket JSON-RPC endpoint.
+	WSPortDiff = 800
+)
+
+// Config configures the HTTP and WebSocket JSON-RPC endpoints.
+type Config struct {
+	HTTP         bool     // serve the HTTP endpoint
+	HTTPHost     string   // interface the HTTP endpoint listens on
+	CORS         []string // origins allowed by cross origin requests to the HTTP endpoint
+	VirtualHosts []string // host names accepted by the HTTP endpoint, against DNS rebinding
+	WS           bool     // serve the WebSocket endpoint
+	WSHost       string   // interface the WebSocket endpoint listens on
+	WSOrigins    []string // origins allowed to open a WebSocket connection
+	Modules      []string // API namespaces served by both endpoints
+}
+
+// DefaultConfig keeps both endpoints off; when enabled they only listen on the
+// local interface and only serve the eth namespace. The debug namespace, which
+// may re-execute blocks, has to be enabled explicitly.
+var DefaultConfig = Config{
+	HTTPHost:     "127.0.0.1",
+	VirtualHosts: []string{"localhost"},
+	WSHost:       "127.0.0.1",
+	Modules:      []string{"eth"},
+}
+
+// Service serves the node APIs over HTTP and WebSocket JSON-RPC.
+type Service struct {
+	apis         []rpc.API
+	config       Config
+	httpEndpoint string
+	wsEndpoint   string
+	httpListener net.Listener
+	httpHandler  *rpc.Server
+	wsListener   net.Listener
+	wsHandler    *rpc.Server
+}
+
+// New returns a JSON-RPC service serving the given APIs on ports derived from
+// the node port.
+func New(apis []rpc.API, config Config, nodePort string) *Service {
+	port, _ := strconv.Atoi(nodePort)
+	return &Service{
+		apis:         apis,
+		config:       config,
+		httpEndpoint: net.JoinHostPort(config.HTTPHost, strconv.Itoa(port+HTTPPortDiff)),
+		wsEndpoint:   net.JoinHostPort(config.WSHost, strconv.Itoa(port+WSPortDiff)),
+	}
+}
+
+// StartService starts the enabled HTTP and WebSocket endpoints.
+func (s *Service) StartService() {
+	if s.config.HTTP {
+		if err := s.startHTTP(); err != nil {
+			utils.GetLogInstance().Error("Failed to start HTTP JSON-RPC endpoint", "endpoint", s.httpEndpoint, "error", err)
+		}
+	}
+	if s.config.WS {
+		if err := s.startWS(); err != nil {
+			utils.GetLogInstance().Error("Failed to start WebSocket JSON-RPC endpoint", "endpoint", s.wsEndpoint, "error", err)
+		}
+	}
+}
+
+// StopService stops the HTTP and WebSocket endpoints.
+func (s *Service) StopService() {
+	if s.httpListener != nil {
+		s.httpListener.Close()
+		s.httpHandler.Stop()
+		s.httpListener, s.httpHandler = nil, nil
+	}
+	if s.wsListener != nil {
+		s.wsListener.Close()
+		s.wsHandler.Stop()
+		s.wsListener, s.wsHandler = nil, nil
+	}
+}
+
+func (s *Service) startHTTP() error {
+	listener, handler, err := rpc.StartHTTPEndpoint(s.httpEndpoint, s.apis, s.config.Modules, s.config.CORS, s.config.VirtualHosts, rpc.DefaultHTTPTimeouts)
+	if err != nil {
+		return err
+	}
+	utils.GetLogInstance().Info("HTTP JSON-RPC endpoint opened", "url", fmt.Sprintf("http://%s", s.httpEndpoint), "modules", s.config.Modules, "cors", s.config.CORS, "vhosts", s.config.VirtualHosts)
+	s.httpListener, s.httpHandler = listener, handler
+	return nil
+}
+
+func (s *Service) startWS() error {
+	listener, handler, err := rpc.StartWSEndpoint(s.wsEndpoint, s.apis, s.config.Modules, s.config.WSOrigins, false)
+	if err != nil {
+		return err
+	}
+	utils.GetLogInstance().Info("WebSocket JSON-RPC endpoint opened", "url", fmt.Sprintf("ws://%s", s.wsEndpoint), "modules", s.config.Modules, "origins", s.config.WSOrigins)
+	s.wsListener, s.wsHandler = listener, handler
+	return nil
+}
@@ -0,0 +1,41 @@
+package jsonrpc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestServiceDisabledByDefault(t *testing.T) {
+	s := New(nil, DefaultConfig, "19000")
+	s.StartService()
+	defer s.StopService()
+	if s.httpListener != nil || s.wsListener != nil {
+		t.Errorf("Expected no endpoint opened by the default config")
+	}
+}
+
+func TestServiceLocalInterface(t *testing.T) {
+	config := DefaultConfig
+	config.HTTP = true
+	s := New(nil, config, "19001")
+	s.StartService()
+	defer s.StopService()
+	if s.httpListener == nil {
+		t.Fatalf("Expected the HTTP endpoint to be opened")
+	}
+	if s.wsListener != nil {
+		t.Errorf("Expected the WebSocket endpoint to stay closed")
+	}
+	host, _, err := net.SplitHostPort(s.httpListener.Addr().String())
+	if err != nil || host != "127.0.0.1" {
+		t.Errorf("Expected the HTTP endpoint on 127.0.0.1, got %v", s.httpListener.Addr())
+	}
+}
+
+func TestDefaultModules(t *testing.T) {
+	for _, module := range DefaultConfig.Modules {
+		if module == "debug" {
+			t.Errorf("Expected the debug namespace to be off by default")
+		}
+	}
+}
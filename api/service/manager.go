@@ -30,6 +30,8 @@ const (
 	NetworkInfo
 	PeerDiscovery
 	Staking
+	JSONRPC
+	BeaconFollower
 	Test
 	Done
 )
@@ -52,8 +54,12 @@ func (t Type) String() string {
 		return "NetworkInfo"
 	case Staking:
 		return "Staking"
+	case JSONRPC:
+		return "JSONRPC"
 	case PeerDiscovery:
 		return "PeerDiscovery"
+	case BeaconFollower:
+		return "BeaconFollower"
 	case Test:
 		return "Test"
 	case Done:
@@ -4,13 +4,14 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/bls/ffi/go/bls"
 	client "github.com/harmony-one/harmony/api/client/service"
 	proto "github.com/harmony-one/harmony/api/client/service/proto"
 	"github.com/harmony-one/harmony/api/proto/message"
+	core_staking "github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
@@ -24,16 +25,18 @@ type Service struct {
 	stoppedChan   chan struct{}
 	peerChan      <-chan p2p.Peer
 	accountKey    *ecdsa.PrivateKey
+	blsPublicKey  *bls.PublicKey
 	stakingAmount int64
 }
 
 // New returns staking service.
-func New(accountKey *ecdsa.PrivateKey, stakingAmount int64, peerChan <-chan p2p.Peer) *Service {
+func New(accountKey *ecdsa.PrivateKey, blsPublicKey *bls.PublicKey, stakingAmount int64, peerChan <-chan p2p.Peer) *Service {
 	return &Service{
 		stopChan:      make(chan struct{}),
 		stoppedChan:   make(chan struct{}),
 		peerChan:      peerChan,
 		accountKey:    accountKey,
+		blsPublicKey:  blsPublicKey,
 		stakingAmount: stakingAmount,
 	}
 }
@@ -81,17 +84,45 @@ func (s *Service) getStakingInfo(beaconPeer p2p.Peer) *proto.StakingContractInfo
 	return client.GetStakingContractInfo(crypto.PubkeyToAddress(s.accountKey.PublicKey))
 }
 
+// estimateStakingGas estimates the gas of the staking transaction on the beacon
+// chain, falling back to a fixed gas if the estimation fails.
+func (s *Service) estimateStakingGas(beaconPeer p2p.Peer, msg types.Message) uint64 {
+	client := client.NewClient(beaconPeer.IP, beaconPeer.Port)
+	defer client.Close()
+	gas, err := client.EstimateGas(msg)
+	if err != nil {
+		utils.GetLogInstance().Warn("Failed to estimate the staking gas", "error", err)
+		return params.TxGas + core_staking.Gas*2
+	}
+	return gas
+}
+
+// createStakingMessage creates the message registering the node as a validator,
+// or adding to its stake if it is already a validator.
 func (s *Service) createStakingMessage(beaconPeer p2p.Peer) *message.Message {
 	stakingInfo := s.getStakingInfo(beaconPeer)
-	toAddress := common.HexToAddress(stakingInfo.ContractAddress)
+	from := crypto.PubkeyToAddress(s.accountKey.PublicKey)
+	stakingMsg := &core_staking.Message{Directive: core_staking.CreateValidator, BLSPublicKey: s.blsPublicKey.Serialize()}
+	if len(stakingInfo.Stake) > 0 {
+		utils.GetLogInstance().Info("Current stake", "stake", new(big.Int).SetBytes(stakingInfo.Stake))
+		stakingMsg = &core_staking.Message{Directive: core_staking.Delegate, Validator: from}
+	}
+	data, err := stakingMsg.Encode()
+	if err != nil {
+		utils.GetLogInstance().Error("Failed to encode the staking message", "error", err)
+		return nil
+	}
+	amount := big.NewInt(s.stakingAmount)
+	gasPrice := big.NewInt(int64(params.Sha256BaseGas)) // pick some predefined gas price.
+	msg := types.NewMessage(from, &core_staking.Address, stakingInfo.Nonce, amount, 0, gasPrice, data, false)
 	tx := types.NewTransaction(
 		stakingInfo.Nonce,
-		toAddress,
+		core_staking.Address,
 		0, // beacon chain.
-		big.NewInt(s.stakingAmount),
-		params.CallValueTransferGas*2,           // hard-code
-		big.NewInt(int64(params.Sha256BaseGas)), // pick some predefined gas price.
-		nil)
+		amount,
+		s.estimateStakingGas(beaconPeer, msg),
+		gasPrice,
+		data)
 
 	if signedTx, err := types.SignTx(tx, types.HomesteadSigner{}, s.accountKey); err == nil {
 		ts := types.Transactions{signedTx}
@@ -0,0 +1,401 @@
+package syncing
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/configs"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/node/worker"
+)
+
+// DownloadConfig configures the header-first download of the blocks missing
+// from the chain.
+type DownloadConfig struct {
+	HeaderBatch int  // Number of headers fetched and verified at once
+	BlockBatch  int  // Number of blocks fetched per request
+	Window      int  // Number of block requests in flight
+	Snapshot    bool // Whether a new node downloads the state of a recent block instead of replaying the chain
+}
+
+// DefaultDownloadConfig is the download configuration used by default.
+var DefaultDownloadConfig = DownloadConfig{
+	HeaderBatch: 512,
+	BlockBatch:  32,
+	Window:      8,
+}
+
+// headerBatch returns the number of headers to fetch at once, within the limit
+// served by the peers.
+func (config DownloadConfig) headerBatch() int {
+	if config.HeaderBatch <= 0 || config.HeaderBatch > MaxHeadersPerRequest {
+		return MaxHeadersPerRequest
+	}
+	return config.HeaderBatch
+}
+
+// blockBatch returns the number of blocks to fetch per request, within the limit
+// served by the peers.
+func (config DownloadConfig) blockBatch() int {
+	if config.BlockBatch <= 0 || config.BlockBatch > MaxBlocksPerRequest {
+		return MaxBlocksPerRequest
+	}
+	return config.BlockBatch
+}
+
+func (config DownloadConfig) window() int {
+	if config.Window <= 0 {
+		return 1
+	}
+	return config.Window
+}
+
+// GetHeaders gets the headers of up to size blocks from the given block number by
+// calling grpc request to the corresponding peer, and updates the current block
+// number of the peer.
+func (peerConfig *SyncPeerConfig) GetHeaders(startNumber uint64, size int) ([]*types.Header, error) {
+	if peerConfig.client == nil {
+		return nil, ErrSyncPeerConfigClientNotReady
+	}
+	var response *pb.DownloaderResponse
+	err := peerConfig.track(func() error {
+		if response = peerConfig.client.GetHeaders(startNumber, uint32(size)); response == nil {
+			return ErrGetHeaders
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	peerConfig.mux.Lock()
+	peerConfig.height = response.BlockNumber
+	peerConfig.mux.Unlock()
+	headers := make([]*types.Header, 0, len(response.Payload))
+	for _, payload := range response.Payload {
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(payload, header); err != nil {
+			return nil, ErrUndecodable
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
+
+// getTargetHeight asks every peer for its current block number and returns the
+// highest block number reached by ConsensusRatio of the peers.
+func (ss *StateSync) getTargetHeight(next uint64) uint64 {
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		heights []uint64
+	)
+	now := time.Now()
+	for _, peerConfig := range ss.syncConfig.peers {
+		if !peerConfig.canServe(0, now) {
+			continue
+		}
+		wg.Add(1)
+		go func(peerConfig *SyncPeerConfig) {
+			defer wg.Done()
+			if _, err := peerConfig.GetHeaders(next, 0); err != nil {
+				return
+			}
+			peerConfig.mux.Lock()
+			defer peerConfig.mux.Unlock()
+			mux.Lock()
+			heights = append(heights, peerConfig.height)
+			mux.Unlock()
+		}(peerConfig)
+	}
+	wg.Wait()
+	if len(heights) == 0 {
+		return 0
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	return heights[int(math.Ceil(ConsensusRatio*float64(len(heights))))-1]
+}
+
+// checkHeaderChain checks that the headers are consecutive and follow parent.
+func checkHeaderChain(parent *types.Header, headers []*types.Header) error {
+	for _, header := range headers {
+		if header.Number == nil || header.Number.Uint64() != parent.Number.Uint64()+1 || header.ParentHash != parent.Hash() {
+			return ErrBrokenHeaderChain
+		}
+		parent = header
+	}
+	return nil
+}
+
+// checkBlocks checks that the blocks are the ones of the headers and that their
+// transactions match the headers.
+func checkBlocks(headers []*types.Header, blocks []*types.Block) error {
+	if len(blocks) != len(headers) {
+		return ErrInvalidBlock
+	}
+	for i, block := range blocks {
+		if block.Hash() != headers[i].Hash() || types.DeriveSha(block.Transactions()) != block.Header().TxHash {
+			return ErrInvalidBlock
+		}
+	}
+	return nil
+}
+
+// checkShard checks that the headers belong to the given shard.
+func checkShard(shardID uint32, headers []*types.Header) error {
+	for _, header := range headers {
+		if header.ShardID != types.EncodeShardID(shardID) {
+			return ErrWrongShard
+		}
+	}
+	return nil
+}
+
+// verifySeals checks that the headers belong to the shard of the chain and
+// carry a valid commit signature of the committee of their epoch.
+func verifySeals(bc *core.BlockChain, headers []*types.Header) error {
+	if err := checkShard(bc.ShardID(), headers); err != nil {
+		return err
+	}
+	for _, header := range headers {
+		if err := bc.Engine().VerifySeal(bc, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBlock checks a block received outside of the header first download:
+// the block must match its header, which must pass verifySeals.
+func verifyBlock(bc *core.BlockChain, block *types.Block) error {
+	headers := []*types.Header{block.Header()}
+	if err := checkBlocks(headers, []*types.Block{block}); err != nil {
+		return err
+	}
+	return verifySeals(bc, headers)
+}
+
+// epochBatchSize limits a batch of size headers following the block with the
+// given number so that it ends at the next epoch block at the latest. The blocks
+// after an epoch block are signed by the committee of its shard state, which is
+// only stored once the epoch block is inserted.
+func epochBatchSize(config *configs.ChainConfig, parent uint64, size int) int {
+	end := config.EpochFirstBlock(config.EpochOfBlock(parent) + 1)
+	if remaining := end - parent; remaining < uint64(size) {
+		return int(remaining)
+	}
+	return size
+}
+
+// getHeaders fetches and verifies the headers of up to size blocks following
+// parent: the headers must be linked to parent and carry a valid commit
+// signature of the committee. The batch must not go past the next epoch block,
+// see epochBatchSize. Headers which can't be verified locally, whichever peer
+// sent them, fail the download with the verification error.
+func (ss *StateSync) getHeaders(bc *core.BlockChain, parent *types.Header, size int) ([]*types.Header, error) {
+	next := parent.Number.Uint64() + 1
+	for attempt := 0; attempt < TimesToFail; attempt++ {
+		peerConfig := ss.pickPeer(next)
+		if peerConfig == nil {
+			return nil, ErrNoSyncPeers
+		}
+		headers, err := peerConfig.GetHeaders(next, size)
+		if err == ErrUndecodable {
+			peerConfig.disconnect()
+			continue
+		}
+		if err != nil {
+			utils.GetLogInstance().Debug("[SYNC] GetHeaders failed", "number", next, "error", err)
+			continue
+		}
+		if len(headers) == 0 {
+			peerConfig.checkWithheld(next)
+			continue
+		}
+		if len(headers) > size {
+			headers = headers[:size]
+		}
+		if err := checkHeaderChain(parent, headers); err != nil {
+			peerConfig.penalize()
+			continue
+		}
+		if err := verifySeals(bc, headers); err != nil {
+			if !reportInvalidBlock([]*SyncPeerConfig{peerConfig}, err) {
+				// The headers can't be verified locally, whichever peer sent them.
+				utils.GetLogInstance().Warn("[SYNC] unable to verify headers", "number", next, "error", err)
+				return nil, err
+			}
+			utils.GetLogInstance().Debug("[SYNC] invalid headers", "error", err)
+			continue
+		}
+		return headers, nil
+	}
+	return nil, ErrGetHeaders
+}
+
+// getBlocks downloads the blocks of the given verified headers, in batches of
+// BlockBatch blocks with up to Window requests in flight.
+func (ss *StateSync) getBlocks(db ethdb.Database, headers []*types.Header) ([]*types.Block, error) {
+	blocks := make([]*types.Block, len(headers))
+	ok := ss.forEachBatch(len(headers), ss.downloadConfig.blockBatch(), func(start, end int) bool {
+		return ss.getBlockBatch(db, headers[start:end], blocks[start:end])
+	})
+	if !ok {
+		return nil, ErrGetBlock
+	}
+	return blocks, nil
+}
+
+// forEachBatch splits n items into batches of the given size and runs fetch on
+// each batch, with up to Window batches in flight. It returns whether fetch
+// succeeded for every batch, giving up at the first failure.
+func (ss *StateSync) forEachBatch(n, size int, fetch func(start, end int) bool) bool {
+	var (
+		tasks  = make(chan int, (n+size-1)/size)
+		failed int32
+		wg     sync.WaitGroup
+	)
+	for start := 0; start < n; start += size {
+		tasks <- start
+	}
+	close(tasks)
+	for i := 0; i < ss.downloadConfig.window(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range tasks {
+				end := start + size
+				if end > n {
+					end = n
+				}
+				if atomic.LoadInt32(&failed) != 0 || !fetch(start, end) {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return failed == 0
+}
+
+// getBlockBatch downloads the blocks of a batch of headers into blocks, trying
+// other peers on failure. The blocks already stored by an interrupted download
+// are not downloaded again. It returns whether the download succeeded.
+func (ss *StateSync) getBlockBatch(db ethdb.Database, headers []*types.Header, blocks []*types.Block) bool {
+	if stored := readPendingBlocks(db, headers); stored != nil && checkBlocks(headers, stored) == nil {
+		copy(blocks, stored)
+		return true
+	}
+	hashes := make([][]byte, len(headers))
+	for i, header := range headers {
+		hash := header.Hash()
+		hashes[i] = hash[:]
+	}
+	last := headers[len(headers)-1].Number.Uint64()
+	for attempt := 0; attempt < TimesToFail; attempt++ {
+		peerConfig := ss.pickPeer(last)
+		if peerConfig == nil {
+			return false
+		}
+		var payload [][]byte
+		err := peerConfig.track(func() (err error) {
+			payload, err = peerConfig.GetBlocks(hashes)
+			return err
+		})
+		if err != nil {
+			utils.GetLogInstance().Debug("[SYNC] GetBlocks failed", "number", last, "error", err)
+			continue
+		}
+		downloaded, err := decodeBlocks(payload)
+		if err != nil {
+			peerConfig.disconnect()
+			continue
+		}
+		if err := checkBlocks(headers, downloaded); err != nil {
+			peerConfig.penalize()
+			continue
+		}
+		ss.addPendingBlocks(db, downloaded)
+		copy(blocks, downloaded)
+		return true
+	}
+	return false
+}
+
+func decodeBlocks(payload [][]byte) ([]*types.Block, error) {
+	blocks := make([]*types.Block, 0, len(payload))
+	for _, data := range payload {
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(data, block); err != nil {
+			return nil, ErrUndecodable
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// downloadBlocks downloads the blocks from the current block of the chain up to
+// the block reached by the peers. The headers are fetched and verified in
+// batches of HeaderBatch headers, ending at the epoch blocks, then the blocks of
+// the batch are downloaded and inserted, so that only one batch is held in
+// memory at a time and the shard state of an epoch is stored before the headers
+// signed by its committee are verified. The
+// progress is kept in the chain database until the download is done, so that a
+// restarted node resumes an interrupted download.
+func (ss *StateSync) downloadBlocks(bc *core.BlockChain, worker *worker.Worker) error {
+	db := bc.ChainDb()
+	saved := ss.resumeProgress(db)
+	current := bc.CurrentBlock().NumberU64()
+	target := ss.getTargetHeight(current + 1)
+	if saved > target {
+		target = saved
+	}
+	ss.startProgress(current, target)
+	if target <= current {
+		ss.prunePendingBlocks(db, true)
+		return nil
+	}
+	ss.setProgressTarget(db, target)
+	if ss.downloadConfig.Snapshot && current == 0 && target >= MinSnapshotDistance {
+		if err := ss.syncSnapshot(bc, target-SnapshotPivotDistance); err != nil {
+			return err
+		}
+		ss.syncMux.Lock()
+		worker.UpdateCurrent()
+		ss.syncMux.Unlock()
+	}
+	utils.GetLogInstance().Info("[SYNC] downloading blocks", "from", bc.CurrentBlock().NumberU64(), "to", target)
+	for parent := bc.CurrentBlock().Header(); parent.Number.Uint64() < target; parent = bc.CurrentBlock().Header() {
+		size := ss.downloadConfig.headerBatch()
+		if remaining := target - parent.Number.Uint64(); remaining < uint64(size) {
+			size = int(remaining)
+		}
+		size = epochBatchSize(bc.HarmonyConfig(), parent.Number.Uint64(), size)
+		headers, err := ss.getHeaders(bc, parent, size)
+		if err != nil {
+			return err
+		}
+		blocks, err := ss.getBlocks(db, headers)
+		if err != nil {
+			return err
+		}
+		_, err = bc.InsertChain(blocks)
+		ss.prunePendingBlocks(db, false)
+		if err != nil {
+			return err
+		}
+		ss.syncMux.Lock()
+		worker.UpdateCurrent()
+		ss.syncMux.Unlock()
+		utils.GetLogInstance().Info("[SYNC] downloaded blocks", "number", bc.CurrentBlock().NumberU64(), "target", target)
+	}
+	ss.prunePendingBlocks(db, true)
+	return nil
+}
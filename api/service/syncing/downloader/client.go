@@ -2,7 +2,6 @@ package downloader
 
 import (
 	"context"
-	"encoding/binary"
 	"fmt"
 	"time"
 
@@ -70,41 +69,70 @@ func (client *Client) GetBlocks(hashes [][]byte) *pb.DownloaderResponse {
 	return response
 }
 
-// Register will register node's ip/port information to peers receive newly created blocks in future
-// hash is the bytes of "ip:port" string representation
-func (client *Client) Register(hash []byte) *pb.DownloaderResponse {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+// GetHeaders gets the RLP encoded headers of up to size blocks from the block
+// with the given number by calling a grpc request. The response also holds the
+// number of the current block of the peer.
+func (client *Client) GetHeaders(startNumber uint64, size uint32) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_REGISTER}
-	request.PeerHash = make([]byte, len(hash))
-	copy(request.PeerHash, hash)
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_HEADERS, StartNumber: startNumber, Size: size}
 	response, err := client.dlClient.Query(ctx, request)
 	if err != nil {
-		utils.GetLogInstance().Info("[SYNC] client.go:Register failed.", "error", err)
+		utils.GetLogInstance().Info("[SYNC] GetHeaders query failed", "error", err)
 	}
 	return response
 }
 
-// PushNewBlock will send the lastest verified blow to registered nodes
-func (client *Client) PushNewBlock(peerID uint32, blockHash []byte, timeout bool) *pb.DownloaderResponse {
+// GetTrieNodes gets the state trie nodes and contract codes with the given
+// hashes by calling a grpc request. The nodes unknown to the peer are left out
+// of the response.
+func (client *Client) GetTrieNodes(hashes [][]byte) *pb.DownloaderResponse {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_TRIENODES, Hashes: hashes}
+	response, err := client.dlClient.Query(ctx, request)
+	if err != nil {
+		utils.GetLogInstance().Info("[SYNC] GetTrieNodes query failed", "error", err)
+	}
+	return response
+}
 
-	peerHash := make([]byte, 4)
-	binary.BigEndian.PutUint32(peerHash, peerID)
-	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_NEWBLOCK}
-	request.BlockHash = make([]byte, len(blockHash))
-	copy(request.BlockHash, blockHash)
-	request.PeerHash = make([]byte, len(peerHash))
-	copy(request.PeerHash, peerHash)
-
-	if timeout {
-		request.Type = pb.DownloaderRequest_REGISTERTIMEOUT
+// GetReceipts gets the RLP encoded receipts of the blocks with the given hashes
+// by calling a grpc request.
+func (client *Client) GetReceipts(hashes [][]byte) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_RECEIPTS, Hashes: hashes}
+	response, err := client.dlClient.Query(ctx, request)
+	if err != nil {
+		utils.GetLogInstance().Info("[SYNC] GetReceipts query failed", "error", err)
 	}
+	return response
+}
 
+// GetShardStates gets the RLP encoded shard states stored with the epoch blocks
+// with the given hashes by calling a grpc request.
+func (client *Client) GetShardStates(hashes [][]byte) *pb.DownloaderResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_SHARDSTATES, Hashes: hashes}
 	response, err := client.dlClient.Query(ctx, request)
 	if err != nil {
-		utils.GetLogInstance().Info("[SYNC] unable to send new block to unsync node", "error", err)
+		utils.GetLogInstance().Info("[SYNC] GetShardStates query failed", "error", err)
 	}
 	return response
 }
+
+// SubscribeBlocks opens a stream of the blocks committed by the peer. The
+// stream is closed by calling the returned cancel function.
+func (client *Client) SubscribeBlocks(peerHash []byte) (pb.Downloader_SubscribeBlocksClient, context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	request := &pb.DownloaderRequest{PeerHash: peerHash}
+	stream, err := client.dlClient.SubscribeBlocks(ctx, request)
+	if err != nil {
+		cancel()
+		utils.GetLogInstance().Info("[SYNC] SubscribeBlocks failed", "error", err)
+		return nil, nil, err
+	}
+	return stream, cancel, nil
+}
@@ -31,6 +31,10 @@ const (
 	DownloaderRequest_REGISTER        DownloaderRequest_RequestType = 3
 	DownloaderRequest_REGISTERTIMEOUT DownloaderRequest_RequestType = 4
 	DownloaderRequest_UNKNOWN         DownloaderRequest_RequestType = 5
+	DownloaderRequest_HEADERS         DownloaderRequest_RequestType = 6
+	DownloaderRequest_TRIENODES       DownloaderRequest_RequestType = 7
+	DownloaderRequest_RECEIPTS        DownloaderRequest_RequestType = 8
+	DownloaderRequest_SHARDSTATES     DownloaderRequest_RequestType = 9
 )
 
 var DownloaderRequest_RequestType_name = map[int32]string{
@@ -40,6 +44,10 @@ var DownloaderRequest_RequestType_name = map[int32]string{
 	3: "REGISTER",
 	4: "REGISTERTIMEOUT",
 	5: "UNKNOWN",
+	6: "HEADERS",
+	7: "TRIENODES",
+	8: "RECEIPTS",
+	9: "SHARDSTATES",
 }
 
 var DownloaderRequest_RequestType_value = map[string]int32{
@@ -49,6 +57,10 @@ var DownloaderRequest_RequestType_value = map[string]int32{
 	"REGISTER":        3,
 	"REGISTERTIMEOUT": 4,
 	"UNKNOWN":         5,
+	"HEADERS":         6,
+	"TRIENODES":       7,
+	"RECEIPTS":        8,
+	"SHARDSTATES":     9,
 }
 
 func (x DownloaderRequest_RequestType) String() string {
@@ -91,10 +103,14 @@ func (DownloaderResponse_RegisterResponseType) EnumDescriptor() ([]byte, []int)
 type DownloaderRequest struct {
 	// Request type.
 	Type DownloaderRequest_RequestType `protobuf:"varint,1,opt,name=type,proto3,enum=downloader.DownloaderRequest_RequestType" json:"type,omitempty"`
-	// The hashes of the blocks we want to download.
-	Hashes               [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
-	PeerHash             []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
-	BlockHash            []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	// The hashes of the blocks we want to download, or of the trie nodes of a
+	// TRIENODES request, or of the epoch blocks of a SHARDSTATES request.
+	Hashes    [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	PeerHash  []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
+	BlockHash []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	// The number of the first header and the number of headers of a HEADERS request.
+	StartNumber          uint64   `protobuf:"varint,5,opt,name=startNumber,proto3" json:"startNumber,omitempty"`
+	Size                 uint32   `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -153,15 +169,31 @@ func (m *DownloaderRequest) GetBlockHash() []byte {
 	return nil
 }
 
+func (m *DownloaderRequest) GetStartNumber() uint64 {
+	if m != nil {
+		return m.StartNumber
+	}
+	return 0
+}
+
+func (m *DownloaderRequest) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
 // DownloaderResponse is the generic response of DownloaderRequest.
 type DownloaderResponse struct {
 	// payload of Block.
 	Payload [][]byte `protobuf:"bytes,1,rep,name=payload,proto3" json:"payload,omitempty"`
 	// response of registration request
-	Type                 DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                                `json:"-"`
-	XXX_unrecognized     []byte                                  `json:"-"`
-	XXX_sizecache        int32                                   `json:"-"`
+	Type DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
+	// number of the current block of the peer, set in HEADERS responses.
+	BlockNumber          uint64   `protobuf:"varint,3,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DownloaderResponse) Reset()         { *m = DownloaderResponse{} }
@@ -203,6 +235,13 @@ func (m *DownloaderResponse) GetType() DownloaderResponse_RegisterResponseType {
 	return DownloaderResponse_SUCCESS
 }
 
+func (m *DownloaderResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("downloader.DownloaderRequest_RequestType", DownloaderRequest_RequestType_name, DownloaderRequest_RequestType_value)
 	proto.RegisterEnum("downloader.DownloaderResponse_RegisterResponseType", DownloaderResponse_RegisterResponseType_name, DownloaderResponse_RegisterResponseType_value)
@@ -213,29 +252,35 @@ func init() {
 func init() { proto.RegisterFile("downloader.proto", fileDescriptor_6a99ec95c7ab1ff1) }
 
 var fileDescriptor_6a99ec95c7ab1ff1 = []byte{
-	// 337 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcf, 0x4e, 0xc2, 0x40,
-	0x10, 0xc6, 0xd9, 0x52, 0xfe, 0x0d, 0x44, 0xd7, 0xd1, 0x98, 0x86, 0xa8, 0x21, 0x3d, 0xe1, 0xa5,
-	0x07, 0x38, 0x79, 0xf0, 0x80, 0x65, 0x85, 0x06, 0x2c, 0x71, 0x5b, 0x24, 0x1e, 0x8b, 0x6c, 0xc4,
-	0x48, 0x68, 0xed, 0x96, 0x98, 0xbe, 0x81, 0xcf, 0xe3, 0x13, 0x9a, 0x96, 0x3f, 0x25, 0x51, 0x39,
-	0x35, 0xbf, 0x6f, 0xba, 0x33, 0xf3, 0x7d, 0xbb, 0x40, 0x67, 0xfe, 0xe7, 0x72, 0xe1, 0x7b, 0x33,
-	0x11, 0x1a, 0x41, 0xe8, 0x47, 0x3e, 0x42, 0xa6, 0xe8, 0x5f, 0x0a, 0x9c, 0x74, 0x77, 0xc8, 0xc5,
-	0xc7, 0x4a, 0xc8, 0x08, 0x6f, 0x41, 0x8d, 0xe2, 0x40, 0x68, 0xa4, 0x41, 0x9a, 0x47, 0xad, 0x6b,
-	0x63, 0xaf, 0xc5, 0xaf, 0x9f, 0x8d, 0xcd, 0xd7, 0x8d, 0x03, 0xc1, 0xd3, 0x63, 0x78, 0x0e, 0xc5,
-	0xb9, 0x27, 0xe7, 0x42, 0x6a, 0x4a, 0x23, 0xdf, 0xac, 0xf1, 0x0d, 0x61, 0x1d, 0xca, 0x81, 0x10,
-	0x61, 0xdf, 0x93, 0x73, 0x2d, 0xdf, 0x20, 0xcd, 0x1a, 0xdf, 0x31, 0x5e, 0x40, 0x65, 0xba, 0xf0,
-	0x5f, 0xde, 0xd3, 0xa2, 0x9a, 0x16, 0x33, 0x41, 0x9f, 0x42, 0x75, 0x6f, 0x0c, 0x02, 0x14, 0xfb,
-	0xac, 0xd3, 0x65, 0x9c, 0xe6, 0xb0, 0x02, 0x85, 0xbb, 0xe1, 0xc8, 0x1c, 0x50, 0x82, 0x35, 0x28,
-	0xdb, 0x6c, 0xb2, 0x26, 0x25, 0x21, 0xce, 0x7a, 0x96, 0xe3, 0x32, 0x4e, 0xf3, 0x78, 0x0a, 0xc7,
-	0x5b, 0x72, 0xad, 0x07, 0x36, 0x1a, 0xbb, 0x54, 0xc5, 0x2a, 0x94, 0xc6, 0xf6, 0xc0, 0x1e, 0x4d,
-	0x6c, 0x5a, 0xd0, 0xbf, 0x09, 0xe0, 0xbe, 0x3b, 0x19, 0xf8, 0x4b, 0x29, 0x50, 0x83, 0x52, 0xe0,
-	0xc5, 0x89, 0xa8, 0x91, 0xd4, 0xcd, 0x16, 0xb1, 0xb7, 0x49, 0x49, 0x49, 0x53, 0x6a, 0xff, 0x97,
-	0xd2, 0xba, 0x8f, 0xc1, 0xc5, 0xeb, 0x9b, 0x8c, 0x32, 0x21, 0xcb, 0x4b, 0xbf, 0x81, 0xb3, 0xbf,
-	0xaa, 0xc9, 0x7a, 0xce, 0xd8, 0x34, 0x99, 0xe3, 0xd0, 0x1c, 0x96, 0x41, 0xbd, 0xef, 0x58, 0x43,
-	0x4a, 0x12, 0xf7, 0x96, 0xed, 0x3c, 0xdb, 0x26, 0x55, 0x5a, 0x4f, 0x00, 0xd9, 0x2c, 0xec, 0x43,
-	0xe1, 0x71, 0x25, 0xc2, 0x18, 0x2f, 0x0f, 0x5e, 0x59, 0xfd, 0xea, 0xf0, 0xae, 0x7a, 0x6e, 0x5a,
-	0x4c, 0x9f, 0x4a, 0xfb, 0x27, 0x00, 0x00, 0xff, 0xff, 0xf9, 0xfb, 0x70, 0x9d, 0x3e, 0x02, 0x00,
-	0x00,
+	// 443 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x93, 0xc1, 0x6e, 0xd3, 0x30,
+	0x1c, 0xc6, 0xeb, 0x34, 0x4d, 0xdb, 0x7f, 0x3a, 0x6a, 0xfe, 0x20, 0x14, 0x4d, 0x80, 0xa2, 0x9c,
+	0xc2, 0xa5, 0x42, 0xdb, 0x89, 0x03, 0x87, 0x2e, 0x35, 0x6b, 0xb4, 0x91, 0x82, 0xed, 0x6a, 0xe2,
+	0x98, 0x6c, 0x16, 0xad, 0x18, 0x4b, 0x88, 0x53, 0xa1, 0xf2, 0x2e, 0xbc, 0x01, 0x8f, 0xc3, 0xd3,
+	0x70, 0x42, 0x71, 0xba, 0x35, 0x12, 0xb0, 0x0b, 0xa7, 0xe4, 0xf7, 0x39, 0xfe, 0xf4, 0xf7, 0xf7,
+	0xc5, 0x40, 0xaf, 0xf2, 0xaf, 0x37, 0xd7, 0x79, 0x7a, 0xa5, 0xca, 0x49, 0x51, 0xe6, 0x55, 0x8e,
+	0xb0, 0x57, 0x82, 0x5f, 0x16, 0x3c, 0x9c, 0xdd, 0x21, 0x57, 0x5f, 0x36, 0x4a, 0x57, 0xf8, 0x1a,
+	0xec, 0x6a, 0x5b, 0x28, 0x8f, 0xf8, 0x24, 0x7c, 0x70, 0xf4, 0x62, 0xd2, 0xb2, 0xf8, 0xe3, 0xe3,
+	0xc9, 0xee, 0x29, 0xb7, 0x85, 0xe2, 0x66, 0x1b, 0x3e, 0x01, 0x67, 0x95, 0xea, 0x95, 0xd2, 0x9e,
+	0xe5, 0x77, 0xc3, 0x11, 0xdf, 0x11, 0x1e, 0xc2, 0xa0, 0x50, 0xaa, 0x9c, 0xa7, 0x7a, 0xe5, 0x75,
+	0x7d, 0x12, 0x8e, 0xf8, 0x1d, 0xe3, 0x53, 0x18, 0x66, 0xd7, 0xf9, 0xe5, 0x27, 0xb3, 0x68, 0x9b,
+	0xc5, 0xbd, 0x80, 0x3e, 0xb8, 0xba, 0x4a, 0xcb, 0x2a, 0xd9, 0x7c, 0xce, 0x54, 0xe9, 0xf5, 0x7c,
+	0x12, 0xda, 0xbc, 0x2d, 0x21, 0x82, 0xad, 0xd7, 0xdf, 0x94, 0xe7, 0xf8, 0x24, 0x3c, 0xe0, 0xe6,
+	0x3d, 0xf8, 0x4e, 0xc0, 0x6d, 0x4d, 0x87, 0x00, 0xce, 0x9c, 0x4d, 0x67, 0x8c, 0xd3, 0x0e, 0x0e,
+	0xa1, 0x77, 0x72, 0xbe, 0x88, 0xce, 0x28, 0xc1, 0x11, 0x0c, 0x12, 0x76, 0xd1, 0x90, 0x55, 0x13,
+	0x67, 0xa7, 0xb1, 0x90, 0x8c, 0xd3, 0x2e, 0x3e, 0x82, 0xf1, 0x2d, 0xc9, 0xf8, 0x2d, 0x5b, 0x2c,
+	0x25, 0xb5, 0xd1, 0x85, 0xfe, 0x32, 0x39, 0x4b, 0x16, 0x17, 0x09, 0xed, 0xd5, 0xd0, 0x98, 0x0a,
+	0xea, 0xe0, 0x01, 0x0c, 0x25, 0x8f, 0x59, 0xb2, 0x98, 0x31, 0x41, 0xfb, 0x8d, 0x57, 0xc4, 0xe2,
+	0x77, 0x52, 0xd0, 0x01, 0x8e, 0xc1, 0x15, 0xf3, 0x29, 0x9f, 0x09, 0x39, 0x95, 0x4c, 0xd0, 0x61,
+	0xf0, 0x93, 0x00, 0xb6, 0xf3, 0xd4, 0x45, 0x7e, 0xa3, 0x15, 0x7a, 0xd0, 0x2f, 0xd2, 0x6d, 0x2d,
+	0x7a, 0xc4, 0xe4, 0x77, 0x8b, 0x78, 0xba, 0xeb, 0xc5, 0x32, 0xbd, 0x1c, 0xff, 0xab, 0x97, 0xc6,
+	0x67, 0xc2, 0xd5, 0xc7, 0xb5, 0xae, 0xf6, 0x42, 0xab, 0x21, 0x1f, 0x5c, 0x13, 0xee, 0x2e, 0xcf,
+	0x6e, 0x93, 0x67, 0x4b, 0x0a, 0x5e, 0xc1, 0xe3, 0xbf, 0xed, 0xaf, 0x8f, 0x2b, 0x96, 0x51, 0xc4,
+	0x84, 0xa0, 0x1d, 0x1c, 0x80, 0xfd, 0x66, 0x1a, 0x9f, 0x53, 0x52, 0x47, 0x1b, 0x27, 0xe2, 0x43,
+	0x12, 0x51, 0xeb, 0xe8, 0x07, 0x01, 0xd8, 0x8f, 0x83, 0x73, 0xe8, 0xbd, 0xdf, 0xa8, 0x72, 0x8b,
+	0xcf, 0xee, 0xfd, 0x8f, 0x0e, 0x9f, 0xdf, 0x7f, 0x9c, 0xa0, 0x83, 0x12, 0xc6, 0x62, 0x93, 0xe9,
+	0xcb, 0x72, 0x9d, 0xa9, 0x93, 0x7a, 0x56, 0xfd, 0xdf, 0x9e, 0x2f, 0x49, 0xe6, 0x98, 0x5b, 0x71,
+	0xfc, 0x7b, 0x00, 0x5c, 0x3d, 0xae, 0xd1, 0x29, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -251,6 +296,9 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type DownloaderClient interface {
 	Query(ctx context.Context, in *DownloaderRequest, opts ...grpc.CallOption) (*DownloaderResponse, error)
+	// SubscribeBlocks streams every block committed by the node, one block per
+	// response, until the subscriber closes the stream.
+	SubscribeBlocks(ctx context.Context, in *DownloaderRequest, opts ...grpc.CallOption) (Downloader_SubscribeBlocksClient, error)
 }
 
 type downloaderClient struct {
@@ -270,9 +318,44 @@ func (c *downloaderClient) Query(ctx context.Context, in *DownloaderRequest, opt
 	return out, nil
 }
 
+func (c *downloaderClient) SubscribeBlocks(ctx context.Context, in *DownloaderRequest, opts ...grpc.CallOption) (Downloader_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Downloader_serviceDesc.Streams[0], "/downloader.Downloader/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &downloaderSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Downloader_SubscribeBlocksClient interface {
+	Recv() (*DownloaderResponse, error)
+	grpc.ClientStream
+}
+
+type downloaderSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *downloaderSubscribeBlocksClient) Recv() (*DownloaderResponse, error) {
+	m := new(DownloaderResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DownloaderServer is the server API for Downloader service.
 type DownloaderServer interface {
 	Query(context.Context, *DownloaderRequest) (*DownloaderResponse, error)
+	// SubscribeBlocks streams every block committed by the node, one block per
+	// response, until the subscriber closes the stream.
+	SubscribeBlocks(*DownloaderRequest, Downloader_SubscribeBlocksServer) error
 }
 
 func RegisterDownloaderServer(s *grpc.Server, srv DownloaderServer) {
@@ -297,6 +380,27 @@ func _Downloader_Query_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Downloader_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloaderRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DownloaderServer).SubscribeBlocks(m, &downloaderSubscribeBlocksServer{stream})
+}
+
+type Downloader_SubscribeBlocksServer interface {
+	Send(*DownloaderResponse) error
+	grpc.ServerStream
+}
+
+type downloaderSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *downloaderSubscribeBlocksServer) Send(m *DownloaderResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Downloader_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "downloader.Downloader",
 	HandlerType: (*DownloaderServer)(nil),
@@ -306,6 +410,12 @@ var _Downloader_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Downloader_Query_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _Downloader_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "downloader.proto",
 }
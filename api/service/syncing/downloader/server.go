@@ -4,19 +4,32 @@ import (
 	"context"
 	"log"
 	"net"
+	"sync"
+	"time"
 
 	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 // Constants for downloader server.
 const (
 	DefaultDownloadPort = "6666"
+	MaxBlockSubscribers = 10 // Maximum number of peers subscribed to the committed blocks
+	BlockStreamBuffer   = 16 // Number of blocks queued for a subscriber before it is dropped as too slow
+
+	MaxSubscriptionDuration = 10 * time.Minute // Time after which a subscription is closed, the peer subscribes again if still out of sync
+	SubscriptionInterval    = 10 * time.Second // Minimum time between two subscriptions of a peer
 )
 
 // Server is the Server struct for downloader package.
 type Server struct {
 	downloadInterface DownloadInterface
+	subscribers       map[chan []byte]string // remote host of each subscription
+	lastSubscriptions map[string]time.Time   // time of the last subscription of each remote host
+	subscribersMux    sync.Mutex
 }
 
 // Query returns the feature at the given point.
@@ -28,6 +41,108 @@ func (s *Server) Query(ctx context.Context, request *pb.DownloaderRequest) (*pb.
 	return response, nil
 }
 
+// SubscribeBlocks streams the blocks broadcast by BroadcastBlock to a syncing
+// peer until it closes the stream, or for at most MaxSubscriptionDuration so
+// that a peer which is back in sync without closing the stream doesn't keep its
+// place. The stream follows the flow control of grpc: the blocks not sent yet
+// are queued, and the subscriber is dropped when BlockStreamBuffer blocks are
+// queued. A remote host has one subscription at a time and subscribes at most
+// once every SubscriptionInterval. The peer hash of the request is chosen by the
+// client, so it doesn't identify the subscriber.
+func (s *Server) SubscribeBlocks(request *pb.DownloaderRequest, stream pb.Downloader_SubscribeBlocksServer) error {
+	blocks := make(chan []byte, BlockStreamBuffer)
+	if err := s.subscribe(blocks, remoteHost(stream.Context()), time.Now()); err != nil {
+		return err
+	}
+	defer s.unsubscribe(blocks)
+	ctx, cancel := context.WithTimeout(stream.Context(), MaxSubscriptionDuration)
+	defer cancel()
+	for {
+		select {
+		case block, ok := <-blocks:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "block stream too slow")
+			}
+			if err := stream.Send(&pb.DownloaderResponse{Payload: [][]byte{block}}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			if stream.Context().Err() == nil {
+				return status.Error(codes.DeadlineExceeded, "block subscription expired")
+			}
+			return nil
+		}
+	}
+}
+
+// remoteHost returns the host of the remote address of a grpc call, without its
+// port.
+func remoteHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// subscribe registers the queue of a subscription of the given remote host,
+// unless the subscriber limit is reached or the host is subscribed or
+// subscribed too recently.
+func (s *Server) subscribe(blocks chan []byte, subscriber string, now time.Time) error {
+	s.subscribersMux.Lock()
+	defer s.subscribersMux.Unlock()
+	if len(s.subscribers) >= MaxBlockSubscribers {
+		return status.Error(codes.ResourceExhausted, "too many block subscribers")
+	}
+	for _, other := range s.subscribers {
+		if other == subscriber {
+			return status.Error(codes.AlreadyExists, "already subscribed")
+		}
+	}
+	if last, ok := s.lastSubscriptions[subscriber]; ok && now.Sub(last) < SubscriptionInterval {
+		return status.Error(codes.Unavailable, "subscribing too often")
+	}
+	for p, last := range s.lastSubscriptions {
+		if now.Sub(last) >= SubscriptionInterval {
+			delete(s.lastSubscriptions, p)
+		}
+	}
+	s.subscribers[blocks] = subscriber
+	s.lastSubscriptions[subscriber] = now
+	return nil
+}
+
+func (s *Server) unsubscribe(blocks chan []byte) {
+	s.subscribersMux.Lock()
+	defer s.subscribersMux.Unlock()
+	delete(s.subscribers, blocks)
+}
+
+// BroadcastBlock sends a RLP encoded block to the subscribers. A subscriber
+// whose queue is full is dropped rather than holding up the others.
+func (s *Server) BroadcastBlock(block []byte) {
+	s.subscribersMux.Lock()
+	defer s.subscribersMux.Unlock()
+	for blocks := range s.subscribers {
+		select {
+		case blocks <- block:
+		default:
+			close(blocks)
+			delete(s.subscribers, blocks)
+		}
+	}
+}
+
+// SubscriberCount returns the number of peers subscribed to the blocks.
+func (s *Server) SubscriberCount() int {
+	s.subscribersMux.Lock()
+	defer s.subscribersMux.Unlock()
+	return len(s.subscribers)
+}
+
 // Start starts the Server on given ip and port.
 func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 	// TODO(minhdoan): Currently not using ip. Fix it later.
@@ -45,6 +160,10 @@ func (s *Server) Start(ip, port string) (*grpc.Server, error) {
 
 // NewServer creates new Server which implements DownloadInterface.
 func NewServer(dlInterface DownloadInterface) *Server {
-	s := &Server{downloadInterface: dlInterface}
+	s := &Server{
+		downloadInterface: dlInterface,
+		subscribers:       make(map[chan []byte]string),
+		lastSubscriptions: make(map[string]time.Time),
+	}
 	return s
 }
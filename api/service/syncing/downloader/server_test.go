@@ -0,0 +1,87 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+func TestBroadcastBlock(t *testing.T) {
+	s := NewServer(nil)
+	now := time.Now()
+	fast, slow := make(chan []byte, BlockStreamBuffer), make(chan []byte, BlockStreamBuffer)
+	if s.subscribe(fast, "10.0.0.1", now) != nil || s.subscribe(slow, "10.0.0.2", now) != nil {
+		t.Fatal("unable to subscribe")
+	}
+	for i := 0; i < BlockStreamBuffer; i++ {
+		s.BroadcastBlock([]byte{byte(i)})
+		<-fast
+	}
+	if s.SubscriberCount() != 2 {
+		t.Fatalf("expected 2 subscribers, got %d", s.SubscriberCount())
+	}
+
+	// The slow subscriber has a full queue, it is dropped at the next block.
+	s.BroadcastBlock([]byte{0xff})
+	if s.SubscriberCount() != 1 {
+		t.Fatalf("expected the slow subscriber to be dropped, got %d subscribers", s.SubscriberCount())
+	}
+	if block := <-fast; len(block) != 1 || block[0] != 0xff {
+		t.Errorf("unexpected block %x", block)
+	}
+	for range slow {
+	}
+
+	s.unsubscribe(fast)
+	for i := 0; i < MaxBlockSubscribers; i++ {
+		if err := s.subscribe(make(chan []byte, BlockStreamBuffer), fmt.Sprintf("10.0.1.%d", i), now); err != nil {
+			t.Fatalf("subscription %d refused: %v", i, err)
+		}
+	}
+	if s.subscribe(make(chan []byte, BlockStreamBuffer), "10.0.2.1", now) == nil {
+		t.Error("expected subscriptions over the limit to be refused")
+	}
+}
+
+func TestSubscriptionRateLimit(t *testing.T) {
+	s := NewServer(nil)
+	now := time.Now()
+	blocks := make(chan []byte, BlockStreamBuffer)
+	if err := s.subscribe(blocks, "10.0.0.1", now); err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	if s.subscribe(make(chan []byte, BlockStreamBuffer), "10.0.0.1", now.Add(SubscriptionInterval)) == nil {
+		t.Error("expected a second subscription of the peer to be refused")
+	}
+	s.unsubscribe(blocks)
+	if s.subscribe(blocks, "10.0.0.1", now.Add(SubscriptionInterval/2)) == nil {
+		t.Error("expected a subscription within the interval to be refused")
+	}
+	if err := s.subscribe(blocks, "10.0.0.1", now.Add(SubscriptionInterval)); err != nil {
+		t.Errorf("subscription after the interval refused: %v", err)
+	}
+}
+
+func TestSubscriberHost(t *testing.T) {
+	call := func(port int) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: port}})
+	}
+	if host := remoteHost(call(9000)); host != "10.0.0.1" {
+		t.Fatalf("unexpected host %q", host)
+	}
+
+	// The calls of a host from other ports, whatever their peer hash, are the
+	// same subscriber.
+	s := NewServer(nil)
+	now := time.Now()
+	if err := s.subscribe(make(chan []byte, BlockStreamBuffer), remoteHost(call(9000)), now); err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	if s.subscribe(make(chan []byte, BlockStreamBuffer), remoteHost(call(9001)), now) == nil {
+		t.Error("expected a second subscription of the host to be refused")
+	}
+}
@@ -8,4 +8,14 @@ var (
 	ErrRegistrationFail             = errors.New("[SYNC]: registration failed")
 	ErrGetBlock                     = errors.New("[SYNC]: get block failed")
 	ErrGetBlockHash                 = errors.New("[SYNC]: get blockhash failed")
+	ErrGetHeaders                   = errors.New("[SYNC]: get headers failed")
+	ErrNoSyncPeers                  = errors.New("[SYNC]: no peer to download from")
+	ErrBrokenHeaderChain            = errors.New("[SYNC]: headers are not linked")
+	ErrInvalidBlock                 = errors.New("[SYNC]: block does not match its header")
+	ErrUndecodable                  = errors.New("[SYNC]: undecodable response")
+	ErrGetTrieNodes                 = errors.New("[SYNC]: get trie nodes failed")
+	ErrGetReceipts                  = errors.New("[SYNC]: get receipts failed")
+	ErrInvalidReceipts              = errors.New("[SYNC]: receipts do not match their block")
+	ErrUnrequestedTrieNode          = errors.New("[SYNC]: trie node not requested")
+	ErrWrongShard                   = errors.New("[SYNC]: block of another shard")
 )
@@ -0,0 +1,173 @@
+package syncing
+
+import (
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Status is the progress of the state syncing.
+type Status struct {
+	Syncing       bool          // Whether the node is downloading missing blocks
+	StartingBlock uint64        // Block number the sync started from
+	CurrentBlock  uint64        // Current block number of the chain
+	HighestBlock  uint64        // Block number the sync is heading to
+	ETA           time.Duration // Estimated time to reach the highest block, 0 if unknown
+}
+
+// Status returns the progress of the sync of the given chain.
+func (ss *StateSync) Status(bc *core.BlockChain) Status {
+	current := bc.CurrentBlock().NumberU64()
+	ss.statusMux.Lock()
+	defer ss.statusMux.Unlock()
+	status := Status{
+		StartingBlock: ss.startBlock,
+		CurrentBlock:  current,
+		HighestBlock:  ss.target,
+	}
+	if ss.target <= current {
+		status.StartingBlock = current
+		status.HighestBlock = current
+		return status
+	}
+	status.Syncing = true
+	status.ETA = estimateETA(ss.startBlock, current, ss.target, time.Since(ss.startTime))
+	return status
+}
+
+// estimateETA estimates the time to reach the highest block at the rate the
+// blocks were downloaded so far, or returns 0 if no block was downloaded yet.
+func estimateETA(start, current, highest uint64, elapsed time.Duration) time.Duration {
+	if current <= start || highest <= current {
+		return 0
+	}
+	return time.Duration(float64(elapsed) / float64(current-start) * float64(highest-current))
+}
+
+// startProgress records the target of a download from the current block of the
+// chain. The starting block is kept while the previous target is not reached,
+// so that the ETA is estimated over the whole download.
+func (ss *StateSync) startProgress(current, target uint64) {
+	ss.statusMux.Lock()
+	defer ss.statusMux.Unlock()
+	if ss.startTime.IsZero() || ss.target <= current {
+		ss.startBlock, ss.startTime = current, time.Now()
+	}
+	ss.target = target
+}
+
+// resumeProgress loads the progress of an interrupted download from the chain
+// database: the peers of the interrupted sync missing from the sync config are
+// connected again, and the block number the download was heading to is
+// returned.
+func (ss *StateSync) resumeProgress(db ethdb.Database) uint64 {
+	ss.progressMux.Lock()
+	defer ss.progressMux.Unlock()
+	ss.progress = rawdb.ReadSyncProgress(db)
+	if ss.progress == nil {
+		ss.progress = &rawdb.SyncProgress{}
+		return 0
+	}
+	known := make(map[string]bool)
+	for _, peerConfig := range ss.syncConfig.peers {
+		known[net.JoinHostPort(peerConfig.ip, peerConfig.port)] = true
+	}
+	for _, address := range ss.progress.Peers {
+		if known[address] {
+			continue
+		}
+		ip, port, err := net.SplitHostPort(address)
+		if err != nil {
+			continue
+		}
+		known[address] = true
+		peerConfig := &SyncPeerConfig{ip: ip, port: port, client: downloader.ClientSetup(ip, port)}
+		ss.syncConfig.peers = append(ss.syncConfig.peers, peerConfig)
+		ss.peerNumber++
+	}
+	utils.GetLogInstance().Info("[SYNC] resuming interrupted sync", "target", ss.progress.Target, "pending", len(ss.progress.Pending), "peers", len(ss.syncConfig.peers))
+	return ss.progress.Target
+}
+
+// saveProgress stores the progress of the download with the current peer set.
+// It is called with progressMux held.
+func (ss *StateSync) saveProgress(db ethdb.Database) {
+	ss.progress.Peers = ss.progress.Peers[:0]
+	for _, peerConfig := range ss.syncConfig.peers {
+		if peerConfig.client != nil && !peerConfig.isDisconnected() {
+			ss.progress.Peers = append(ss.progress.Peers, net.JoinHostPort(peerConfig.ip, peerConfig.port))
+		}
+	}
+	rawdb.WriteSyncProgress(db, ss.progress)
+}
+
+// setProgressTarget stores the block number the download is heading to.
+func (ss *StateSync) setProgressTarget(db ethdb.Database, target uint64) {
+	ss.progressMux.Lock()
+	defer ss.progressMux.Unlock()
+	ss.progress.Target = target
+	ss.saveProgress(db)
+}
+
+// addPendingBlocks stores verified blocks not inserted in the chain yet, so
+// that they are not downloaded again if the node restarts before inserting
+// them.
+func (ss *StateSync) addPendingBlocks(db ethdb.Database, blocks []*types.Block) {
+	ss.progressMux.Lock()
+	defer ss.progressMux.Unlock()
+	batch := db.NewBatch()
+	for _, block := range blocks {
+		rawdb.WriteBlock(batch, block)
+		ss.progress.Pending = append(ss.progress.Pending, block.Hash())
+	}
+	if err := batch.Write(); err != nil {
+		utils.GetLogInstance().Warn("[SYNC] unable to store downloaded blocks", "error", err)
+		return
+	}
+	ss.saveProgress(db)
+}
+
+// readPendingBlocks returns the blocks of the headers stored by an interrupted
+// download, or nil if some of them are missing.
+func readPendingBlocks(db ethdb.Database, headers []*types.Header) []*types.Block {
+	blocks := make([]*types.Block, len(headers))
+	for i, header := range headers {
+		if blocks[i] = rawdb.ReadBlock(db, header.Hash(), header.Number.Uint64()); blocks[i] == nil {
+			return nil
+		}
+	}
+	return blocks
+}
+
+// prunePendingBlocks forgets the pending blocks inserted in the chain. When the
+// download is done, the pending blocks which did not make it to the canonical
+// chain are deleted along with the progress.
+func (ss *StateSync) prunePendingBlocks(db ethdb.Database, done bool) {
+	ss.progressMux.Lock()
+	defer ss.progressMux.Unlock()
+	pending := ss.progress.Pending[:0]
+	for _, hash := range ss.progress.Pending {
+		number := rawdb.ReadHeaderNumber(db, hash)
+		if number == nil || rawdb.ReadCanonicalHash(db, *number) == hash {
+			continue
+		}
+		if done {
+			rawdb.DeleteBlock(db, hash, *number)
+			continue
+		}
+		pending = append(pending, hash)
+	}
+	if done {
+		ss.progress = &rawdb.SyncProgress{}
+		rawdb.DeleteSyncProgress(db)
+		return
+	}
+	ss.progress.Pending = pending
+	ss.saveProgress(db)
+}
@@ -0,0 +1,176 @@
+package syncing
+
+import (
+	"time"
+
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Reputation parameters of the syncing peers.
+const (
+	MaxPeerFailures    = 3                // Consecutive failed requests after which a peer is banned
+	PeerBanDuration    = 30 * time.Second // Duration of the first ban of a peer, doubled at each new ban
+	MaxPeerBanDuration = 10 * time.Minute // Maximum duration of a ban
+	initialPeerLatency = time.Second      // Latency assumed for a peer not measured yet
+	latencyWeight      = 0.2              // Weight of a new measure in the average latency of a peer
+)
+
+// peerReputation tracks how well a peer serves the sync requests. It is guarded
+// by the mutex of the SyncPeerConfig holding it.
+type peerReputation struct {
+	requests            int
+	failures            int
+	consecutiveFailures int
+	penalties           int           // number of invalid responses
+	latency             time.Duration // moving average of the latency of the successful requests
+	inFlight            int           // number of requests in progress
+	bans                int
+	bannedUntil         time.Time
+	disconnected        bool // whether the peer served undecodable or wrongly signed data
+}
+
+// score rates a peer, higher is better: its success rate, lowered by its
+// penalties, over its average latency. The requests in progress share the
+// score so that concurrent requests are spread over the best peers.
+func (rep *peerReputation) score() float64 {
+	latency := rep.latency
+	if latency == 0 {
+		latency = initialPeerLatency
+	}
+	successRate := float64(rep.requests-rep.failures+1) / float64(rep.requests+2)
+	return successRate / float64(1+rep.penalties) / latency.Seconds() / float64(1+rep.inFlight)
+}
+
+func (rep *peerReputation) available(now time.Time) bool {
+	return !rep.disconnected && !now.Before(rep.bannedUntil)
+}
+
+func (rep *peerReputation) recordSuccess(latency time.Duration) {
+	rep.requests++
+	rep.consecutiveFailures = 0
+	if rep.latency == 0 {
+		rep.latency = latency
+	} else {
+		rep.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(rep.latency))
+	}
+}
+
+// recordFailure records a request without response, banning the peer after
+// MaxPeerFailures consecutive failures.
+func (rep *peerReputation) recordFailure(now time.Time) bool {
+	rep.requests++
+	rep.failures++
+	rep.consecutiveFailures++
+	if rep.consecutiveFailures < MaxPeerFailures {
+		return false
+	}
+	rep.ban(now)
+	return true
+}
+
+// ban excludes the peer from the sync for PeerBanDuration, doubled at each new
+// ban up to MaxPeerBanDuration.
+func (rep *peerReputation) ban(now time.Time) time.Duration {
+	duration := MaxPeerBanDuration
+	if rep.bans < 16 && PeerBanDuration<<uint(rep.bans) < MaxPeerBanDuration {
+		duration = PeerBanDuration << uint(rep.bans)
+	}
+	rep.bans++
+	rep.consecutiveFailures = 0
+	rep.bannedUntil = now.Add(duration)
+	return duration
+}
+
+// track runs a request to the peer and records its latency and outcome.
+func (peerConfig *SyncPeerConfig) track(request func() error) error {
+	peerConfig.mux.Lock()
+	peerConfig.reputation.inFlight++
+	peerConfig.mux.Unlock()
+
+	start := time.Now()
+	err := request()
+
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	peerConfig.reputation.inFlight--
+	if err == nil {
+		peerConfig.reputation.recordSuccess(time.Since(start))
+	} else if peerConfig.reputation.recordFailure(time.Now()) {
+		utils.GetLogInstance().Info("[SYNC] peer banned after failed requests", "ip", peerConfig.ip, "port", peerConfig.port, "until", peerConfig.reputation.bannedUntil)
+	}
+	return err
+}
+
+// penalize records an invalid response of the peer, such as missing blocks or
+// headers not following the requested block, and bans the peer.
+func (peerConfig *SyncPeerConfig) penalize() {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	peerConfig.reputation.penalties++
+	duration := peerConfig.reputation.ban(time.Now())
+	utils.GetLogInstance().Warn("[SYNC] peer banned for an invalid response", "ip", peerConfig.ip, "port", peerConfig.port, "duration", duration)
+}
+
+// checkWithheld penalizes a peer which served no data for the block with the
+// given number although it claims to have it, so it doesn't keep being picked.
+func (peerConfig *SyncPeerConfig) checkWithheld(number uint64) {
+	peerConfig.mux.Lock()
+	withheld := peerConfig.height >= number
+	peerConfig.mux.Unlock()
+	if withheld {
+		peerConfig.penalize()
+	}
+}
+
+// disconnect closes the connection to a peer which served undecodable or
+// wrongly signed data. The peer is not asked for data again.
+func (peerConfig *SyncPeerConfig) disconnect() {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	if peerConfig.reputation.disconnected {
+		return
+	}
+	peerConfig.reputation.disconnected = true
+	if peerConfig.client != nil {
+		peerConfig.client.Close()
+	}
+	utils.GetLogInstance().Warn("[SYNC] peer disconnected for serving invalid data", "ip", peerConfig.ip, "port", peerConfig.port)
+}
+
+func (peerConfig *SyncPeerConfig) isDisconnected() bool {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	return peerConfig.reputation.disconnected
+}
+
+// canServe returns whether a peer can be asked for the block with the given number.
+func (peerConfig *SyncPeerConfig) canServe(number uint64, now time.Time) bool {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	return peerConfig.client != nil && peerConfig.reputation.available(now) && peerConfig.height >= number
+}
+
+func (peerConfig *SyncPeerConfig) score() float64 {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	return peerConfig.reputation.score()
+}
+
+// pickPeer returns the best scoring peer which can serve the block with the
+// given number, or nil if there is none.
+func (ss *StateSync) pickPeer(number uint64) *SyncPeerConfig {
+	var (
+		best      *SyncPeerConfig
+		bestScore float64
+		now       = time.Now()
+	)
+	for _, peerConfig := range ss.syncConfig.peers {
+		if !peerConfig.canServe(number, now) {
+			continue
+		}
+		if score := peerConfig.score(); best == nil || score > bestScore {
+			best, bestScore = peerConfig, score
+		}
+	}
+	return best
+}
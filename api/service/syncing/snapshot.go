@@ -0,0 +1,278 @@
+package syncing
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Parameters of the snapshot sync.
+const (
+	// SnapshotPivotDistance is the number of blocks below the target of the sync
+	// the state is downloaded at, so that the peers still hold it in memory.
+	SnapshotPivotDistance = 64
+	// MinSnapshotDistance is the number of missing blocks from which a new node
+	// downloads the state instead of replaying the blocks.
+	MinSnapshotDistance = 1024
+)
+
+// GetTrieNodes gets the state trie nodes and contract codes with the given
+// hashes from the peer. A response without any node counts as a failed request,
+// the peer does not hold the state.
+func (peerConfig *SyncPeerConfig) GetTrieNodes(hashes [][]byte) ([][]byte, error) {
+	if peerConfig.client == nil {
+		return nil, ErrSyncPeerConfigClientNotReady
+	}
+	var response *pb.DownloaderResponse
+	err := peerConfig.track(func() error {
+		if response = peerConfig.client.GetTrieNodes(hashes); response == nil || len(response.Payload) == 0 {
+			return ErrGetTrieNodes
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Payload, nil
+}
+
+// GetReceipts gets the receipts of the blocks with the given hashes from the peer.
+func (peerConfig *SyncPeerConfig) GetReceipts(hashes [][]byte) ([]types.Receipts, error) {
+	if peerConfig.client == nil {
+		return nil, ErrSyncPeerConfigClientNotReady
+	}
+	var response *pb.DownloaderResponse
+	err := peerConfig.track(func() error {
+		if response = peerConfig.client.GetReceipts(hashes); response == nil {
+			return ErrGetReceipts
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	receipts := make([]types.Receipts, 0, len(response.Payload))
+	for _, payload := range response.Payload {
+		var blockReceipts types.Receipts
+		if err := rlp.DecodeBytes(payload, &blockReceipts); err != nil {
+			return nil, ErrUndecodable
+		}
+		receipts = append(receipts, blockReceipts)
+	}
+	return receipts, nil
+}
+
+// checkReceipts checks that the receipts are the ones of the blocks.
+func checkReceipts(blocks []*types.Block, receipts []types.Receipts) error {
+	if len(receipts) != len(blocks) {
+		return ErrInvalidReceipts
+	}
+	for i, block := range blocks {
+		if types.DeriveSha(receipts[i]) != block.ReceiptHash() {
+			return ErrInvalidReceipts
+		}
+	}
+	return nil
+}
+
+// getReceipts downloads the receipts of the given verified blocks, in batches of
+// BlockBatch blocks with up to Window requests in flight.
+func (ss *StateSync) getReceipts(blocks []*types.Block) ([]types.Receipts, error) {
+	receipts := make([]types.Receipts, len(blocks))
+	ok := ss.forEachBatch(len(blocks), ss.downloadConfig.blockBatch(), func(start, end int) bool {
+		return ss.getReceiptBatch(blocks[start:end], receipts[start:end])
+	})
+	if !ok {
+		return nil, ErrGetReceipts
+	}
+	return receipts, nil
+}
+
+// getReceiptBatch downloads the receipts of a batch of blocks into receipts,
+// trying other peers on failure. It returns whether the download succeeded.
+func (ss *StateSync) getReceiptBatch(blocks []*types.Block, receipts []types.Receipts) bool {
+	hashes := make([][]byte, len(blocks))
+	for i, block := range blocks {
+		hash := block.Hash()
+		hashes[i] = hash[:]
+	}
+	last := blocks[len(blocks)-1].NumberU64()
+	for attempt := 0; attempt < TimesToFail; attempt++ {
+		peerConfig := ss.pickPeer(last)
+		if peerConfig == nil {
+			return false
+		}
+		downloaded, err := peerConfig.GetReceipts(hashes)
+		if err == ErrUndecodable {
+			peerConfig.disconnect()
+			continue
+		}
+		if err != nil {
+			utils.GetLogInstance().Debug("[SYNC] GetReceipts failed", "number", last, "error", err)
+			continue
+		}
+		if err := checkReceipts(blocks, downloaded); err != nil {
+			peerConfig.penalize()
+			continue
+		}
+		copy(receipts, downloaded)
+		return true
+	}
+	return false
+}
+
+// getTrieNodes downloads the state trie nodes with the given hashes, in batches
+// of MaxTrieNodesPerRequest nodes with up to Window requests in flight, from the
+// peers holding the block with the given number. A node is identified by the
+// hash of its data, so that any node returned is genuine. The nodes not found
+// at the peers are left out.
+func (ss *StateSync) getTrieNodes(number uint64, hashes []common.Hash) map[common.Hash][]byte {
+	var (
+		nodes = make(map[common.Hash][]byte, len(hashes))
+		mux   sync.Mutex
+	)
+	ss.forEachBatch(len(hashes), MaxTrieNodesPerRequest, func(start, end int) bool {
+		requested := make(map[common.Hash]bool, end-start)
+		request := make([][]byte, 0, end-start)
+		for _, hash := range hashes[start:end] {
+			requested[hash] = true
+			request = append(request, hash.Bytes())
+		}
+		for attempt := 0; attempt < TimesToFail; attempt++ {
+			peerConfig := ss.pickPeer(number)
+			if peerConfig == nil {
+				return true
+			}
+			payload, err := peerConfig.GetTrieNodes(request)
+			if err != nil {
+				utils.GetLogInstance().Debug("[SYNC] GetTrieNodes failed", "number", number, "error", err)
+				continue
+			}
+			delivered := make(map[common.Hash][]byte, len(payload))
+			for _, data := range payload {
+				hash := crypto.Keccak256Hash(data)
+				if !requested[hash] {
+					err = ErrUnrequestedTrieNode
+					break
+				}
+				delivered[hash] = data
+			}
+			if err != nil {
+				peerConfig.penalize()
+				continue
+			}
+			mux.Lock()
+			for hash, data := range delivered {
+				nodes[hash] = data
+			}
+			mux.Unlock()
+			return true
+		}
+		return true
+	})
+	return nodes
+}
+
+// syncState downloads the state trie with the given root, along with the
+// storage tries and the codes of the contracts, into the chain database.
+func (ss *StateSync) syncState(db ethdb.Database, number uint64, root common.Hash) error {
+	var (
+		sched    = state.NewStateSync(root, db)
+		batch    = db.NewBatch()
+		retry    []common.Hash // nodes requested but not delivered yet
+		failures int
+		synced   int
+		maxNodes = ss.downloadConfig.window() * MaxTrieNodesPerRequest
+	)
+	for sched.Pending() > 0 || len(retry) > 0 {
+		hashes := retry
+		if len(hashes) < maxNodes {
+			hashes = append(hashes, sched.Missing(maxNodes-len(hashes))...)
+		}
+		nodes := ss.getTrieNodes(number, hashes)
+		if len(nodes) == 0 {
+			if failures++; failures >= TimesToFail {
+				return ErrGetTrieNodes
+			}
+			retry = hashes
+			continue
+		}
+		failures = 0
+		retry = nil
+		for _, hash := range hashes {
+			data, ok := nodes[hash]
+			if !ok {
+				retry = append(retry, hash)
+				continue
+			}
+			if _, _, err := sched.Process([]trie.SyncResult{{Hash: hash, Data: data}}); err != nil {
+				return err
+			}
+		}
+		if _, err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		synced += len(nodes)
+		utils.GetLogInstance().Debug("[SYNC] downloaded state nodes", "synced", synced, "pending", sched.Pending()+len(retry))
+	}
+	return batch.Write()
+}
+
+// syncSnapshot writes the blocks up to pivot to the chain without executing
+// them, downloads the state of pivot and makes pivot the current block of the
+// chain. The blocks written by an interrupted snapshot sync are not downloaded
+// again, nor the state nodes already stored.
+func (ss *StateSync) syncSnapshot(bc *core.BlockChain, pivot uint64) error {
+	db := bc.ChainDb()
+	parent := bc.CurrentBlock().Header()
+	for next := parent.Number.Uint64() + 1; next <= pivot; next++ {
+		header := bc.GetHeaderByNumber(next)
+		if header == nil || !bc.HasBlock(header.Hash(), next) {
+			break
+		}
+		parent = header
+	}
+	utils.GetLogInstance().Info("[SYNC] downloading the state of a recent block", "from", parent.Number, "pivot", pivot)
+	for parent.Number.Uint64() < pivot {
+		size := ss.downloadConfig.headerBatch()
+		if remaining := pivot - parent.Number.Uint64(); remaining < uint64(size) {
+			size = int(remaining)
+		}
+		size = epochBatchSize(bc.HarmonyConfig(), parent.Number.Uint64(), size)
+		headers, err := ss.getHeaders(bc, parent, size)
+		if err != nil {
+			return err
+		}
+		blocks, err := ss.getBlocks(db, headers)
+		if err != nil {
+			return err
+		}
+		receipts, err := ss.getReceipts(blocks)
+		if err != nil {
+			return err
+		}
+		if _, err := bc.InsertSnapshotChain(blocks, receipts); err != nil {
+			return err
+		}
+		parent = headers[len(headers)-1]
+	}
+	if err := ss.syncState(db, pivot, parent.Root); err != nil {
+		return err
+	}
+	return bc.CommitSnapshot(parent.Hash())
+}
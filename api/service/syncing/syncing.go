@@ -2,19 +2,20 @@ package syncing
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
-	"reflect"
 	"sort"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
 
-	"github.com/Workiva/go-datastructures/queue"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
@@ -25,11 +26,13 @@ import (
 
 // Constants for syncing.
 const (
-	ConsensusRatio                        = float64(0.66)
-	SleepTimeAfterNonConsensusBlockHashes = time.Second * 30
-	TimesToFail                           = 5
-	RegistrationNumber                    = 3
-	SyncingPortDifference                 = 3000
+	ConsensusRatio         = float64(0.66)
+	TimesToFail            = 5
+	RegistrationNumber     = 3
+	SyncingPortDifference  = 3000
+	MaxHeadersPerRequest   = 1024 // Maximum number of headers served per request
+	MaxBlocksPerRequest    = 128  // Maximum number of blocks, block receipts or shard states served per request
+	MaxTrieNodesPerRequest = 384  // Maximum number of state trie nodes served per request
 )
 
 // SyncPeerConfig is peer config to sync.
@@ -37,8 +40,10 @@ type SyncPeerConfig struct {
 	ip          string
 	port        string
 	client      *downloader.Client
-	blockHashes [][]byte       // block hashes before node doing sync
-	newBlocks   []*types.Block // blocks after node doing sync
+	height      uint64             // number of the current block of the peer
+	reputation  peerReputation     // how well the peer serves the sync requests
+	newBlocks   []*types.Block     // blocks after node doing sync
+	unsubscribe context.CancelFunc // closes the stream of the blocks committed by the peer, nil if not subscribed
 	mux         sync.Mutex
 }
 
@@ -47,12 +52,6 @@ func (peerConfig *SyncPeerConfig) GetClient() *downloader.Client {
 	return peerConfig.client
 }
 
-// SyncBlockTask is the task struct to sync a specific block.
-type SyncBlockTask struct {
-	index     int
-	blockHash []byte
-}
-
 // SyncConfig contains an array of SyncPeerConfig.
 type SyncConfig struct {
 	peers []*SyncPeerConfig
@@ -63,22 +62,34 @@ func CreateStateSync(ip string, port string) *StateSync {
 	stateSync := &StateSync{}
 	stateSync.selfip = ip
 	stateSync.selfport = port
-	stateSync.commonBlocks = make(map[int]*types.Block)
+	stateSync.downloadConfig = DefaultDownloadConfig
 	stateSync.lastMileBlocks = []*types.Block{}
 	return stateSync
 }
 
 // StateSync is the struct that implements StateSyncInterface.
 type StateSync struct {
-	selfip             string
-	selfport           string
-	peerNumber         int
-	activePeerNumber   int
-	commonBlocks       map[int]*types.Block
-	lastMileBlocks     []*types.Block // last mile blocks to catch up with the consensus
-	syncConfig         *SyncConfig
-	stateSyncTaskQueue *queue.Queue
-	syncMux            sync.Mutex
+	selfip           string
+	selfport         string
+	peerNumber       int
+	activePeerNumber int
+	downloadConfig   DownloadConfig
+	lastMileBlocks   []*types.Block // last mile blocks to catch up with the consensus
+	syncConfig       *SyncConfig
+	syncMux          sync.Mutex
+
+	progress    *rawdb.SyncProgress // download progress kept in the chain database
+	progressMux sync.Mutex
+
+	startBlock uint64 // block number the download started from
+	target     uint64 // block number the download is heading to
+	startTime  time.Time
+	statusMux  sync.Mutex
+}
+
+// SetDownloadConfig sets the batch sizes and the window of the block download.
+func (ss *StateSync) SetDownloadConfig(config DownloadConfig) {
+	ss.downloadConfig = config
 }
 
 // AddLastMileBlock add the lastest a few block into queue for syncing
@@ -91,7 +102,13 @@ func (ss *StateSync) AddLastMileBlock(block *types.Block) {
 // CloseConnections close grpc  connections for state sync clients
 func (ss *StateSync) CloseConnections() {
 	for _, pc := range ss.syncConfig.peers {
-		if pc.client != nil {
+		pc.mux.Lock()
+		if pc.unsubscribe != nil {
+			pc.unsubscribe()
+		}
+		pc.mux.Unlock()
+		// The connections to the disconnected peers are already closed.
+		if pc.client != nil && !pc.isDisconnected() {
 			pc.client.Close()
 		}
 	}
@@ -107,44 +124,12 @@ func GetServicePort(nodePort string) string {
 	return ""
 }
 
-// AddNewBlock will add newly received block into state syncing queue
-func (ss *StateSync) AddNewBlock(peerHash []byte, block *types.Block) {
-	for i, pc := range ss.syncConfig.peers {
-		pid := utils.GetUniqueIDFromIPPort(pc.ip, GetServicePort(pc.port))
-		ph := make([]byte, 4)
-		binary.BigEndian.PutUint32(ph, pid)
-		if bytes.Compare(ph, peerHash) != 0 {
-			continue
-		}
-		pc.mux.Lock()
-		pc.newBlocks = append(pc.newBlocks, block)
-		pc.mux.Unlock()
-		utils.GetLogInstance().Debug("[SYNC] new block received", "total", len(ss.syncConfig.peers[i].newBlocks), "blockHeight", block.NumberU64())
-	}
-}
-
 // CreateTestSyncPeerConfig used for testing.
-func CreateTestSyncPeerConfig(client *downloader.Client, blockHashes [][]byte) *SyncPeerConfig {
+func CreateTestSyncPeerConfig(client *downloader.Client, height uint64) *SyncPeerConfig {
 	return &SyncPeerConfig{
-		client:      client,
-		blockHashes: blockHashes,
-	}
-}
-
-// CompareSyncPeerConfigByblockHashes compares two SyncPeerConfig by blockHashes.
-func CompareSyncPeerConfigByblockHashes(a *SyncPeerConfig, b *SyncPeerConfig) int {
-	if len(a.blockHashes) != len(b.blockHashes) {
-		if len(a.blockHashes) < len(b.blockHashes) {
-			return -1
-		}
-		return 1
-	}
-	for id := range a.blockHashes {
-		if !reflect.DeepEqual(a.blockHashes[id], b.blockHashes[id]) {
-			return bytes.Compare(a.blockHashes[id], b.blockHashes[id])
-		}
+		client: client,
+		height: height,
 	}
-	return 0
 }
 
 // GetBlocks gets blocks by calling grpc request to the corresponding peer.
@@ -203,168 +188,6 @@ func (ss *StateSync) CleanUpNilPeers() {
 	}
 }
 
-// GetHowManyMaxConsensus returns max number of consensus nodes and the first ID of consensus group.
-// Assumption: all peers are sorted by CompareSyncPeerConfigByBlockHashes first.
-func (syncConfig *SyncConfig) GetHowManyMaxConsensus() (int, int) {
-	// As all peers are sorted by their blockHashes, all equal blockHashes should come together and consecutively.
-	curCount := 0
-	curFirstID := -1
-	maxCount := 0
-	maxFirstID := -1
-	for i := range syncConfig.peers {
-		if curFirstID == -1 || CompareSyncPeerConfigByblockHashes(syncConfig.peers[curFirstID], syncConfig.peers[i]) != 0 {
-			curCount = 1
-			curFirstID = i
-		} else {
-			curCount++
-		}
-		if curCount > maxCount {
-			maxCount = curCount
-			maxFirstID = curFirstID
-		}
-	}
-	return maxFirstID, maxCount
-}
-
-// InitForTesting used for testing.
-func (syncConfig *SyncConfig) InitForTesting(client *downloader.Client, blockHashes [][]byte) {
-	for i := range syncConfig.peers {
-		syncConfig.peers[i].blockHashes = blockHashes
-		syncConfig.peers[i].client = client
-	}
-}
-
-// CleanUpPeers cleans up all peers whose blockHashes are not equal to consensus block hashes.
-func (syncConfig *SyncConfig) CleanUpPeers(maxFirstID int) {
-	fixedPeer := syncConfig.peers[maxFirstID]
-	for i := 0; i < len(syncConfig.peers); i++ {
-		if CompareSyncPeerConfigByblockHashes(fixedPeer, syncConfig.peers[i]) != 0 {
-			// TODO: move it into a util delete func.
-			// See tip https://github.com/golang/go/wiki/SliceTricks
-			// Close the client and remove the peer out of the
-			syncConfig.peers[i].client.Close()
-			copy(syncConfig.peers[i:], syncConfig.peers[i+1:])
-			syncConfig.peers[len(syncConfig.peers)-1] = nil
-			syncConfig.peers = syncConfig.peers[:len(syncConfig.peers)-1]
-		}
-	}
-}
-
-// GetBlockHashesConsensusAndCleanUp chesk if all consensus hashes are equal.
-func (ss *StateSync) GetBlockHashesConsensusAndCleanUp() bool {
-	// Sort all peers by the blockHashes.
-	sort.Slice(ss.syncConfig.peers, func(i, j int) bool {
-		return CompareSyncPeerConfigByblockHashes(ss.syncConfig.peers[i], ss.syncConfig.peers[j]) == -1
-	})
-	maxFirstID, maxCount := ss.syncConfig.GetHowManyMaxConsensus()
-	if float64(maxCount) >= ConsensusRatio*float64(ss.activePeerNumber) {
-		ss.syncConfig.CleanUpPeers(maxFirstID)
-		ss.CleanUpNilPeers()
-		return true
-	}
-	return false
-}
-
-// GetConsensusHashes gets all hashes needed to download.
-func (ss *StateSync) GetConsensusHashes(startHash []byte) bool {
-	count := 0
-	for {
-		var wg sync.WaitGroup
-		for id := range ss.syncConfig.peers {
-			if ss.syncConfig.peers[id].client == nil {
-				continue
-			}
-			wg.Add(1)
-			go func(peerConfig *SyncPeerConfig) {
-				defer wg.Done()
-				response := peerConfig.client.GetBlockHashes(startHash)
-				if response == nil {
-					return
-				}
-				peerConfig.blockHashes = response.Payload
-			}(ss.syncConfig.peers[id])
-		}
-		wg.Wait()
-		if ss.GetBlockHashesConsensusAndCleanUp() {
-			break
-		}
-		if count > TimesToFail {
-			utils.GetLogInstance().Info("GetConsensusHashes: reached # of times to failed")
-			return false
-		}
-		count++
-		time.Sleep(SleepTimeAfterNonConsensusBlockHashes)
-	}
-	utils.GetLogInstance().Info("syncing: Finished getting consensus block hashes.")
-	return true
-}
-
-func (ss *StateSync) generateStateSyncTaskQueue(bc *core.BlockChain) {
-	ss.stateSyncTaskQueue = queue.New(0)
-	for _, configPeer := range ss.syncConfig.peers {
-		if configPeer.client != nil {
-			for id, blockHash := range configPeer.blockHashes {
-				ss.stateSyncTaskQueue.Put(SyncBlockTask{index: id, blockHash: blockHash})
-			}
-			break
-		}
-	}
-	utils.GetLogInstance().Info("syncing: Finished generateStateSyncTaskQueue", "length", ss.stateSyncTaskQueue.Len())
-}
-
-// downloadBlocks downloads blocks from state sync task queue.
-func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
-	// Initialize blockchain
-	var wg sync.WaitGroup
-	wg.Add(ss.activePeerNumber)
-	count := 0
-	for i := range ss.syncConfig.peers {
-		if ss.syncConfig.peers[i].client == nil {
-			continue
-		}
-		go func(peerConfig *SyncPeerConfig, stateSyncTaskQueue *queue.Queue, bc *core.BlockChain) {
-			defer wg.Done()
-			for !stateSyncTaskQueue.Empty() {
-				task, err := ss.stateSyncTaskQueue.Poll(1, time.Millisecond)
-				if err == queue.ErrTimeout {
-					utils.GetLogInstance().Debug("[SYNC] ss.stateSyncTaskQueue poll timeout", "error", err)
-					break
-				}
-				syncTask := task[0].(SyncBlockTask)
-				//id := syncTask.index
-				payload, err := peerConfig.GetBlocks([][]byte{syncTask.blockHash})
-				if err != nil {
-					count++
-					utils.GetLogInstance().Debug("[SYNC] GetBlocks failed", "failNumber", count)
-					if count > TimesToFail {
-						break
-					}
-					ss.stateSyncTaskQueue.Put(syncTask)
-					continue
-				}
-
-				var blockObj types.Block
-				// currently only send one block a time
-				err = rlp.DecodeBytes(payload[0], &blockObj)
-				if err != nil {
-					count++
-					utils.GetLogInstance().Debug("[SYNC] downloadBlocks: failed to DecodeBytes from received new block")
-					if count > TimesToFail {
-						break
-					}
-					ss.stateSyncTaskQueue.Put(syncTask)
-					continue
-				}
-				ss.syncMux.Lock()
-				ss.commonBlocks[syncTask.index] = &blockObj
-				ss.syncMux.Unlock()
-			}
-		}(ss.syncConfig.peers[i], ss.stateSyncTaskQueue, bc)
-	}
-	wg.Wait()
-	utils.GetLogInstance().Info("[SYNC] Finished downloadBlocks.")
-}
-
 // CompareBlockByHash compares two block by hash, it will be used in sort the blocks
 func CompareBlockByHash(a *types.Block, b *types.Block) int {
 	ha := a.Hash()
@@ -394,40 +217,38 @@ func GetHowManyMaxConsensus(blocks []*types.Block) (int, int) {
 	return maxFirstID, maxCount
 }
 
-func (ss *StateSync) getMaxConsensusBlockFromParentHash(parentHash common.Hash) *types.Block {
+// getMaxConsensusBlockFromParentHash returns the block following the given
+// parent received from most peers, along with the peers which sent it.
+func (ss *StateSync) getMaxConsensusBlockFromParentHash(parentHash common.Hash) (*types.Block, []*SyncPeerConfig) {
 	candidateBlocks := []*types.Block{}
-	ss.syncMux.Lock()
+	senders := make(map[common.Hash][]*SyncPeerConfig)
 	for id := range ss.syncConfig.peers {
 		peerConfig := ss.syncConfig.peers[id]
+		if peerConfig.isDisconnected() {
+			continue
+		}
+		peerConfig.mux.Lock()
 		for _, block := range peerConfig.newBlocks {
 			ph := block.ParentHash()
 			if bytes.Compare(ph[:], parentHash[:]) == 0 {
 				candidateBlocks = append(candidateBlocks, block)
+				senders[block.Hash()] = append(senders[block.Hash()], peerConfig)
 				break
 			}
 		}
+		peerConfig.mux.Unlock()
 	}
-	ss.syncMux.Unlock()
 	if len(candidateBlocks) == 0 {
-		return nil
+		return nil, nil
 	}
 	// Sort by blockHashes.
 	sort.Slice(candidateBlocks, func(i, j int) bool {
 		return CompareBlockByHash(candidateBlocks[i], candidateBlocks[j]) == -1
 	})
 	maxFirstID, maxCount := GetHowManyMaxConsensus(candidateBlocks)
-	utils.GetLogInstance().Debug("[SYNC] Find block with matching parenthash", "parentHash", parentHash, "hash", candidateBlocks[maxFirstID].Hash(), "maxCount", maxCount)
-	return candidateBlocks[maxFirstID]
-}
-
-func (ss *StateSync) getBlockFromOldBlocksByParentHash(parentHash common.Hash) *types.Block {
-	for _, block := range ss.commonBlocks {
-		ph := block.ParentHash()
-		if bytes.Compare(ph[:], parentHash[:]) == 0 {
-			return block
-		}
-	}
-	return nil
+	block := candidateBlocks[maxFirstID]
+	utils.GetLogInstance().Debug("[SYNC] Find block with matching parenthash", "parentHash", parentHash, "hash", block.Hash(), "maxCount", maxCount)
+	return block, senders[block.Hash()]
 }
 
 func (ss *StateSync) getBlockFromLastMileBlocksByParentHash(parentHash common.Hash) *types.Block {
@@ -440,57 +261,78 @@ func (ss *StateSync) getBlockFromLastMileBlocksByParentHash(parentHash common.Ha
 	return nil
 }
 
-func (ss *StateSync) updateBlockAndStatus(block *types.Block, bc *core.BlockChain, worker *worker.Worker) bool {
+// updateBlockAndStatus verifies the block and inserts it in the chain. A block
+// failing verification is not inserted and the verification error is returned.
+func (ss *StateSync) updateBlockAndStatus(block *types.Block, bc *core.BlockChain, worker *worker.Worker) error {
 	utils.GetLogInstance().Info("[SYNC] Current Block", "blockHex", bc.CurrentBlock().Hash().Hex())
+	if err := verifyBlock(bc, block); err != nil {
+		utils.GetLogInstance().Warn("[SYNC] unverifiable block", "blockHeight", block.NumberU64(), "blockHex", block.Hash().Hex(), "error", err)
+		return err
+	}
 	_, err := bc.InsertChain([]*types.Block{block})
 	if err != nil {
 		utils.GetLogInstance().Debug("Error adding new block to blockchain", "Error", err)
-		return false
+		return err
 	}
 	utils.GetLogInstance().Info("[SYNC] new block added to blockchain", "blockHeight", bc.CurrentBlock().NumberU64(), "blockHex", bc.CurrentBlock().Hash().Hex(), "parentHex", bc.CurrentBlock().ParentHash().Hex())
 	ss.syncMux.Lock()
 	worker.UpdateCurrent()
 	ss.syncMux.Unlock()
-	return true
+	return nil
 }
 
-// generateNewState will construct most recent state from downloaded blocks
-func (ss *StateSync) generateNewState(bc *core.BlockChain, worker *worker.Worker) {
-	// update blocks created before node start sync
-	parentHash := bc.CurrentBlock().Hash()
-	for {
-		block := ss.getBlockFromOldBlocksByParentHash(parentHash)
-		if block == nil {
-			break
+// reportInvalidBlock reports the peers which sent a block failing verification:
+// they are disconnected if the block is not signed by the committee of its
+// epoch, read from a verified shard state, and penalized if the block does not
+// match its header or belongs to another shard. It returns false if the error
+// is not the peers' fault, such as an unknown or unverified committee, in which
+// case the other peers would fail the same way.
+func reportInvalidBlock(peers []*SyncPeerConfig, err error) bool {
+	switch err {
+	case consensus.ErrInvalidSignature, consensus.ErrNotEnoughSignatures:
+		for _, peerConfig := range peers {
+			peerConfig.disconnect()
 		}
-		ok := ss.updateBlockAndStatus(block, bc, worker)
-		if !ok {
-			break
+	case ErrInvalidBlock, ErrWrongShard:
+		for _, peerConfig := range peers {
+			peerConfig.penalize()
 		}
-		parentHash = block.Hash()
+	default:
+		return false
 	}
-	ss.syncMux.Lock()
-	ss.commonBlocks = make(map[int]*types.Block)
-	ss.syncMux.Unlock()
+	return true
+}
 
+// generateNewState will construct most recent state from downloaded blocks.
+// The blocks are inserted in order until one of them cannot be verified. A
+// block which can't be verified locally, whichever peer sent it, aborts the
+// sync round with the verification error.
+func (ss *StateSync) generateNewState(bc *core.BlockChain, worker *worker.Worker) error {
 	// update blocks after node start sync
-	parentHash = bc.CurrentBlock().Hash()
+	parentHash := bc.CurrentBlock().Hash()
+	var unverifiable error
 	for {
-		block := ss.getMaxConsensusBlockFromParentHash(parentHash)
+		block, senders := ss.getMaxConsensusBlockFromParentHash(parentHash)
 		if block == nil {
 			break
 		}
-		ok := ss.updateBlockAndStatus(block, bc, worker)
-		if !ok {
+		if err := ss.updateBlockAndStatus(block, bc, worker); err != nil {
+			if !reportInvalidBlock(senders, err) {
+				utils.GetLogInstance().Warn("[SYNC] unable to verify block", "number", block.NumberU64(), "error", err)
+				unverifiable = err
+			}
 			break
 		}
 		parentHash = block.Hash()
 	}
-	ss.syncMux.Lock()
-	for id := range ss.syncConfig.peers {
-		ss.syncConfig.peers[id].newBlocks = []*types.Block{}
+	for _, peerConfig := range ss.syncConfig.peers {
+		peerConfig.mux.Lock()
+		peerConfig.newBlocks = []*types.Block{}
+		peerConfig.mux.Unlock()
+	}
+	if unverifiable != nil {
+		return unverifiable
 	}
-	ss.syncMux.Unlock()
 
 	// update last mile blocks if any
 	parentHash = bc.CurrentBlock().Hash()
@@ -499,66 +341,106 @@ func (ss *StateSync) generateNewState(bc *core.BlockChain, worker *worker.Worker
 		if block == nil {
 			break
 		}
-		ok := ss.updateBlockAndStatus(block, bc, worker)
-		if !ok {
+		if err := ss.updateBlockAndStatus(block, bc, worker); err != nil {
 			break
 		}
 		parentHash = block.Hash()
 	}
+	return nil
 }
 
-// StartStateSync starts state sync.
-func (ss *StateSync) StartStateSync(startHash []byte, bc *core.BlockChain, worker *worker.Worker) {
-	ss.RegisterNodeInfo()
-	// Gets consensus hashes.
-	if !ss.GetConsensusHashes(startHash) {
-		utils.GetLogInstance().Debug("[SYNC] StartStateSync unable to reach consensus on ss.GetConsensusHashes")
-		return
+// StartStateSync starts state sync: the blocks missing from the chain are
+// downloaded header first, then the blocks received since the sync started are
+// added. It returns an error if the missing blocks could not be downloaded or a
+// received block could not be verified, in which case the node is not in sync.
+func (ss *StateSync) StartStateSync(bc *core.BlockChain, worker *worker.Worker) error {
+	ss.SubscribeBlocks()
+	if err := ss.downloadBlocks(bc, worker); err != nil {
+		utils.GetLogInstance().Warn("[SYNC] StartStateSync unable to download the missing blocks", "error", err)
+		return err
 	}
-	utils.GetLogInstance().Debug("[SYNC] StartStateSync reach consensus on ss.GetConsensusHashes")
-	ss.generateStateSyncTaskQueue(bc)
-	// Download blocks.
-	if ss.stateSyncTaskQueue.Len() > 0 {
-		ss.downloadBlocks(bc)
-	}
-	ss.generateNewState(bc, worker)
+	return ss.generateNewState(bc, worker)
 }
 
-func (peerConfig *SyncPeerConfig) registerToBroadcast(peerHash []byte) error {
-	response := peerConfig.client.Register(peerHash)
-	if response == nil || response.Type == pb.DownloaderResponse_FAIL {
+// subscribeBlocks subscribes to the blocks committed by the peer, which are
+// added to its new blocks until the stream is closed.
+func (peerConfig *SyncPeerConfig) subscribeBlocks(peerHash []byte) error {
+	stream, unsubscribe, err := peerConfig.client.SubscribeBlocks(peerHash)
+	if err != nil {
 		return ErrRegistrationFail
-	} else if response.Type == pb.DownloaderResponse_SUCCESS {
-		return nil
 	}
-	return ErrRegistrationFail
+	peerConfig.mux.Lock()
+	peerConfig.unsubscribe = unsubscribe
+	peerConfig.mux.Unlock()
+	go peerConfig.receiveBlocks(stream)
+	return nil
+}
+
+// receiveBlocks adds the blocks received from the stream to the new blocks of
+// the peer until the stream is closed. A peer streaming undecodable blocks is
+// disconnected.
+func (peerConfig *SyncPeerConfig) receiveBlocks(stream pb.Downloader_SubscribeBlocksClient) {
+	defer func() {
+		peerConfig.mux.Lock()
+		peerConfig.unsubscribe()
+		peerConfig.unsubscribe = nil
+		peerConfig.mux.Unlock()
+	}()
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			utils.GetLogInstance().Debug("[SYNC] block stream closed", "ip", peerConfig.ip, "port", peerConfig.port, "error", err)
+			return
+		}
+		for _, payload := range response.Payload {
+			block := new(types.Block)
+			if err := rlp.DecodeBytes(payload, block); err != nil {
+				peerConfig.disconnect()
+				return
+			}
+			peerConfig.mux.Lock()
+			peerConfig.newBlocks = append(peerConfig.newBlocks, block)
+			peerConfig.mux.Unlock()
+			utils.GetLogInstance().Debug("[SYNC] new block received", "ip", peerConfig.ip, "port", peerConfig.port, "blockHeight", block.NumberU64())
+		}
+	}
+}
+
+func (peerConfig *SyncPeerConfig) isSubscribed() bool {
+	peerConfig.mux.Lock()
+	defer peerConfig.mux.Unlock()
+	return peerConfig.unsubscribe != nil
 }
 
-// RegisterNodeInfo will register node to peers to accept future new block broadcasting
-// return number of successfull registration
-func (ss *StateSync) RegisterNodeInfo() int {
+// SubscribeBlocks subscribes to the blocks committed by up to RegistrationNumber
+// peers, so that the blocks committed while syncing are added after the
+// download. The closed subscriptions are opened again. It returns the number of
+// peers subscribed to.
+func (ss *StateSync) SubscribeBlocks() int {
 	ss.CleanUpNilPeers()
-	registrationNumber := RegistrationNumber
-	utils.GetLogInstance().Debug("[SYNC] node registration to peers", "registrationNumber", registrationNumber, "activePeerNumber", ss.activePeerNumber)
 	peerID := utils.GetUniqueIDFromIPPort(ss.selfip, ss.selfport)
 	peerHash := make([]byte, 4)
 	binary.BigEndian.PutUint32(peerHash[:], peerID)
 
 	count := 0
-	for id := range ss.syncConfig.peers {
-		peerConfig := ss.syncConfig.peers[id]
-		if count >= registrationNumber {
+	for _, peerConfig := range ss.syncConfig.peers {
+		if peerConfig.isSubscribed() {
+			count++
+		}
+	}
+	utils.GetLogInstance().Debug("[SYNC] subscribing to new blocks", "subscribed", count, "registrationNumber", RegistrationNumber, "activePeerNumber", ss.activePeerNumber)
+	for _, peerConfig := range ss.syncConfig.peers {
+		if count >= RegistrationNumber {
 			break
 		}
-		if peerConfig.client == nil {
+		if peerConfig.client == nil || peerConfig.isDisconnected() || peerConfig.isSubscribed() {
 			continue
 		}
-		err := peerConfig.registerToBroadcast(peerHash)
-		if err != nil {
-			utils.GetLogInstance().Debug("[SYNC] register failed to peer", "ip", peerConfig.ip, "port", peerConfig.port, "peerHash", peerHash)
+		if err := peerConfig.subscribeBlocks(peerHash); err != nil {
+			utils.GetLogInstance().Debug("[SYNC] subscription failed to peer", "ip", peerConfig.ip, "port", peerConfig.port, "peerHash", peerHash)
 			continue
 		}
-		utils.GetLogInstance().Debug("[SYNC] register success", "ip", peerConfig.ip, "port", peerConfig.port)
+		utils.GetLogInstance().Debug("[SYNC] subscription success", "ip", peerConfig.ip, "port", peerConfig.port)
 		count++
 	}
 	return count
@@ -1,40 +1,262 @@
 package syncing
 
 import (
+	"bytes"
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/configs"
 	"github.com/stretchr/testify/assert"
 )
 
 // Simple test for IncorrectResponse
 func TestCreateTestSyncPeerConfig(t *testing.T) {
 	client := &downloader.Client{}
-	blockHashes := [][]byte{[]byte{}}
-	syncPeerConfig := CreateTestSyncPeerConfig(client, blockHashes)
+	syncPeerConfig := CreateTestSyncPeerConfig(client, 10)
 	assert.Equal(t, client, syncPeerConfig.GetClient(), "error")
 }
 
-// Simple test for IncorrectResponse
-func TestCompareSyncPeerConfigByblockHashes(t *testing.T) {
+func TestPickPeer(t *testing.T) {
 	client := &downloader.Client{}
-	blockHashes1 := [][]byte{[]byte{1, 2, 3}}
-	syncPeerConfig1 := CreateTestSyncPeerConfig(client, blockHashes1)
-	blockHashes2 := [][]byte{[]byte{1, 2, 4}}
-	syncPeerConfig2 := CreateTestSyncPeerConfig(client, blockHashes2)
+	low := CreateTestSyncPeerConfig(client, 5)
+	high := CreateTestSyncPeerConfig(client, 10)
+	banned := CreateTestSyncPeerConfig(client, 20)
+	banned.reputation.ban(time.Now())
+	ss := CreateStateSync("127.0.0.1", "9000")
+	ss.syncConfig = &SyncConfig{peers: []*SyncPeerConfig{low, high, banned}}
+
+	low.reputation.recordSuccess(100 * time.Millisecond)
+	high.reputation.recordSuccess(200 * time.Millisecond)
+	assert.Equal(t, low, ss.pickPeer(5), "fastest peer holding the block")
+	assert.Equal(t, high, ss.pickPeer(8), "only peer holding the block")
+	assert.Nil(t, ss.pickPeer(15), "banned peers are not picked")
+
+	// Requests in flight and penalties spread the work to the other peers.
+	low.reputation.inFlight = 2
+	assert.Equal(t, high, ss.pickPeer(5), "less busy peer")
+	low.reputation.inFlight = 0
+	low.reputation.penalties = 2
+	assert.Equal(t, high, ss.pickPeer(5), "peer without penalties")
+}
+
+func TestCheckWithheld(t *testing.T) {
+	peerConfig := CreateTestSyncPeerConfig(&downloader.Client{}, 10)
+	peerConfig.checkWithheld(11)
+	assert.Equal(t, 0, peerConfig.reputation.penalties, "peer without the block penalized")
+	peerConfig.checkWithheld(10)
+	assert.Equal(t, 1, peerConfig.reputation.penalties, "peer withholding the block not penalized")
+	assert.False(t, peerConfig.canServe(5, time.Now()), "peer withholding the block not banned")
+}
+
+func TestPeerReputation(t *testing.T) {
+	now := time.Now()
+	rep := &peerReputation{}
+	for i := 1; i < MaxPeerFailures; i++ {
+		assert.False(t, rep.recordFailure(now), "banned too early")
+	}
+	assert.True(t, rep.recordFailure(now), "banned after consecutive failures")
+	assert.False(t, rep.available(now.Add(PeerBanDuration-time.Second)), "available while banned")
+	assert.True(t, rep.available(now.Add(PeerBanDuration)), "banned after the ban")
+
+	// The ban duration doubles up to the maximum.
+	assert.Equal(t, 2*PeerBanDuration, rep.ban(now), "second ban duration")
+	for i := 0; i < 10; i++ {
+		rep.ban(now)
+	}
+	assert.Equal(t, MaxPeerBanDuration, rep.ban(now), "maximum ban duration")
+
+	// The latency is a moving average of the measures.
+	rep = &peerReputation{}
+	rep.recordSuccess(time.Second)
+	rep.recordSuccess(2 * time.Second)
+	assert.Equal(t, 1200*time.Millisecond, rep.latency, "average latency")
+	slow := rep.score()
+	rep.recordFailure(now)
+	assert.True(t, rep.score() < slow, "failures lower the score")
+	rep.disconnected = true
+	assert.False(t, rep.available(now), "disconnected peer available")
+}
+
+func makeHeaders(parent *types.Header, n int) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := range headers {
+		headers[i] = &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			TxHash:     types.EmptyRootHash,
+		}
+		parent = headers[i]
+	}
+	return headers
+}
+
+func TestCheckHeaderChain(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0)}
+	headers := makeHeaders(genesis, 3)
+	assert.Nil(t, checkHeaderChain(genesis, headers), "linked headers")
+	assert.Nil(t, checkHeaderChain(genesis, nil), "no headers")
+	assert.Equal(t, ErrBrokenHeaderChain, checkHeaderChain(headers[0], headers), "wrong parent")
+	assert.Equal(t, ErrBrokenHeaderChain, checkHeaderChain(genesis, []*types.Header{headers[0], headers[2]}), "missing header")
+}
+
+func TestCheckBlocks(t *testing.T) {
+	headers := makeHeaders(&types.Header{Number: big.NewInt(0)}, 2)
+	blocks := []*types.Block{types.NewBlockWithHeader(headers[0]), types.NewBlockWithHeader(headers[1])}
+	assert.Nil(t, checkBlocks(headers, blocks), "blocks of the headers")
+	assert.Equal(t, ErrInvalidBlock, checkBlocks(headers, blocks[:1]), "missing block")
+	assert.Equal(t, ErrInvalidBlock, checkBlocks(headers, []*types.Block{blocks[1], blocks[0]}), "blocks out of order")
+
+	tx := types.NewTransaction(0, common.Address{}, 0, big.NewInt(1), 21000, big.NewInt(1), nil)
+	withTx := blocks[0].WithBody([]*types.Transaction{tx}, nil, nil)
+	assert.Equal(t, ErrInvalidBlock, checkBlocks(headers, []*types.Block{withTx, blocks[1]}), "transactions not matching the header")
+}
+
+func TestCheckShard(t *testing.T) {
+	headers := makeHeaders(&types.Header{Number: big.NewInt(0)}, 2)
+	assert.Nil(t, checkShard(0, headers), "headers of the shard")
+	headers[1].ShardID = types.EncodeShardID(1)
+	assert.Equal(t, ErrWrongShard, checkShard(0, headers), "header of another shard")
+}
+
+func TestEpochBatchSize(t *testing.T) {
+	config := &configs.ChainConfig{BlocksPerEpoch: 5}
+	assert.Equal(t, 5, epochBatchSize(config, 0, 512), "batch ending at the first epoch block")
+	assert.Equal(t, 1, epochBatchSize(config, 4, 512), "batch of the epoch block alone")
+	assert.Equal(t, 5, epochBatchSize(config, 5, 512), "batch ending at the next epoch block")
+	assert.Equal(t, 2, epochBatchSize(config, 6, 2), "batch within an epoch")
+}
+
+// rotatingCommitteeEngine checks the seals of a chain whose committee changes at
+// every epoch: the extra data of a block gives the epoch of the committee which
+// signed it, and the committee of an epoch is only known once the shard state
+// of its first block is stored. Otherwise the genesis committee is used, like
+// the committee engine does.
+type rotatingCommitteeEngine struct {
+	consensus.Engine
+}
+
+func (rotatingCommitteeEngine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	number, config := header.Number.Uint64(), chain.HarmonyConfig()
+	epoch := config.EpochOfBlock(number - 1)
+	if epoch > 0 && chain.(*core.BlockChain).GetShardStateByNumber(config.EpochFirstBlock(epoch)) == nil {
+		if !bytes.Equal(header.Extra, []byte{0}) {
+			return consensus.ErrUnverifiedCommittee
+		}
+		return nil
+	}
+	if !bytes.Equal(header.Extra, []byte{byte(epoch)}) {
+		return consensus.ErrInvalidSignature
+	}
+	return nil
+}
+
+func TestVerifySealsAcrossEpochs(t *testing.T) {
+	spec := core.Genesis{Config: params.TestChainConfig}
+	db, genDB := ethdb.NewMemDatabase(), ethdb.NewMemDatabase()
+	spec.MustCommit(db)
+	genesis := spec.MustCommit(genDB)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, rotatingCommitteeEngine{consensus.NewFaker()}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	config := chain.HarmonyConfig()
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, int(3*config.BlocksPerEpoch), func(i int, gen *core.BlockGen) {
+		gen.SetExtra([]byte{byte(config.EpochOfBlock(uint64(i)))})
+	})
+	headers := make([]*types.Header, len(blocks))
+	for i, block := range blocks {
+		headers[i] = block.Header()
+	}
+
+	// A batch going past an epoch block can't be verified before the epoch
+	// block is inserted.
+	assert.Equal(t, consensus.ErrUnverifiedCommittee, verifySeals(chain, headers), "batch across epochs")
+
+	// The batches ending at the epoch blocks are verified against the rotated
+	// committees once the previous batch is inserted.
+	for parent := 0; parent < len(blocks); {
+		size := epochBatchSize(config, uint64(parent), len(blocks)-parent)
+		if err := verifySeals(chain, headers[parent:parent+size]); err != nil {
+			t.Fatalf("failed to verify headers %d to %d: %v", parent+1, parent+size, err)
+		}
+		if _, err := chain.InsertChain(blocks[parent : parent+size]); err != nil {
+			t.Fatalf("failed to insert blocks: %v", err)
+		}
+		parent += size
+	}
+	assert.Equal(t, blocks[len(blocks)-1].Hash(), chain.CurrentBlock().Hash(), "chain not synced")
+}
+
+func TestReportInvalidBlock(t *testing.T) {
+	signer, sender := &SyncPeerConfig{}, &SyncPeerConfig{}
+	assert.True(t, reportInvalidBlock([]*SyncPeerConfig{signer}, consensus.ErrInvalidSignature), "forged block not reported")
+	assert.True(t, signer.isDisconnected(), "peer sending a forged block kept")
+	assert.True(t, reportInvalidBlock([]*SyncPeerConfig{sender}, ErrWrongShard), "block of another shard not reported")
+	assert.False(t, sender.isDisconnected(), "peer sending a block of another shard disconnected")
+	assert.False(t, sender.reputation.available(time.Now()), "peer sending a block of another shard not banned")
+
+	for _, err := range []error{consensus.ErrUnknownCommittee, consensus.ErrUnverifiedCommittee} {
+		other := &SyncPeerConfig{}
+		assert.False(t, reportInvalidBlock([]*SyncPeerConfig{other}, err), "local error reported")
+		assert.False(t, other.isDisconnected(), "peer disconnected for a local error")
+		assert.True(t, other.reputation.available(time.Now()), "peer banned for a local error")
+	}
+}
+
+func TestCheckReceipts(t *testing.T) {
+	receipts := types.Receipts{types.NewReceipt(nil, false, 21000)}
+	headers := makeHeaders(&types.Header{Number: big.NewInt(0)}, 2)
+	headers[0].ReceiptHash = types.DeriveSha(receipts)
+	headers[1].ReceiptHash = types.EmptyRootHash
+	blocks := []*types.Block{types.NewBlockWithHeader(headers[0]), types.NewBlockWithHeader(headers[1])}
+	assert.Nil(t, checkReceipts(blocks, []types.Receipts{receipts, nil}), "receipts of the blocks")
+	assert.Equal(t, ErrInvalidReceipts, checkReceipts(blocks, []types.Receipts{receipts}), "missing receipts")
+	assert.Equal(t, ErrInvalidReceipts, checkReceipts(blocks, []types.Receipts{nil, receipts}), "receipts out of order")
+}
+
+func TestEstimateETA(t *testing.T) {
+	assert.Equal(t, 30*time.Second, estimateETA(100, 110, 140, 10*time.Second), "remaining blocks at the download rate")
+	assert.Equal(t, time.Duration(0), estimateETA(100, 100, 140, 10*time.Second), "no block downloaded yet")
+	assert.Equal(t, time.Duration(0), estimateETA(100, 140, 140, 10*time.Second), "target reached")
+}
 
-	// syncPeerConfig1 is less than syncPeerConfig2
-	assert.Equal(t, CompareSyncPeerConfigByblockHashes(syncPeerConfig1, syncPeerConfig2), -1, "syncPeerConfig1 is less than syncPeerConfig2")
+func TestSyncProgress(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	newStateSync := func() *StateSync {
+		ss := CreateStateSync("127.0.0.1", "9000")
+		peerConfig := CreateTestSyncPeerConfig(&downloader.Client{}, 10)
+		peerConfig.ip, peerConfig.port = "127.0.0.1", "6000"
+		ss.syncConfig = &SyncConfig{peers: []*SyncPeerConfig{peerConfig}}
+		return ss
+	}
+	ss := newStateSync()
+	assert.Equal(t, uint64(0), ss.resumeProgress(db), "no interrupted sync")
 
-	// syncPeerConfig1 is greater than syncPeerConfig2
-	blockHashes1[0][2] = 5
-	assert.Equal(t, CompareSyncPeerConfigByblockHashes(syncPeerConfig1, syncPeerConfig2), 1, "syncPeerConfig1 is greater than syncPeerConfig2")
+	headers := makeHeaders(&types.Header{Number: big.NewInt(0)}, 2)
+	blocks := []*types.Block{types.NewBlockWithHeader(headers[0]), types.NewBlockWithHeader(headers[1])}
+	ss.setProgressTarget(db, 10)
+	ss.addPendingBlocks(db, blocks)
 
-	// syncPeerConfig1 is equal to syncPeerConfig2
-	blockHashes1[0][2] = 4
-	assert.Equal(t, CompareSyncPeerConfigByblockHashes(syncPeerConfig1, syncPeerConfig2), 0, "syncPeerConfig1 is equal to syncPeerConfig2")
+	// A restarted node resumes the download with the stored blocks.
+	ss = newStateSync()
+	assert.Equal(t, uint64(10), ss.resumeProgress(db), "target of the interrupted sync")
+	assert.Equal(t, []common.Hash{blocks[0].Hash(), blocks[1].Hash()}, ss.progress.Pending, "pending blocks")
+	assert.Equal(t, []string{"127.0.0.1:6000"}, ss.progress.Peers, "sync peers")
+	assert.Nil(t, checkBlocks(headers, readPendingBlocks(db, headers)), "stored blocks")
 
-	// syncPeerConfig1 is less than syncPeerConfig2
-	blockHashes1 = blockHashes1[:1]
-	assert.Equal(t, CompareSyncPeerConfigByblockHashes(syncPeerConfig1, syncPeerConfig2), 0, "syncPeerConfig1 is less than syncPeerConfig2")
+	// The blocks left out of the chain are deleted when the sync is done.
+	ss.prunePendingBlocks(db, true)
+	assert.Nil(t, readPendingBlocks(db, headers), "pending blocks kept")
+	assert.Nil(t, rawdb.ReadSyncProgress(db), "progress kept")
 }
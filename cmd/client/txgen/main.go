@@ -129,14 +129,14 @@ func main() {
 		panic("unable to new host in txgen")
 	}
 	for shardID := range shardIDLeaderMap {
-		node := node.New(host, &consensus.Consensus{ShardID: shardID}, nil)
+		node := node.New(host, &consensus.Consensus{ShardID: shardID}, nil, nil, nil)
 		// Assign many fake addresses so we have enough address to play with at first
 		nodes = append(nodes, node)
 	}
 
 	// Client/txgenerator server node setup
 	consensusObj := consensus.New(host, "0", nil, p2p.Peer{})
-	clientNode := node.New(host, consensusObj, nil)
+	clientNode := node.New(host, consensusObj, nil, nil, nil)
 	clientNode.Client = client.NewClient(clientNode.GetHost(), shardIDLeaderMap)
 
 	readySignal := make(chan uint32)
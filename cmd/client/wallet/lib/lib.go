@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/harmony-one/harmony/api/client"
+	clientService "github.com/harmony-one/harmony/api/client/service"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/core/types"
 	libs "github.com/harmony-one/harmony/internal/beaconchain/libs"
@@ -43,7 +44,7 @@ func CreateWalletNode() *node.Node {
 		host.AddPeer(&leaderPeer)
 	}
 
-	walletNode := node.New(host, nil, nil)
+	walletNode := node.New(host, nil, nil, nil, nil)
 	walletNode.Client = client.NewClient(walletNode.GetHost(), shardIDLeaderMap)
 	return walletNode
 }
@@ -57,3 +58,17 @@ func SubmitTransaction(tx *types.Transaction, walletNode *node.Node, shardID uin
 	time.Sleep(300 * time.Millisecond)
 	return nil
 }
+
+// SendTransaction sends the transaction to the leader of its shard through the
+// client service, which reports whether the transaction was accepted.
+func SendTransaction(tx *types.Transaction, walletNode *node.Node, shardID uint32) error {
+	leader := walletNode.Client.Leaders[shardID]
+	port, _ := strconv.Atoi(leader.Port)
+	serviceClient := clientService.NewClient(leader.IP, strconv.Itoa(port+node.ClientServicePortDiff))
+	defer serviceClient.Close()
+	if err := serviceClient.SendTransaction(tx); err != nil {
+		return err
+	}
+	fmt.Printf("Transaction Id for shard %d: %s\n", int(shardID), tx.Hash().Hex())
+	return nil
+}
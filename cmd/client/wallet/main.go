@@ -15,10 +15,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	crypto2 "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	clientService "github.com/harmony-one/harmony/api/client/service"
+	proto "github.com/harmony-one/harmony/api/client/service/proto"
 	"github.com/harmony-one/harmony/cmd/client/wallet/lib"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/node"
 )
@@ -52,12 +56,19 @@ var (
 	transferReceiverPtr = transferCommand.String("to", "", "Specify the receiver account")
 	transferAmountPtr   = transferCommand.Float64("amount", 0, "Specify the amount to transfer")
 	transferShardIDPtr  = transferCommand.Int("shardID", -1, "Specify the shard ID for the transfer")
+	transferToShardPtr  = transferCommand.Int("toShardID", -1, "Specify the shard ID of the receiver for a cross-shard transfer")
 
 	freeTokenCommand    = flag.NewFlagSet("GetFreeToken", flag.ExitOnError)
 	freeTokenAddressPtr = freeTokenCommand.String("address", "", "Specify the account address to receive the free token")
 
 	balanceCommand    = flag.NewFlagSet("GetFreeToken", flag.ExitOnError)
 	balanceAddressPtr = balanceCommand.String("address", "", "Specify the account address to check balance for")
+	balanceVerifyPtr  = balanceCommand.Bool("verify", false, "Verify the balances with Merkle proofs against the block headers")
+	balanceGenesisPtr = balanceCommand.String("genesis", "", "Genesis file with the committees signing the block headers the balances are verified against")
+
+	receiptCommand    = flag.NewFlagSet("receipt", flag.ExitOnError)
+	receiptTxIDPtr    = receiptCommand.String("txid", "", "Specify the hash of the transaction")
+	receiptShardIDPtr = receiptCommand.Int("shardID", -1, "Specify the shard ID of the transaction")
 )
 
 // The main wallet program entrance. Note the this wallet program is for demo-purpose only. It does not implement
@@ -87,6 +98,9 @@ func main() {
 		fmt.Println("        --to             - The receiver account's address")
 		fmt.Println("        --amount         - The amount of token to transfer")
 		fmt.Println("        --shardId        - The shard Id for the transfer")
+		fmt.Println("    8. receipt       - Shows the status and receipt of a transaction")
+		fmt.Println("        --txid           - The hash of the transaction")
+		fmt.Println("        --shardID        - The shard Id of the transaction")
 		os.Exit(1)
 	}
 
@@ -109,6 +123,8 @@ func main() {
 		processGetFreeToken()
 	case "transfer":
 		processTransferCommand()
+	case "receipt":
+		processReceiptCommand()
 	default:
 		fmt.Printf("Unknown action: %s\n", os.Args[1])
 		flag.PrintDefaults()
@@ -162,17 +178,28 @@ func processBalancesCommand() {
 	balanceCommand.Parse(os.Args[2:])
 	walletNode := lib.CreateWalletNode()
 
+	fetchBalance := FetchBalance
+	if *balanceVerifyPtr {
+		engine, err := genesisCommittees(*balanceGenesisPtr)
+		if err != nil {
+			fmt.Printf("Failed to load the committees of the genesis file: %v\n", err)
+			return
+		}
+		fetchBalance = func(address common.Address, walletNode *node.Node) map[uint32]AccountState {
+			return FetchVerifiedBalance(address, walletNode, engine)
+		}
+	}
 	if *balanceAddressPtr == "" {
 		for i, address := range ReadAddresses() {
 			fmt.Printf("Account %d: %s:\n", i, address.Hex())
-			for shardID, balanceNonce := range FetchBalance(address, walletNode) {
+			for shardID, balanceNonce := range fetchBalance(address, walletNode) {
 				fmt.Printf("    Balance in Shard %d:  %s \n", shardID, convertBalanceIntoReadableFormat(balanceNonce.balance))
 			}
 		}
 	} else {
 		address := common.HexToAddress(*balanceAddressPtr)
 		fmt.Printf("Account: %s:\n", address.Hex())
-		for shardID, balanceNonce := range FetchBalance(address, walletNode) {
+		for shardID, balanceNonce := range fetchBalance(address, walletNode) {
 			fmt.Printf("    Balance in Shard %d:  %s \n", shardID, convertBalanceIntoReadableFormat(balanceNonce.balance))
 		}
 	}
@@ -261,8 +288,51 @@ func processTransferCommand() {
 
 	amountBigInt := big.NewInt(int64(amount * params.GWei))
 	amountBigInt = amountBigInt.Mul(amountBigInt, big.NewInt(params.GWei))
-	tx, _ := types.SignTx(types.NewTransaction(state.nonce, receiverAddress, uint32(shardID), amountBigInt, params.TxGas, nil, nil), types.HomesteadSigner{}, senderPriKey)
-	lib.SubmitTransaction(tx, walletNode, uint32(shardID))
+	toShardID := *transferToShardPtr
+	if toShardID == -1 {
+		toShardID = shardID
+	}
+	tx, _ := types.SignTx(types.NewCrossShardTransaction(state.nonce, receiverAddress, uint32(shardID), uint32(toShardID), amountBigInt, params.TxGas, nil, nil), types.HomesteadSigner{}, senderPriKey)
+	if err := lib.SendTransaction(tx, walletNode, uint32(shardID)); err != nil {
+		fmt.Printf("Failed to send the transaction: %v\n", err)
+		return
+	}
+	fmt.Printf("Transaction %s sent, check its status with: wallet receipt --txid %s --shardID %d\n", tx.Hash().Hex(), tx.Hash().Hex(), shardID)
+}
+
+func processReceiptCommand() {
+	receiptCommand.Parse(os.Args[2:])
+	if *receiptTxIDPtr == "" {
+		fmt.Println("Error: --txid is required")
+		return
+	}
+	shardID := *receiptShardIDPtr
+	if shardID == -1 {
+		fmt.Println("Please specify the shard ID of the transaction (e.g. --shardID=0)")
+		return
+	}
+	walletNode := lib.CreateWalletNode()
+	leader, ok := walletNode.Client.Leaders[uint32(shardID)]
+	if !ok {
+		fmt.Printf("Failed connecting to the shard %d\n", shardID)
+		return
+	}
+	port, _ := strconv.Atoi(leader.Port)
+	client := clientService.NewClient(leader.IP, strconv.Itoa(port+node.ClientServicePortDiff))
+	receipt := client.GetTransactionReceipt(common.HexToHash(*receiptTxIDPtr))
+
+	fmt.Printf("Status: %s\n", receipt.Status)
+	if receipt.Status != proto.TransactionStatus_TX_STATUS_INCLUDED && receipt.Status != proto.TransactionStatus_TX_STATUS_FAILED {
+		return
+	}
+	fmt.Printf("Block: %d (%s)\n", receipt.BlockNumber, common.BytesToHash(receipt.BlockHash).Hex())
+	fmt.Printf("Index: %d, Gas used: %d\n", receipt.Index, receipt.GasUsed)
+	if len(receipt.ContractAddress) > 0 {
+		fmt.Printf("Contract address: %s\n", common.BytesToAddress(receipt.ContractAddress).Hex())
+	}
+	for _, l := range receipt.Logs {
+		fmt.Printf("Log %d from %s: %d topics, %d bytes of data\n", l.Index, common.BytesToAddress(l.Address).Hex(), len(l.Topics), len(l.Data))
+	}
 }
 
 func convertBalanceIntoReadableFormat(balance *big.Int) string {
@@ -319,6 +389,47 @@ func FetchBalance(address common.Address, walletNode *node.Node) map[uint32]Acco
 	return result
 }
 
+// genesisCommittees returns a consensus engine holding the committees of the
+// given genesis file, which sign the block headers of the first epoch.
+func genesisCommittees(path string) (*consensus.Consensus, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the -genesis file is required to verify the balances")
+	}
+	genesis, err := core.LoadGenesis(path)
+	if err != nil {
+		return nil, err
+	}
+	db := ethdb.NewMemDatabase()
+	block, err := genesis.Commit(db)
+	if err != nil {
+		return nil, err
+	}
+	engine := consensus.NewFaker()
+	engine.ShardID = genesis.ShardID
+	node.SetupGenesisCommittees(engine, db, block.Hash())
+	return engine, nil
+}
+
+// FetchVerifiedBalance fetches account balance of specified address with its Merkle
+// proof, and only returns the balances that match the state root of a block header
+// signed by the committee of its shard known by the engine
+func FetchVerifiedBalance(address common.Address, walletNode *node.Node, engine consensus.Engine) map[uint32]AccountState {
+	result := make(map[uint32]AccountState)
+	for shardID, leader := range walletNode.Client.Leaders {
+		port, _ := strconv.Atoi(leader.Port)
+		client := clientService.NewClient(leader.IP, strconv.Itoa(port+node.ClientServicePortDiff))
+		response := client.GetProof(address, nil, clientService.LatestBlockNumber)
+		header, proof, err := clientService.VerifyProofResponse(address, response, engine)
+		if err != nil {
+			fmt.Printf("Failed to verify the balance in shard %d: %v\n", shardID, err)
+			continue
+		}
+		fmt.Printf("Balance in shard %d verified against block %d (%s)\n", shardID, header.Number.Uint64(), header.Hash().Hex())
+		result[shardID] = AccountState{proof.Balance, proof.Nonce}
+	}
+	return result
+}
+
 // GetFreeToken requests for token test token on each shard
 func GetFreeToken(address common.Address, walletNode *node.Node) {
 	for shardID, leader := range walletNode.Client.Leaders {
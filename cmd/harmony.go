@@ -1,12 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
+	"os/signal"
 	"path"
 	"runtime"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/harmony-one/harmony/drand"
@@ -16,8 +21,15 @@ import (
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	multiaddr "github.com/multiformats/go-multiaddr"
 
+	"github.com/harmony-one/harmony/api/service/jsonrpc"
+	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/staking"
+	"github.com/harmony-one/harmony/core/vm"
 	"github.com/harmony-one/harmony/internal/attack"
+	"github.com/harmony-one/harmony/internal/configs"
 	pkg_newnode "github.com/harmony-one/harmony/internal/newnode"
 	"github.com/harmony-one/harmony/internal/profiler"
 	"github.com/harmony-one/harmony/internal/utils"
@@ -80,7 +92,286 @@ func loggingInit(logFolder, role, ip, port string, onlyLogTps bool) {
 	log.Root().SetHandler(h)
 }
 
+// processInitCommand writes the genesis block described by a genesis file into
+// the database of the node, so that the node starts on that chain.
+func processInitCommand(args []string) {
+	initCommand := flag.NewFlagSet("init", flag.ExitOnError)
+	ip := initCommand.String("ip", "127.0.0.1", "IP of the node")
+	port := initCommand.String("port", "9000", "port of the node.")
+	genesisFile := initCommand.String("genesis", "", "JSON file of the genesis block")
+	initCommand.Parse(args)
+
+	if *genesisFile == "" {
+		fmt.Println("Usage: harmony init -genesis <genesis.json> [-ip <ip>] [-port <port>]")
+		os.Exit(1)
+	}
+	genesis, err := core.LoadGenesis(*genesisFile)
+	if err != nil {
+		fmt.Printf("Failed to load genesis file: %v\n", err)
+		os.Exit(1)
+	}
+	ldb, err := InitLDBDatabase(*ip, *port, false)
+	if err != nil {
+		fmt.Printf("Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer ldb.Close()
+
+	_, hash, err := core.SetupGenesisBlock(ldb, genesis)
+	if err != nil {
+		fmt.Printf("Failed to write genesis block: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully wrote genesis block %x of shard %v\n", hash, genesis.ShardID)
+}
+
+// openBlockChain opens the blockchain stored in the database of the node. The
+// database must have been initialized with a genesis block, by "harmony init"
+// or by running the node once. The committees of the genesis are used to verify
+// block signatures.
+func openBlockChain(ip, port string) (*core.BlockChain, *ethdb.LDBDatabase, error) {
+	ldb, err := InitLDBDatabase(ip, port, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := core.VerifyStoredGenesis(ldb); err != nil {
+		ldb.Close()
+		if err == core.ErrNoGenesis {
+			return nil, nil, fmt.Errorf("database has no genesis block, run harmony init first")
+		}
+		return nil, nil, err
+	}
+	genesisHash := rawdb.ReadCanonicalHash(ldb, 0)
+	evmConfig := rawdb.ReadChainConfig(ldb, genesisHash)
+	if evmConfig == nil {
+		ldb.Close()
+		return nil, nil, fmt.Errorf("database has no chain configuration, run harmony init first")
+	}
+	engine := consensus.NewFaker()
+	engine.ShardID = uint32(evmConfig.ChainID.Int64())
+	node.SetupGenesisCommittees(engine, ldb, genesisHash)
+
+	chain, err := core.NewBlockChain(ldb, nil, evmConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		ldb.Close()
+		return nil, nil, err
+	}
+	return chain, ldb, nil
+}
+
+// processExportCommand writes a range of blocks of the canonical chain to a
+// file as RLP, to be read back by "harmony import".
+func processExportCommand(args []string) {
+	exportCommand := flag.NewFlagSet("export", flag.ExitOnError)
+	ip := exportCommand.String("ip", "127.0.0.1", "IP of the node")
+	port := exportCommand.String("port", "9000", "port of the node.")
+	from := exportCommand.Uint64("from", 0, "number of the first exported block")
+	to := exportCommand.Int64("to", -1, "number of the last exported block, -1 means the current block")
+	exportCommand.Parse(args)
+
+	if exportCommand.NArg() != 1 {
+		fmt.Println("Usage: harmony export [-from <number>] [-to <number>] [-ip <ip>] [-port <port>] <file>")
+		os.Exit(1)
+	}
+	log.Root().SetHandler(log.StreamHandler(os.Stdout, log.TerminalFormat(false)))
+	chain, ldb, err := openBlockChain(*ip, *port)
+	if err != nil {
+		fmt.Printf("Failed to open blockchain: %v\n", err)
+		os.Exit(1)
+	}
+	defer ldb.Close()
+
+	last := chain.CurrentBlock().NumberU64()
+	if *to >= 0 {
+		last = uint64(*to)
+	}
+	file, err := os.Create(exportCommand.Arg(0))
+	if err != nil {
+		fmt.Printf("Failed to create export file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if err := chain.ExportN(file, *from, last); err != nil {
+		fmt.Printf("Failed to export blocks: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully exported blocks %v to %v\n", *from, last)
+}
+
+// processImportCommand inserts the blocks of a file written by "harmony export"
+// into the blockchain of the node.
+func processImportCommand(args []string) {
+	importCommand := flag.NewFlagSet("import", flag.ExitOnError)
+	ip := importCommand.String("ip", "127.0.0.1", "IP of the node")
+	port := importCommand.String("port", "9000", "port of the node.")
+	batchSize := importCommand.Int("batch", 2500, "number of blocks inserted at once")
+	verifySeals := importCommand.Bool("verify_seals", true, "verify the committee signatures of the imported blocks")
+	importCommand.Parse(args)
+
+	if importCommand.NArg() != 1 {
+		fmt.Println("Usage: harmony import [-batch <size>] [-verify_seals=false] [-ip <ip>] [-port <port>] <file>")
+		os.Exit(1)
+	}
+	log.Root().SetHandler(log.StreamHandler(os.Stdout, log.TerminalFormat(false)))
+	chain, ldb, err := openBlockChain(*ip, *port)
+	if err != nil {
+		fmt.Printf("Failed to open blockchain: %v\n", err)
+		os.Exit(1)
+	}
+	defer ldb.Close()
+
+	// The signatures are checked against the committees stored with the genesis.
+	if engine, ok := chain.Engine().(*consensus.Consensus); *verifySeals && (!ok || !engine.HasShardPublicKeys(chain.ShardID())) {
+		fmt.Println("The genesis has no committee to verify the block signatures with, run harmony init with a genesis committee or import with -verify_seals=false")
+		os.Exit(1)
+	}
+
+	file, err := os.Open(importCommand.Arg(0))
+	if err != nil {
+		fmt.Printf("Failed to open import file: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	imported, err := chain.Import(file, *batchSize, *verifySeals)
+	// Flush the recent states kept in memory, the node restarts from the head.
+	chain.Stop()
+	if err != nil {
+		fmt.Printf("Imported %v blocks before failing: %v\n", imported, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully imported %v blocks, head is block %v\n", imported, chain.CurrentBlock().NumberU64())
+}
+
+// processStakesCommand prints the staking registry of the current block as
+// JSON: the validators with their delegations and the withdrawn stakes not
+// released yet. With -verify, the registry is first checked against the one
+// rebuilt from the staking transactions of the chain.
+func processStakesCommand(args []string) {
+	stakesCommand := flag.NewFlagSet("stakes", flag.ExitOnError)
+	ip := stakesCommand.String("ip", "127.0.0.1", "IP of the node")
+	port := stakesCommand.String("port", "9000", "port of the node.")
+	verify := stakesCommand.Bool("verify", false, "verify the registry against the staking transactions of the chain")
+	from := stakesCommand.Uint64("from", 0, "number of the block the staking transactions are replayed from by -verify")
+	stakesCommand.Parse(args)
+
+	// The registry is printed to stdout, keep the logs apart.
+	log.Root().SetHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	chain, ldb, err := openBlockChain(*ip, *port)
+	if err != nil {
+		fmt.Printf("Failed to open blockchain: %v\n", err)
+		os.Exit(1)
+	}
+	defer ldb.Close()
+
+	statedb, err := chain.State()
+	if err != nil {
+		fmt.Printf("Failed to open the state of block %v: %v\n", chain.CurrentBlock().NumberU64(), err)
+		os.Exit(1)
+	}
+	if *verify {
+		if err := chain.VerifyStakes(*from); err != nil {
+			fmt.Printf("Failed to verify the staking registry: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Verified the staking registry of block %v from block %v\n", chain.CurrentBlock().NumberU64(), *from)
+	}
+	data, err := json.MarshalIndent(staking.Dump(statedb), "", "  ")
+	if err != nil {
+		fmt.Printf("Failed to encode the staking registry: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
+
+// newCacheConfig returns the state cache configuration of the given gc mode:
+// "full" keeps the recent states in memory and prunes the older ones, while
+// "archive" writes the state of every block to disk.
+func newCacheConfig(gcMode string, trieCache int, triesInMemory uint64) (*core.CacheConfig, error) {
+	var cacheConfig *core.CacheConfig
+	switch gcMode {
+	case "full":
+		cacheConfig = core.DefaultCacheConfig()
+	case "archive":
+		cacheConfig = core.ArchiveCacheConfig()
+	default:
+		return nil, fmt.Errorf("unknown gc mode %q, want full or archive", gcMode)
+	}
+	if trieCache > 0 {
+		cacheConfig.TrieNodeLimit = trieCache
+	}
+	if triesInMemory > 0 {
+		cacheConfig.TriesInMemory = triesInMemory
+	}
+	return cacheConfig, nil
+}
+
+// parseBeaconPeers parses the comma separated ip:port addresses of the beacon
+// chain nodes followed by a shard node.
+func parseBeaconPeers(addresses string) ([]p2p.Peer, error) {
+	peers := []p2p.Peer{}
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+		ip, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid beacon peer %q: %v", address, err)
+		}
+		peers = append(peers, p2p.Peer{IP: ip, Port: port})
+	}
+	return peers, nil
+}
+
+// splitList splits a comma separated flag value, dropping the empty items.
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stopOnSignal flushes the transaction journal and the recent states of the
+// node to disk on SIGINT or SIGTERM before exiting, so a pruned node can restart
+// from its head state.
+func stopOnSignal(currentNode *node.Node) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		utils.GetLogInstance().Info("Got signal, shutting down", "signal", sig)
+		currentNode.TxPool.Stop()
+		currentNode.Blockchain().Stop()
+		os.Exit(0)
+	}()
+}
+
 func main() {
+	// harmony init -genesis <file> initializes the database with a genesis block.
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		processInitCommand(os.Args[2:])
+		return
+	}
+	// harmony export [-from <number>] [-to <number>] <file> writes blocks to a file.
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		processExportCommand(os.Args[2:])
+		return
+	}
+	// harmony import <file> inserts the blocks of an exported file.
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		processImportCommand(os.Args[2:])
+		return
+	}
+	// harmony stakes [-verify] [-from <number>] prints the staking registry.
+	if len(os.Args) > 1 && os.Args[1] == "stakes" {
+		processStakesCommand(os.Args[2:])
+		return
+	}
+
 	// TODO: use http://getmyipaddress.org/ or http://www.get-myip.com/ to retrieve my IP address
 	ip := flag.String("ip", "127.0.0.1", "IP of the node")
 	port := flag.String("port", "9000", "port of the node.")
@@ -117,6 +408,44 @@ func main() {
 	// logConn logs incoming/outgoing connections
 	logConn := flag.Bool("log_conn", false, "log incoming/outgoing connections")
 
+	// chainConfigFile is the Harmony chain configuration used for a new genesis block,
+	// it must match the configuration of an existing database
+	chainConfigFile := flag.String("chain_config", "", "JSON file of the chain configuration (epoch length, shards, gas, rewards)")
+
+	// genesisFile is the genesis the database was initialized with by "harmony init"
+	genesisFile := flag.String("genesis", "", "JSON file of the genesis block, checked against the one stored in the database")
+
+	// gcMode chooses between a pruned state (full) and keeping the state of every block (archive)
+	gcMode := flag.String("gcmode", "full", "garbage collection mode of the state: full or archive")
+	trieCache := flag.Int("trie_cache", 256, "memory limit (MB) of the state trie cache before flushing to disk")
+	triesInMemory := flag.Uint64("tries_in_memory", 128, "number of recent block states kept in memory in full gc mode")
+
+	// The block proposal policy of the leader
+	blockTime := flag.Duration("block_time", node.DefaultBlockProposalPolicy.BlockTime, "target time between two blocks with pending transactions")
+	emptyBlockInterval := flag.Duration("empty_block_interval", node.DefaultBlockProposalPolicy.EmptyBlockInterval, "time after which an empty block is proposed, 0 disables empty blocks")
+	maxBlockTxs := flag.Int("max_block_txs", node.DefaultBlockProposalPolicy.MaxTransactions, "maximum number of transactions in a block")
+	maxBlockGas := flag.Uint64("max_block_gas", node.DefaultBlockProposalPolicy.MaxGas, "maximum total gas limit of the transactions in a block, 0 means the block gas limit")
+	maxBlockBytes := flag.Uint64("max_block_bytes", node.DefaultBlockProposalPolicy.MaxBytes, "maximum total size of the transactions in a block, 0 means no limit")
+
+	// The header-first block download of a node out of sync
+	syncHeaderBatch := flag.Int("sync_header_batch", syncing.DefaultDownloadConfig.HeaderBatch, "number of headers fetched and verified at once when syncing")
+	syncBlockBatch := flag.Int("sync_block_batch", syncing.DefaultDownloadConfig.BlockBatch, "number of blocks fetched per request when syncing")
+	syncWindow := flag.Int("sync_window", syncing.DefaultDownloadConfig.Window, "number of block requests in flight when syncing")
+	syncSnapshot := flag.Bool("sync_snapshot", syncing.DefaultDownloadConfig.Snapshot, "download the state of a recent block instead of replaying the whole chain when joining")
+
+	// The beacon chain nodes a shard node follows the beacon headers from
+	beaconPeers := flag.String("beacon_peers", "", "comma separated ip:port of the beacon chain nodes a shard node downloads the beacon headers from")
+
+	// The HTTP and WebSocket JSON-RPC endpoints, off and bound to the local interface by default
+	rpcEnabled := flag.Bool("rpc", false, "enable the HTTP JSON-RPC endpoint on the node port + 500")
+	rpcAddr := flag.String("rpcaddr", jsonrpc.DefaultConfig.HTTPHost, "interface the HTTP JSON-RPC endpoint listens on")
+	rpcCORS := flag.String("rpccorsdomain", "", "comma separated origins allowed by cross origin requests to the HTTP JSON-RPC endpoint")
+	rpcVHosts := flag.String("rpcvhosts", strings.Join(jsonrpc.DefaultConfig.VirtualHosts, ","), "comma separated host names accepted by the HTTP JSON-RPC endpoint, * accepts all")
+	wsEnabled := flag.Bool("ws", false, "enable the WebSocket JSON-RPC endpoint on the node port + 800")
+	wsAddr := flag.String("wsaddr", jsonrpc.DefaultConfig.WSHost, "interface the WebSocket JSON-RPC endpoint listens on")
+	wsOrigins := flag.String("wsorigins", "", "comma separated origins allowed to open a WebSocket JSON-RPC connection")
+	rpcAPI := flag.String("rpcapi", strings.Join(jsonrpc.DefaultConfig.Modules, ","), "comma separated API namespaces served over JSON-RPC: eth, debug")
+
 	flag.Parse()
 
 	if *versionFlag {
@@ -212,12 +541,61 @@ func main() {
 	// Init logging.
 	loggingInit(*logFolder, role, *ip, *port, *onlyLogTps)
 
+	chainConfig := configs.DefaultChainConfig
+	if *chainConfigFile != "" {
+		chainConfig, err = configs.LoadChainConfig(*chainConfigFile)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	cacheConfig, err := newCacheConfig(*gcMode, *trieCache, *triesInMemory)
+	if err != nil {
+		panic(err)
+	}
+
+	bcPeers, err := parseBeaconPeers(*beaconPeers)
+	if err != nil {
+		panic(err)
+	}
+
 	// Initialize leveldb if dbSupported.
 	var ldb *ethdb.LDBDatabase
 	if *dbSupported {
 		ldb, _ = InitLDBDatabase(*ip, *port, *freshDB)
 	}
 
+	// The genesis block of an initialized database must not have been altered.
+	if ldb != nil {
+		if err := core.VerifyStoredGenesis(ldb); err != nil && err != core.ErrNoGenesis {
+			utils.GetLogInstance().Error("Stored genesis check failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// The chain configuration of an initialized database can't be changed.
+	if *chainConfigFile != "" && ldb != nil {
+		if err := core.VerifyHarmonyConfig(ldb, chainConfig); err != nil {
+			utils.GetLogInstance().Error("Chain configuration check failed, the database was created with another configuration", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Make sure the database was initialized with the given genesis.
+	if *genesisFile != "" {
+		genesis, err := core.LoadGenesis(*genesisFile)
+		if err != nil {
+			panic(err)
+		}
+		if ldb == nil {
+			panic("genesis file requires db support")
+		}
+		if err := core.VerifyGenesis(ldb, genesis); err != nil {
+			utils.GetLogInstance().Error("Genesis check failed, run harmony init with the genesis file first", "error", err)
+			os.Exit(1)
+		}
+	}
+
 	host, err := p2pimpl.NewHost(&selfPeer, nodePriKey)
 	if *logConn {
 		host.GetP2PHost().Network().Notify(utils.ConnLogger)
@@ -245,8 +623,32 @@ func main() {
 	}
 
 	// Current node.
-	currentNode := node.New(host, consensus, ldb)
+	currentNode := node.New(host, consensus, ldb, chainConfig, cacheConfig)
 	currentNode.Consensus.OfflinePeers = currentNode.OfflinePeers
+	currentNode.ProposalPolicy = node.BlockProposalPolicy{
+		BlockTime:          *blockTime,
+		EmptyBlockInterval: *emptyBlockInterval,
+		MaxTransactions:    *maxBlockTxs,
+		MaxGas:             *maxBlockGas,
+		MaxBytes:           *maxBlockBytes,
+	}
+	currentNode.SyncDownloadConfig = syncing.DownloadConfig{
+		HeaderBatch: *syncHeaderBatch,
+		BlockBatch:  *syncBlockBatch,
+		Window:      *syncWindow,
+		Snapshot:    *syncSnapshot,
+	}
+	currentNode.RPCConfig = jsonrpc.Config{
+		HTTP:         *rpcEnabled,
+		HTTPHost:     *rpcAddr,
+		CORS:         splitList(*rpcCORS),
+		VirtualHosts: splitList(*rpcVHosts),
+		WS:           *wsEnabled,
+		WSHost:       *wsAddr,
+		WSOrigins:    splitList(*wsOrigins),
+		Modules:      splitList(*rpcAPI),
+	}
+	currentNode.BCPeers = bcPeers
 	currentNode.Role = node.NewNode
 
 	if *isBeacon {
@@ -255,6 +657,15 @@ func main() {
 		} else {
 			currentNode.Role = node.BeaconValidator
 		}
+		// Check the staking records kept in the state against the staking
+		// transactions received since the last check. A node whose records
+		// can't be trusted must not take part in consensus.
+		checkpoint, err := currentNode.Blockchain().VerifyStakesSinceCheckpoint()
+		if err != nil {
+			utils.GetLogInstance().Error("Staking records check failed", "checkpoint", checkpoint, "error", err)
+			os.Exit(1)
+		}
+		utils.GetLogInstance().Info("Staking records verified", "checkpoint", checkpoint)
 
 	} else {
 		if role == "leader" {
@@ -291,6 +702,7 @@ func main() {
 		}
 	}
 
+	stopOnSignal(currentNode)
 	go currentNode.SupportSyncing()
 	currentNode.ServiceManagerSetup()
 	currentNode.RunServices()
@@ -13,14 +13,15 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	protobuf "github.com/golang/protobuf/proto"
 	"github.com/harmony-one/bls/ffi/go/bls"
 	consensus_proto "github.com/harmony-one/harmony/api/consensus"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
 	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/internal/configs"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/host"
@@ -57,6 +58,10 @@ type Consensus struct {
 	PublicKeys []*bls.PublicKey
 	pubKeyLock sync.Mutex
 
+	// Public keys of the committees of the other shards, used to verify the
+	// headers in cross-shard receipts proofs
+	shardPublicKeys map[uint32][]*bls.PublicKey
+
 	// private/public keys of current node
 	priKey *bls.SecretKey
 	pubKey *bls.PublicKey
@@ -521,20 +526,152 @@ func (consensus *Consensus) verifyHeader(chain ChainReader, header, parent *type
 	return nil
 }
 
-// VerifySeal implements consensus.Engine, checking whether the given block satisfies
-// the PoW difficulty requirements.
+// VerifySeal implements consensus.Engine, checking that the given block carries
+// the commit signature of the committee of its shard and epoch.
 func (consensus *Consensus) VerifySeal(chain ChainReader, header *types.Header) error {
+	publicKeys, verified := consensus.epochCommittee(chain, binary.BigEndian.Uint32(header.ShardID[:]), header.Number.Uint64())
+	if len(publicKeys) == 0 {
+		return ErrUnknownCommittee
+	}
+	err := verifySignatures(publicKeys, header)
+	if !verified && (err == ErrNotEnoughSignatures || err == ErrInvalidSignature) {
+		return ErrUnverifiedCommittee
+	}
+	return err
+}
+
+// verifySignatures checks that the given block carries the commit signature of
+// the given committee.
+func verifySignatures(publicKeys []*bls.PublicKey, header *types.Header) error {
+	commitMask, err := bls_cosi.NewMask(publicKeys, nil)
+	if err != nil {
+		return err
+	}
+	if err := commitMask.SetMask(header.CommitBitmap); err != nil {
+		return ErrInvalidSignature
+	}
+	if commitMask.CountEnabled() < len(publicKeys)*2/3+1 {
+		return ErrNotEnoughSignatures
+	}
+	prepareMask, err := bls_cosi.NewMask(publicKeys, nil)
+	if err != nil {
+		return err
+	}
+	if err := prepareMask.SetMask(header.PrepareBitmap); err != nil {
+		return ErrInvalidSignature
+	}
+
+	// The commit signature signs the aggregated prepare signature and bitmap,
+	// which in turn sign the block hash announced by the leader.
+	commitSig := bls.Sign{}
+	if err := commitSig.Deserialize(header.CommitSignature[:]); err != nil {
+		return ErrInvalidSignature
+	}
+	prepareSigAndBitmap := append(header.PrepareSignature[:], header.PrepareBitmap...)
+	if !commitSig.VerifyHash(commitMask.AggregatePublic, prepareSigAndBitmap) {
+		return ErrInvalidSignature
+	}
+	prepareSig := bls.Sign{}
+	if err := prepareSig.Deserialize(header.PrepareSignature[:]); err != nil {
+		return ErrInvalidSignature
+	}
+	blockHash := header.HashWithoutSignatures()
+	if !prepareSig.VerifyHash(prepareMask.AggregatePublic, blockHash[:]) {
+		return ErrInvalidSignature
+	}
 	return nil
 }
 
+// committeeOf returns the public keys of the committee of the given shard.
+func (consensus *Consensus) committeeOf(shardID uint32) []*bls.PublicKey {
+	consensus.pubKeyLock.Lock()
+	defer consensus.pubKeyLock.Unlock()
+	if shardID == consensus.ShardID {
+		return consensus.PublicKeys
+	}
+	return consensus.shardPublicKeys[shardID]
+}
+
+// shardStateReader is implemented by the chains storing the shard states of
+// their epochs, such as core.BlockChain.
+type shardStateReader interface {
+	ShardID() uint32
+	GetShardStateByNumber(number uint64) types.ShardState
+}
+
+// epochCommittee returns the public keys of the committee of the given shard
+// which signed the given block. After the genesis epoch, the committee is read
+// from the shard state stored with the first block of the epoch, so that the
+// blocks of past epochs are checked against the committee which signed them.
+// The first block of an epoch, which stores the committees of the new epoch,
+// is still signed by the committee of the previous epoch. The configured
+// committee is returned for the genesis epoch and for the chains which don't
+// store shard states. The current committee is returned, and reported as
+// unverified, when the chain does not hold the shard state of a later epoch, or
+// its node IDs are not BLS public keys.
+func (consensus *Consensus) epochCommittee(chain ChainReader, shardID uint32, number uint64) ([]*bls.PublicKey, bool) {
+	reader, ok := chain.(shardStateReader)
+	if !ok || reader.ShardID() != shardID || number == 0 {
+		return consensus.committeeOf(shardID), true
+	}
+	config := chain.HarmonyConfig()
+	if config == nil || config.EpochOfBlock(number-1) == 0 {
+		return consensus.committeeOf(shardID), true
+	}
+	for _, committee := range reader.GetShardStateByNumber(config.EpochFirstBlock(config.EpochOfBlock(number - 1))) {
+		if committee.ShardID != shardID {
+			continue
+		}
+		if pubKeys := committeeKeys(committee.NodeList); len(pubKeys) > 0 {
+			return pubKeys, true
+		}
+		break
+	}
+	return consensus.committeeOf(shardID), false
+}
+
+// committeeKeys decodes the node IDs of a committee given as hex encoded BLS
+// public keys, or returns nil if any of them is not such a key.
+func committeeKeys(nodeList []types.NodeID) []*bls.PublicKey {
+	pubKeys := make([]*bls.PublicKey, 0, len(nodeList))
+	for _, nodeID := range nodeList {
+		key, err := hex.DecodeString(string(nodeID))
+		if err != nil {
+			return nil
+		}
+		pubKey := &bls.PublicKey{}
+		if err := pubKey.Deserialize(key); err != nil {
+			return nil
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys
+}
+
+// HasShardPublicKeys checks whether the committee public keys of the given shard are known
+func (consensus *Consensus) HasShardPublicKeys(shardID uint32) bool {
+	return len(consensus.committeeOf(shardID)) > 0
+}
+
+// SetShardPublicKeys sets the committee public keys of another shard, protected by a mutex
+func (consensus *Consensus) SetShardPublicKeys(shardID uint32, pubKeys []*bls.PublicKey) {
+	consensus.pubKeyLock.Lock()
+	defer consensus.pubKeyLock.Unlock()
+	if consensus.shardPublicKeys == nil {
+		consensus.shardPublicKeys = make(map[uint32][]*bls.PublicKey)
+	}
+	consensus.shardPublicKeys[shardID] = append(pubKeys[:0:0], pubKeys...)
+}
+
 // Finalize implements consensus.Engine, accumulating the block and uncle rewards,
 // setting the final state and assembling the block.
-func (consensus *Consensus) Finalize(chain ChainReader, header *types.Header, state *state.DB, txs []*types.Transaction, receipts []*types.Receipt) (*types.Block, error) {
+func (consensus *Consensus) Finalize(chain ChainReader, header *types.Header, state *state.DB, txs []*types.Transaction, receipts []*types.Receipt, outcxs types.CXReceipts, incxs types.CXReceiptsProofs) (*types.Block, error) {
 	// Accumulate any block and uncle rewards and commit the final state root
 	// Header seems complete, assemble into a block and return
-	accumulateRewards(chain.Config(), state, header)
+	accumulateRewards(chain.HarmonyConfig(), state, header)
+	releaseUnbonded(chain.HarmonyConfig(), state, header)
 	header.Root = state.IntermediateRoot(false)
-	return types.NewBlock(header, txs, receipts), nil
+	return types.NewBlock(header, txs, receipts, outcxs, incxs), nil
 }
 
 // SealHash returns the hash of a block prior to it being sealed.
@@ -572,11 +709,31 @@ func (consensus *Consensus) Prepare(chain ChainReader, header *types.Header) err
 	return nil
 }
 
-// AccumulateRewards credits the coinbase of the given block with the mining
-// reward. The total reward consists of the static block reward and rewards for
-// included uncles. The coinbase of each uncle block is also rewarded.
-func accumulateRewards(config *params.ChainConfig, state *state.DB, header *types.Header) {
-	// TODO: implement mining rewards
+// AccumulateRewards credits the coinbase of the given block with the block
+// reward defined by the reward schedule of the chain configuration. The reward
+// of a staked validator is shared between its delegators instead.
+func accumulateRewards(config *configs.ChainConfig, state *state.DB, header *types.Header) {
+	reward := config.BlockRewardAt(header.Number.Uint64())
+	if reward.Sign() <= 0 {
+		return
+	}
+	if validator := staking.GetValidatorByCoinbase(state, header.Coinbase); validator != nil {
+		staking.AddReward(state, validator.Address, reward)
+		return
+	}
+	state.AddBalance(header.Coinbase, reward)
+}
+
+// releaseUnbonded returns, at epoch blocks, the withdrawn stakes whose unbonding
+// period is over.
+func releaseUnbonded(config *configs.ChainConfig, state *state.DB, header *types.Header) {
+	number := header.Number.Uint64()
+	if !config.IsEpochBlock(number) {
+		return
+	}
+	if released := staking.ReleaseUnbonded(state, number, config.UnbondingReleaseBlock); len(released) > 0 {
+		utils.GetLogInstance().Info("Released unbonded stakes", "block", number, "count", len(released))
+	}
 }
 
 // GetNodeID returns the nodeID
@@ -5,6 +5,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 // ChainReader defines a small collection of methods needed to access the local
@@ -14,6 +15,9 @@ type ChainReader interface {
 	// Config retrieves the blockchain's chain configuration.
 	Config() *params.ChainConfig
 
+	// HarmonyConfig retrieves the blockchain's Harmony specific chain configuration.
+	HarmonyConfig() *configs.ChainConfig
+
 	// CurrentHeader retrieves the current header from the local chain.
 	CurrentHeader() *types.Header
 
@@ -61,7 +65,7 @@ type Engine interface {
 	// Note: The block header and state database might be updated to reflect any
 	// consensus rules that happen at finalization (e.g. block rewards).
 	Finalize(chain ChainReader, header *types.Header, state *state.DB, txs []*types.Transaction,
-		receipts []*types.Receipt) (*types.Block, error)
+		receipts []*types.Receipt, outcxs types.CXReceipts, incxs types.CXReceiptsProofs) (*types.Block, error)
 
 	// Seal generates a new sealing request for the given input block and pushes
 	// the result into the given channel.
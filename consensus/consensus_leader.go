@@ -255,10 +255,8 @@ func (consensus *Consensus) processCommitMessage(message consensus_proto.Message
 		}
 
 		// Sign the block
-		copy(blockObj.Header().PrepareSignature[:], consensus.aggregatedPrepareSig.Serialize()[:])
-		copy(blockObj.Header().PrepareBitmap[:], consensus.prepareBitmap.Bitmap)
-		copy(blockObj.Header().CommitSignature[:], consensus.aggregatedCommitSig.Serialize()[:])
-		copy(blockObj.Header().CommitBitmap[:], consensus.commitBitmap.Bitmap)
+		blockObj.SetPrepareSig(consensus.aggregatedPrepareSig.Serialize(), consensus.prepareBitmap.Bitmap)
+		blockObj.SetCommitSig(consensus.aggregatedCommitSig.Serialize(), consensus.commitBitmap.Bitmap)
 
 		consensus.state = targetState
 
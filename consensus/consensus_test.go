@@ -2,9 +2,13 @@ package consensus
 
 import (
 	"bytes"
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	consensus_proto "github.com/harmony-one/harmony/api/consensus"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/configs"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/p2pimpl"
@@ -139,3 +143,55 @@ func TestSignAndMarshalConsensusMessage(t *testing.T) {
 		t.Error("No signature is signed on the consensus message.")
 	}
 }
+
+func TestCommitteeKeys(t *testing.T) {
+	_, pk1 := utils.GenKey("1", "1")
+	_, pk2 := utils.GenKey("2", "2")
+	nodeList := []types.NodeID{
+		types.NodeID(hex.EncodeToString(pk1.Serialize())),
+		types.NodeID(hex.EncodeToString(pk2.Serialize())),
+	}
+	pubKeys := committeeKeys(nodeList)
+	if len(pubKeys) != 2 || !pubKeys[0].IsEqual(pk1) || !pubKeys[1].IsEqual(pk2) {
+		t.Errorf("committee keys not decoded in order: %v", pubKeys)
+	}
+	if pubKeys := committeeKeys(append(nodeList, "node3")); pubKeys != nil {
+		t.Errorf("committee with a non BLS node ID decoded: %v", pubKeys)
+	}
+}
+
+// shardStateChain is a chain of shard 0 which stores no shard state.
+type shardStateChain struct {
+	ChainReader
+}
+
+func (shardStateChain) HarmonyConfig() *configs.ChainConfig {
+	return &configs.ChainConfig{BlocksPerEpoch: 10}
+}
+
+func (shardStateChain) ShardID() uint32 { return 0 }
+
+func (shardStateChain) GetShardStateByNumber(number uint64) types.ShardState { return nil }
+
+func TestVerifySealUnverifiedCommittee(t *testing.T) {
+	_, pubKey := utils.GenKey("1", "1")
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "9902", PubKey: pubKey}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9902")
+	host, err := p2pimpl.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	consensus := New(host, "0", []p2p.Peer{leader}, leader)
+
+	// The blocks of the genesis epoch are checked against the configured committee.
+	header := &types.Header{Number: big.NewInt(5)}
+	if err := consensus.VerifySeal(shardStateChain{}, header); err != ErrInvalidSignature {
+		t.Errorf("expected %v, got %v", ErrInvalidSignature, err)
+	}
+	// The shard state of a later epoch is missing, the block is not rejected as
+	// wrongly signed.
+	header = &types.Header{Number: big.NewInt(25)}
+	if err := consensus.VerifySeal(shardStateChain{}, header); err != ErrUnverifiedCommittee {
+		t.Errorf("expected %v, got %v", ErrUnverifiedCommittee, err)
+	}
+}
@@ -40,4 +40,22 @@ var (
 
 	// ErrInvalidConsensusMessage is returned is the consensus message received is invalid
 	ErrInvalidConsensusMessage = errors.New("invalid consensus message")
+
+	// ErrUnknownCommittee is returned if the committee of a block's shard is unknown
+	// so the block signatures can't be verified.
+	ErrUnknownCommittee = errors.New("unknown committee")
+
+	// ErrNotEnoughSignatures is returned if less than two thirds of the committee
+	// signed a block.
+	ErrNotEnoughSignatures = errors.New("not enough signatures")
+
+	// ErrInvalidSignature is returned if the aggregated signatures of a block are
+	// invalid.
+	ErrInvalidSignature = errors.New("invalid aggregated signature")
+
+	// ErrUnverifiedCommittee is returned instead of ErrNotEnoughSignatures and
+	// ErrInvalidSignature if the block was checked against the current committee
+	// because the shard state of its epoch is missing, so the block may have been
+	// signed by another committee.
+	ErrUnverifiedCommittee = errors.New("committee of the block epoch unavailable")
 )
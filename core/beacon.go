@@ -0,0 +1,178 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/configs"
+)
+
+// BeaconShardID is the shard ID of the beacon chain.
+const BeaconShardID uint32 = 0
+
+var (
+	// ErrNotBeaconHeader is returned when a header followed as a beacon header
+	// belongs to another shard.
+	ErrNotBeaconHeader = errors.New("header not of the beacon chain")
+
+	// ErrBeaconGenesisMismatch is returned when the first beacon header stored
+	// doesn't follow the beacon genesis block.
+	ErrBeaconGenesisMismatch = errors.New("beacon header not following the beacon genesis block")
+)
+
+// BeaconHeaders keeps the beacon chain headers followed by a shard node, along
+// with the shard states of the beacon epoch blocks, so that the node knows the
+// committees and random seeds decided by the beacon chain without running a
+// beacon node. It implements consensus.ChainReader, the beacon headers being
+// verified against the committees of their epochs: the beacon committee of the
+// genesis, and then the ones of the shard states stored with the headers.
+type BeaconHeaders struct {
+	db            ethdb.Database
+	genesis       common.Hash // hash of the beacon genesis block
+	chainConfig   *params.ChainConfig
+	harmonyConfig *configs.ChainConfig
+	engine        consensus.Engine
+
+	mu   sync.RWMutex
+	head *types.Header // latest beacon header, nil until the first one is stored
+}
+
+// NewBeaconHeaders returns the beacon headers stored in the given database,
+// descending from the beacon genesis block with the given hash and verified
+// with the given consensus engine.
+func NewBeaconHeaders(db ethdb.Database, genesis common.Hash, chainConfig *params.ChainConfig, harmonyConfig *configs.ChainConfig, engine consensus.Engine) *BeaconHeaders {
+	beacon := &BeaconHeaders{
+		db:            db,
+		genesis:       genesis,
+		chainConfig:   chainConfig,
+		harmonyConfig: harmonyConfig,
+		engine:        engine,
+	}
+	if number := rawdb.ReadHeadBeaconHeaderNumber(db); number != nil {
+		beacon.head = rawdb.ReadBeaconHeader(db, *number)
+	}
+	return beacon
+}
+
+// InsertHeader verifies a beacon header and stores it as the latest one, along
+// with its shard state if the header is the one of an epoch block. The header
+// must follow the latest beacon header. The first header stored is the one
+// following the beacon genesis block, which carries no signature, and is
+// signed by the beacon committee of the genesis.
+func (beacon *BeaconHeaders) InsertHeader(header *types.Header, shardState types.ShardState) error {
+	if binary.BigEndian.Uint32(header.ShardID[:]) != BeaconShardID {
+		return ErrNotBeaconHeader
+	}
+	beacon.mu.Lock()
+	defer beacon.mu.Unlock()
+	if header.Number == nil || header.Number.Uint64() != beacon.nextNumber() {
+		return consensus.ErrUnknownAncestor
+	}
+	if beacon.head == nil && header.ParentHash != beacon.genesis {
+		return ErrBeaconGenesisMismatch
+	}
+	if beacon.head != nil && header.ParentHash != beacon.head.Hash() {
+		return consensus.ErrUnknownAncestor
+	}
+	if header.ShardStateHash != (common.Hash{}) && shardState.Hash() != header.ShardStateHash {
+		return ErrShardStateNotMatch
+	}
+	if err := beacon.engine.VerifySeal(beacon, header); err != nil {
+		return err
+	}
+	number := header.Number.Uint64()
+	batch := beacon.db.NewBatch()
+	rawdb.WriteBeaconHeader(batch, header)
+	if header.ShardStateHash != (common.Hash{}) {
+		rawdb.WriteBeaconShardState(batch, number, shardState)
+		log.Info("Stored beacon shard state", "number", number, "hash", header.ShardStateHash)
+	}
+	rawdb.WriteHeadBeaconHeaderNumber(batch, number)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	beacon.head = header
+	return nil
+}
+
+// NextNumber returns the number of the next beacon header to insert.
+func (beacon *BeaconHeaders) NextNumber() uint64 {
+	beacon.mu.RLock()
+	defer beacon.mu.RUnlock()
+	return beacon.nextNumber()
+}
+
+func (beacon *BeaconHeaders) nextNumber() uint64 {
+	if beacon.head == nil {
+		return 1
+	}
+	return beacon.head.Number.Uint64() + 1
+}
+
+// ShardID returns the shard ID of the beacon chain.
+func (beacon *BeaconHeaders) ShardID() uint32 { return BeaconShardID }
+
+// Config retrieves the chain configuration of the shard node.
+func (beacon *BeaconHeaders) Config() *params.ChainConfig { return beacon.chainConfig }
+
+// HarmonyConfig retrieves the Harmony specific chain configuration.
+func (beacon *BeaconHeaders) HarmonyConfig() *configs.ChainConfig { return beacon.harmonyConfig }
+
+// CurrentHeader retrieves the latest beacon header, or nil if none is stored.
+func (beacon *BeaconHeaders) CurrentHeader() *types.Header {
+	beacon.mu.RLock()
+	defer beacon.mu.RUnlock()
+	return beacon.head
+}
+
+// GetHeaderByNumber retrieves the beacon header with the given number.
+func (beacon *BeaconHeaders) GetHeaderByNumber(number uint64) *types.Header {
+	return rawdb.ReadBeaconHeader(beacon.db, number)
+}
+
+// GetHeader retrieves the beacon header with the given hash and number.
+func (beacon *BeaconHeaders) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := beacon.GetHeaderByNumber(number)
+	if header == nil || header.Hash() != hash {
+		return nil
+	}
+	return header
+}
+
+// GetHeaderByHash retrieves the beacon header with the given hash.
+func (beacon *BeaconHeaders) GetHeaderByHash(hash common.Hash) *types.Header {
+	number := rawdb.ReadBeaconHeaderNumber(beacon.db, hash)
+	if number == nil {
+		return nil
+	}
+	return beacon.GetHeader(hash, *number)
+}
+
+// GetBlock returns nil, the bodies of the beacon blocks are not kept.
+func (beacon *BeaconHeaders) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return nil
+}
+
+// GetShardStateByNumber retrieves the shard state stored with the beacon epoch
+// block with the given number.
+func (beacon *BeaconHeaders) GetShardStateByNumber(number uint64) types.ShardState {
+	return rawdb.ReadBeaconShardState(beacon.db, number)
+}
+
+// GetRandSeedByNumber retrieves the random seed of the beacon block with the
+// given number, or 0 if the header is not stored.
+func (beacon *BeaconHeaders) GetRandSeedByNumber(number uint64) int64 {
+	header := beacon.GetHeaderByNumber(number)
+	if header == nil {
+		return 0
+	}
+	return int64(header.RandSeed)
+}
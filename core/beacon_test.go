@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+var errTestSeal = errors.New("invalid test seal")
+
+// testSealEngine accepts the seals of the headers without extra data.
+type testSealEngine struct {
+	consensus.Engine
+}
+
+func (testSealEngine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	if len(header.Extra) > 0 {
+		return errTestSeal
+	}
+	return nil
+}
+
+func makeBeaconHeaders(parent *types.Header, n int) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := range headers {
+		headers[i] = &types.Header{Number: big.NewInt(int64(i + 1)), RandSeed: uint64(i + 1)}
+		if parent != nil {
+			headers[i].ParentHash = parent.Hash()
+		}
+		parent = headers[i]
+	}
+	return headers
+}
+
+func TestBeaconHeaders(t *testing.T) {
+	chain, db := newTestChain(t, nil)
+	genesis := &types.Header{Number: big.NewInt(0), Extra: []byte("beacon genesis")}
+	beacon := NewBeaconHeaders(db, genesis.Hash(), params.TestChainConfig, chain.HarmonyConfig(), testSealEngine{})
+	if beacon.NextNumber() != 1 {
+		t.Fatalf("next beacon header of an empty store: %d", beacon.NextNumber())
+	}
+
+	epochBlock := chain.HarmonyConfig().EpochFirstBlock(1)
+	shardState := types.ShardState{{ShardID: 0, NodeList: []types.NodeID{"node1", "node2"}}, {ShardID: 1, NodeList: []types.NodeID{"node3"}}}
+	headers := makeBeaconHeaders(genesis, int(epochBlock))
+	epochHeader := headers[epochBlock-1]
+	epochHeader.ShardStateHash = shardState.Hash()
+
+	if err := beacon.InsertHeader(makeBeaconHeaders(nil, 1)[0], nil); err != ErrBeaconGenesisMismatch {
+		t.Errorf("beacon header of another genesis inserted: %v", err)
+	}
+
+	for _, header := range headers[:epochBlock-1] {
+		if err := beacon.InsertHeader(header, nil); err != nil {
+			t.Fatalf("failed to insert beacon header %d: %v", header.Number, err)
+		}
+	}
+	if err := beacon.InsertHeader(headers[0], nil); err != consensus.ErrUnknownAncestor {
+		t.Errorf("known beacon header inserted again: %v", err)
+	}
+	shardHeader := &types.Header{Number: epochHeader.Number, ParentHash: epochHeader.ParentHash, ShardID: types.EncodeShardID(1)}
+	if err := beacon.InsertHeader(shardHeader, nil); err != ErrNotBeaconHeader {
+		t.Errorf("header of another shard inserted: %v", err)
+	}
+	if err := beacon.InsertHeader(epochHeader, nil); err != ErrShardStateNotMatch {
+		t.Errorf("epoch header inserted without its shard state: %v", err)
+	}
+	forged := types.CopyHeader(epochHeader)
+	forged.Extra = []byte("forged")
+	if err := beacon.InsertHeader(forged, shardState); err != errTestSeal {
+		t.Errorf("beacon header with an invalid seal inserted: %v", err)
+	}
+	if err := beacon.InsertHeader(epochHeader, shardState); err != nil {
+		t.Fatalf("failed to insert epoch beacon header: %v", err)
+	}
+
+	// The beacon headers are read back from the database.
+	beacon = NewBeaconHeaders(db, genesis.Hash(), params.TestChainConfig, chain.HarmonyConfig(), testSealEngine{})
+	if head := beacon.CurrentHeader(); head == nil || head.Hash() != epochHeader.Hash() {
+		t.Fatalf("latest beacon header not restored: %v", head)
+	}
+	if header := beacon.GetHeaderByHash(headers[1].Hash()); header == nil || header.Number.Uint64() != 2 {
+		t.Errorf("beacon header not found by hash: %v", header)
+	}
+	if header := beacon.GetHeader(common.Hash{}, 2); header != nil {
+		t.Errorf("beacon header found with a wrong hash: %v", header)
+	}
+
+	// The new shard states are calculated from the beacon epoch block lagging
+	// behind the shard epoch, and from the local chain before the lag.
+	chain.SetBeaconHeaders(beacon)
+	ss := GetShardingStateFromBlockChain(chain, 1+BeaconEpochLag)
+	if ss == nil || ss.shardState.Hash() != shardState.Hash() || ss.rnd != int64(epochHeader.RandSeed) {
+		t.Errorf("sharding state not read from the beacon chain: %v", ss)
+	}
+	if ss := GetShardingStateFromBlockChain(chain, BeaconEpochLag); ss == nil || len(ss.shardState) != 0 || ss.rnd != 0 {
+		t.Errorf("sharding state before the lag read from the beacon chain: %v", ss)
+	}
+	if ss := GetShardingStateFromBlockChain(chain, 2+BeaconEpochLag); ss != nil {
+		t.Errorf("sharding state read without its beacon epoch block: %v", ss)
+	}
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/configs"
 	"github.com/harmony-one/harmony/internal/utils"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -56,19 +57,15 @@ var (
 )
 
 const (
-	bodyCacheLimit      = 256
-	blockCacheLimit     = 256
-	receiptsCacheLimit  = 32
-	maxFutureBlocks     = 256
-	maxTimeFutureBlocks = 30
-	badBlockLimit       = 10
-	triesInMemory       = 128
-	shardCacheLimit     = 2
-
-	// BlocksPerEpoch is the number of blocks in one epoch
-	// currently set to small number for testing
-	// in future, this need to be adjusted dynamically instead of constant
-	BlocksPerEpoch = 5
+	bodyCacheLimit       = 256
+	blockCacheLimit      = 256
+	receiptsCacheLimit   = 32
+	maxFutureBlocks      = 256
+	maxTimeFutureBlocks  = 30
+	badBlockLimit        = 10
+	defaultTriesInMemory = 128
+	shardCacheLimit      = 2
+	cxSealCacheLimit     = 256
 
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	BlockChainVersion = 3
@@ -80,6 +77,26 @@ type CacheConfig struct {
 	Disabled      bool          // Whether to disable trie write caching (archive node)
 	TrieNodeLimit int           // Memory limit (MB) at which to flush the current in-memory trie to disk
 	TrieTimeLimit time.Duration // Time limit after which to flush the current in-memory trie to disk
+	TriesInMemory uint64        // Number of recent block states kept referenced in memory (pruned node)
+}
+
+// DefaultCacheConfig returns the configuration of a pruned node, which keeps
+// the states of the recent blocks in memory and only periodically flushes a
+// full state to disk.
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		TrieNodeLimit: 256,
+		TrieTimeLimit: 5 * time.Minute,
+		TriesInMemory: defaultTriesInMemory,
+	}
+}
+
+// ArchiveCacheConfig returns the configuration of an archive node, which
+// writes the state of every block to disk.
+func ArchiveCacheConfig() *CacheConfig {
+	config := DefaultCacheConfig()
+	config.Disabled = true
+	return config
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -97,8 +114,9 @@ type CacheConfig struct {
 // included in the canonical one where as GetBlockByNumber always represents the
 // canonical chain.
 type BlockChain struct {
-	chainConfig *params.ChainConfig // Chain & network configuration
-	cacheConfig *CacheConfig        // Cache configuration for pruning
+	chainConfig   *params.ChainConfig  // Chain & network configuration
+	harmonyConfig *configs.ChainConfig // Harmony specific chain configuration
+	cacheConfig   *CacheConfig         // Cache configuration for pruning
 
 	db     ethdb.Database // Low level persistent database to store final content in
 	triegc *prque.Prque   // Priority queue mapping block numbers to tries to gc
@@ -112,6 +130,7 @@ type BlockChain struct {
 	logsFeed      event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
+	beacon        *BeaconHeaders // beacon chain followed by a shard node, nil if not followed
 
 	mu      sync.RWMutex // global mutex for locking chain operations
 	chainmu sync.RWMutex // blockchain insertion lock
@@ -128,6 +147,7 @@ type BlockChain struct {
 	blockCache      *lru.Cache     // Cache for the most recent entire blocks
 	futureBlocks    *lru.Cache     // future blocks are blocks added for later processing
 	shardStateCache *lru.Cache
+	cxSealCache     *lru.Cache // Hashes of the source blocks whose seals were verified for their cross-shard receipts
 
 	quit    chan struct{} // blockchain quit channel
 	running int32         // running must be called atomically
@@ -142,6 +162,8 @@ type BlockChain struct {
 
 	badBlocks      *lru.Cache              // Bad block cache
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
+
+	bloomIndexer *BloomIndexer // Bloom bits indexer of the canonical chain for log filtering
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -149,10 +171,14 @@ type BlockChain struct {
 // Processor.
 func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *params.ChainConfig, engine consensus.Engine, vmConfig vm.Config, shouldPreserve func(block *types.Block) bool) (*BlockChain, error) {
 	if cacheConfig == nil {
-		cacheConfig = &CacheConfig{
-			TrieNodeLimit: 256 * 1024 * 1024,
-			TrieTimeLimit: 5 * time.Minute,
-		}
+		cacheConfig = DefaultCacheConfig()
+	} else {
+		// The defaults are filled in a copy, the caller may share its configuration.
+		config := *cacheConfig
+		cacheConfig = &config
+	}
+	if cacheConfig.TriesInMemory == 0 {
+		cacheConfig.TriesInMemory = defaultTriesInMemory
 	}
 	bodyCache, _ := lru.New(bodyCacheLimit)
 	bodyRLPCache, _ := lru.New(bodyCacheLimit)
@@ -161,6 +187,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
 	shardCache, _ := lru.New(shardCacheLimit)
+	cxSealCache, _ := lru.New(cxSealCacheLimit)
 
 	bc := &BlockChain{
 		chainConfig:     chainConfig,
@@ -176,9 +203,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		blockCache:      blockCache,
 		futureBlocks:    futureBlocks,
 		shardStateCache: shardCache,
+		cxSealCache:     cxSealCache,
 		engine:          engine,
 		vmConfig:        vmConfig,
 		badBlocks:       badBlocks,
+		bloomIndexer:    NewBloomIndexer(db, BloomBitsBlocks),
 	}
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
@@ -192,9 +221,17 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if bc.genesisBlock == nil {
 		return nil, ErrNoGenesis
 	}
+	bc.harmonyConfig = bc.hc.HarmonyConfig()
+	if err := bc.harmonyConfig.Validate(); err != nil {
+		return nil, err
+	}
 	if err := bc.loadLastState(); err != nil {
 		return nil, err
 	}
+	// Catch up with the sections completed before the indexer existed.
+	if err := bc.bloomIndexer.Index(bc.CurrentBlock().NumberU64()); err != nil {
+		log.Error("Failed to index bloom bits", "err", err)
+	}
 	// Take ownership of this particular state
 	go bc.update()
 	return bc, nil
@@ -208,14 +245,18 @@ func (bc *BlockChain) ValidateNewBlock(block *types.Block, address common.Addres
 		return err
 	}
 
+	if err := bc.ValidateIncomingReceipts(block); err != nil {
+		return err
+	}
+
 	// Process block using the parent state as reference point.
-	receipts, _, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
+	receipts, cxReceipts, _, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
 	if err != nil {
 		bc.reportBlock(block, receipts, err)
 		return err
 	}
 
-	err = bc.Validator().ValidateState(block, bc.CurrentBlock(), state, receipts, usedGas)
+	err = bc.Validator().ValidateState(block, bc.CurrentBlock(), state, receipts, cxReceipts, usedGas)
 	if err != nil {
 		bc.reportBlock(block, receipts, err)
 		return err
@@ -421,6 +462,26 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.DB, error) {
 	return state.New(root, bc.stateCache)
 }
 
+// GetProof returns the header of the canonical block with the given number and
+// the Merkle proof of the given account and storage slots in the state of that
+// block. The proof can be checked with state.VerifyAccountProof against the
+// state root of the header.
+func (bc *BlockChain) GetProof(address common.Address, storageKeys []common.Hash, number uint64) (*types.Header, *state.AccountProof, error) {
+	header := bc.GetHeaderByNumber(number)
+	if header == nil {
+		return nil, nil, ErrUnknownBlock
+	}
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := statedb.GetAccountProof(address, storageKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, proof, nil
+}
+
 // Reset purges the entire blockchain, restoring it to its genesis state.
 func (bc *BlockChain) Reset() error {
 	return bc.ResetWithGenesisBlock(bc.genesisBlock)
@@ -503,6 +564,63 @@ func (bc *BlockChain) ExportN(w io.Writer, first uint64, last uint64) error {
 	return nil
 }
 
+// Import reads RLP encoded blocks, as written by Export, from the given reader
+// and inserts them into the chain in batches of batchSize blocks. Blocks already
+// in the chain are skipped. If checkSeals is set, the signatures of every block
+// are verified before insertion; a batch then ends at the first block of an
+// epoch, so that the blocks which follow are checked against the committee of
+// the shard state it stores. It returns the number of imported blocks.
+func (bc *BlockChain) Import(r io.Reader, batchSize int, checkSeals bool) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("import failed: invalid batch size %d", batchSize)
+	}
+	var (
+		stream   = rlp.NewStream(r, 0)
+		batch    = make(types.Blocks, 0, batchSize)
+		imported = 0
+		start    = time.Now()
+	)
+	insertBatch := func() error {
+		missing := make(types.Blocks, 0, len(batch))
+		for _, block := range batch {
+			if bc.HasBlock(block.Hash(), block.NumberU64()) {
+				continue
+			}
+			if checkSeals {
+				if err := bc.engine.VerifySeal(bc, block.Header()); err != nil {
+					return fmt.Errorf("import failed on #%d: invalid seal: %v", block.NumberU64(), err)
+				}
+			}
+			missing = append(missing, block)
+		}
+		batch = batch[:0]
+		if len(missing) == 0 {
+			return nil
+		}
+		if n, err := bc.InsertChain(missing); err != nil {
+			return fmt.Errorf("import failed on #%d: %v", missing[n].NumberU64(), err)
+		}
+		imported += len(missing)
+		log.Info("Importing blocks", "imported", imported, "number", missing[len(missing)-1].NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+		return nil
+	}
+	for {
+		block := new(types.Block)
+		if err := stream.Decode(block); err == io.EOF {
+			break
+		} else if err != nil {
+			return imported, fmt.Errorf("import failed: invalid block: %v", err)
+		}
+		batch = append(batch, block)
+		if len(batch) == batchSize || (checkSeals && block.NumberU64() > 0 && bc.harmonyConfig.IsEpochBlock(block.NumberU64())) {
+			if err := insertBatch(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	return imported, insertBatch()
+}
+
 // insert injects a new head block into the current block chain. This method
 // assumes that the block is indeed a true head. It will also reset the head
 // header and the head fast sync block to this very same block if they are older
@@ -650,6 +768,89 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetTransaction retrieves a canonical transaction by hash, along with the hash
+// and number of its block and its index in the block.
+func (bc *BlockChain) GetTransaction(hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
+	return rawdb.ReadTransaction(bc.db, hash)
+}
+
+// GetReceipt retrieves the receipt of a canonical transaction by hash, along
+// with the hash and number of its block and its index in the block.
+func (bc *BlockChain) GetReceipt(hash common.Hash) (*types.Receipt, common.Hash, uint64, uint64) {
+	return rawdb.ReadReceipt(bc.db, hash)
+}
+
+// ReadCXReceipts retrieves the outgoing cross-shard receipts of the block with
+// the given hash.
+func (bc *BlockChain) ReadCXReceipts(hash common.Hash) types.CXReceipts {
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil {
+		return nil
+	}
+	return rawdb.ReadCXReceipts(bc.db, hash, *number)
+}
+
+// IsCXReceiptsProofSpent checks whether the receipts of the given source block
+// were already credited by a canonical block of this shard.
+func (bc *BlockChain) IsCXReceiptsProofSpent(cxp *types.CXReceiptsProof) bool {
+	hash := rawdb.ReadCXReceiptsProofSpent(bc.db, cxp.ShardID(), cxp.BlockNumber())
+	if hash == (common.Hash{}) {
+		return false
+	}
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	return number != nil && rawdb.ReadCanonicalHash(bc.db, *number) == hash
+}
+
+// ValidateCXReceiptsProof checks that the given proof carries unspent receipts
+// to this shard, committed by a block signed by the source shard committee.
+func (bc *BlockChain) ValidateCXReceiptsProof(cxp *types.CXReceiptsProof) error {
+	if err := cxp.Verify(bc.ShardID()); err != nil {
+		return err
+	}
+	if cxp.ShardID() == bc.ShardID() {
+		return ErrCXReceiptsSameShard
+	}
+	if bc.IsCXReceiptsProofSpent(cxp) {
+		return ErrCXReceiptsSpent
+	}
+	// The seal of a source block is verified once, the proof being checked
+	// again when the block including it is verified and inserted.
+	hash := cxp.Header.Hash()
+	if bc.cxSealCache.Contains(hash) {
+		return nil
+	}
+	if err := bc.engine.VerifySeal(bc, cxp.Header); err != nil {
+		return err
+	}
+	bc.cxSealCache.Add(hash, true)
+	return nil
+}
+
+// ValidateIncomingReceipts validates the cross-shard receipts proofs included
+// in the given block.
+func (bc *BlockChain) ValidateIncomingReceipts(block *types.Block) error {
+	incxs := block.IncomingReceipts()
+	if hash := types.DeriveSha(incxs); len(incxs) > 0 && hash != block.Header().IncomingReceiptHash {
+		return fmt.Errorf("incoming receipt root hash mismatch: have %x, want %x", hash, block.Header().IncomingReceiptHash)
+	}
+	type source struct {
+		shardID uint32
+		number  uint64
+	}
+	seen := make(map[source]bool)
+	for _, cxp := range incxs {
+		if err := bc.ValidateCXReceiptsProof(cxp); err != nil {
+			return err
+		}
+		key := source{cxp.ShardID(), cxp.BlockNumber()}
+		if seen[key] {
+			return ErrCXReceiptsSpent
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
 // GetBlocksFromHash returns the block corresponding to hash and up to n-1 ancestors.
 // [deprecated by eth/62]
 func (bc *BlockChain) GetBlocksFromHash(hash common.Hash, n int) (blocks []*types.Block) {
@@ -703,11 +904,12 @@ func (bc *BlockChain) Stop() {
 	// We're writing three different states to catch different restart scenarios:
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
 	//  - HEAD-1:   So we don't do large reorgs if our HEAD becomes an uncle
-	//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
+	//  - HEAD-(N-1): So we have a hard limit on the number of blocks reexecuted,
+	//    N being the number of states kept in memory
 	if !bc.cacheConfig.Disabled {
 		triedb := bc.stateCache.TrieDB()
 
-		for _, offset := range []uint64{0, 1, triesInMemory - 1} {
+		for _, offset := range []uint64{0, 1, bc.cacheConfig.TriesInMemory - 1} {
 			if number := bc.CurrentBlock().NumberU64(); number > offset {
 				recent := bc.GetBlockByNumber(number - offset)
 
@@ -924,7 +1126,7 @@ func (bc *BlockChain) WriteBlockWithoutState(block *types.Block, td *big.Int) (e
 }
 
 // WriteBlockWithState writes the block and all associated state to the database.
-func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.Receipt, state *state.DB) (status WriteStatus, err error) {
+func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.Receipt, cxReceipts types.CXReceipts, state *state.DB) (status WriteStatus, err error) {
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
@@ -965,6 +1167,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		bc.triegc.Push(root, -int64(block.NumberU64()))
 
+		triesInMemory := bc.cacheConfig.TriesInMemory
 		if current := block.NumberU64(); current > triesInMemory {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
 			var (
@@ -983,7 +1186,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 				// If we're exceeding limits but haven't reached a large enough memory gap,
 				// warn the user that the system is becoming unstable.
 				if chosen < lastWrite+triesInMemory && bc.gcproc >= 2*bc.cacheConfig.TrieTimeLimit {
-					log.Info("State in memory for too long, committing", "time", bc.gcproc, "allowance", bc.cacheConfig.TrieTimeLimit, "optimum", float64(chosen-lastWrite)/triesInMemory)
+					log.Info("State in memory for too long, committing", "time", bc.gcproc, "allowance", bc.cacheConfig.TrieTimeLimit, "optimum", float64(chosen-lastWrite)/float64(triesInMemory))
 				}
 				// Flush an entire trie and restart the counters
 				triedb.Commit(header.Root, true)
@@ -1005,6 +1208,10 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// Write other block data using a batch.
 	batch := bc.db.NewBatch()
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteCXReceipts(batch, block.Hash(), block.NumberU64(), cxReceipts)
+	for _, cxp := range block.IncomingReceipts() {
+		rawdb.WriteCXReceiptsProofSpent(batch, cxp.ShardID(), cxp.BlockNumber(), block.Hash())
+	}
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -1046,6 +1253,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// Set new head.
 	if status == CanonStatTy {
 		bc.insert(block)
+		if err := bc.bloomIndexer.Index(block.NumberU64()); err != nil {
+			log.Error("Failed to index bloom bits", "number", block.NumberU64(), "err", err)
+		}
 	}
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
@@ -1201,13 +1411,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			return i, events, coalescedLogs, err
 		}
 		// Process block using the parent state as reference point.
-		receipts, logs, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
+		receipts, cxReceipts, logs, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			return i, events, coalescedLogs, err
 		}
 		// Validate the state using the default validator
-		err = bc.Validator().ValidateState(block, parent, state, receipts, usedGas)
+		err = bc.Validator().ValidateState(block, parent, state, receipts, cxReceipts, usedGas)
 		if err != nil {
 			bc.reportBlock(block, receipts, err)
 			return i, events, coalescedLogs, err
@@ -1215,7 +1425,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		proctime := time.Since(bstart)
 
 		// Write the block to the chain and get the status.
-		status, err := bc.WriteBlockWithState(block, receipts, state)
+		status, err := bc.WriteBlockWithState(block, receipts, cxReceipts, state)
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
@@ -1614,9 +1824,24 @@ func (bc *BlockChain) GetHeaderByNumber(number uint64) *types.Header {
 // Config retrieves the blockchain's chain configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
+// HarmonyConfig retrieves the blockchain's Harmony specific chain configuration.
+func (bc *BlockChain) HarmonyConfig() *configs.ChainConfig { return bc.harmonyConfig }
+
+// SetBeaconHeaders sets the beacon chain followed by a shard node.
+func (bc *BlockChain) SetBeaconHeaders(beacon *BeaconHeaders) { bc.beacon = beacon }
+
+// BeaconHeaders returns the beacon chain followed by a shard node, or nil.
+func (bc *BlockChain) BeaconHeaders() *BeaconHeaders { return bc.beacon }
+
 // Engine retrieves the blockchain's consensus engine.
 func (bc *BlockChain) Engine() consensus.Engine { return bc.engine }
 
+// ChainDb retrieves the database the blockchain is stored in.
+func (bc *BlockChain) ChainDb() ethdb.Database { return bc.db }
+
+// BloomIndexer retrieves the bloom bits indexer of the canonical chain.
+func (bc *BlockChain) BloomIndexer() *BloomIndexer { return bc.bloomIndexer }
+
 // SubscribeRemovedLogsEvent registers a subscription of RemovedLogsEvent.
 func (bc *BlockChain) SubscribeRemovedLogsEvent(ch chan<- RemovedLogsEvent) event.Subscription {
 	return bc.scope.Track(bc.rmLogsFeed.Subscribe(ch))
@@ -1689,13 +1914,16 @@ func (bc *BlockChain) GetNewShardState(block *types.Block) types.ShardState {
 	hash := block.Hash()
 	number := block.NumberU64()
 	// just ignore non-epoch block
-	if !CheckEpochBlock(number) {
+	if !bc.harmonyConfig.IsEpochBlock(number) {
 		return nil
 	}
 	shardState := bc.GetShardState(hash, number)
 	if shardState == nil {
-		epoch := GetEpochFromBlockNumber(number)
-		shardState = CalculateNewShardState(bc, epoch)
+		epoch := bc.harmonyConfig.EpochOfBlock(number)
+		if shardState = CalculateNewShardState(bc, epoch); shardState == nil {
+			utils.GetLogInstance().Warn("[resharding] beacon epoch block not stored, unable to calculate the new shard state", "number", number)
+			return nil
+		}
 		bc.shardStateCache.Add(hash, shardState)
 	}
 	return shardState
@@ -1705,6 +1933,9 @@ func (bc *BlockChain) GetNewShardState(block *types.Block) types.ShardState {
 func (bc *BlockChain) ValidateNewShardState(block *types.Block) error {
 	shardState := bc.GetNewShardState(block)
 	if shardState == nil {
+		if bc.harmonyConfig.IsEpochBlock(block.NumberU64()) {
+			return ErrBeaconShardStateMissing
+		}
 		return nil
 	}
 	if shardState.Hash() != block.Header().ShardStateHash {
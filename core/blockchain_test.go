@@ -0,0 +1,220 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+var (
+	testChainKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testChainSpec   = Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  GenesisAlloc{crypto.PubkeyToAddress(testChainKey.PublicKey): {Balance: big.NewInt(1000000000000000)}},
+	}
+)
+
+// newTestChain creates an empty blockchain on the test genesis.
+func newTestChain(t *testing.T, cacheConfig *CacheConfig) (*BlockChain, ethdb.Database) {
+	db := ethdb.NewMemDatabase()
+	testChainSpec.MustCommit(db)
+	chain, err := NewBlockChain(db, cacheConfig, params.TestChainConfig, consensus.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	return chain, db
+}
+
+// newTestStateChain inserts a chain of n blocks, each changing the state, into a
+// new blockchain with the given cache configuration.
+func newTestStateChain(t *testing.T, cacheConfig *CacheConfig, n int) (*BlockChain, ethdb.Database, []*types.Block) {
+	chain, db := newTestChain(t, cacheConfig)
+	// Blocks are generated on a separate database so that no state is written
+	// to the chain database before insertion.
+	genDB := ethdb.NewMemDatabase()
+	genesis := testChainSpec.MustCommit(genDB)
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, n, func(i int, gen *BlockGen) {
+		tx := types.NewTransaction(uint64(i), common.BytesToAddress([]byte{byte(i + 1)}), 0, big.NewInt(1000), params.TxGas, nil, nil)
+		signedTx, err := types.SignTx(tx, signer, testChainKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(signedTx)
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return chain, db, blocks
+}
+
+func TestPrunedState(t *testing.T) {
+	cacheConfig := DefaultCacheConfig()
+	cacheConfig.TriesInMemory = 4
+	chain, db, blocks := newTestStateChain(t, cacheConfig, 10)
+
+	for _, block := range blocks[:5] {
+		if chain.HasState(block.Root()) {
+			t.Errorf("state of block %d should be pruned", block.NumberU64())
+		}
+	}
+	for _, block := range blocks[6:] {
+		if !chain.HasState(block.Root()) {
+			t.Errorf("state of block %d should be kept", block.NumberU64())
+		}
+	}
+
+	// The recent states are flushed to disk on stop, so the head state is
+	// available after a restart.
+	chain.Stop()
+	restarted, err := NewBlockChain(db, cacheConfig, params.TestChainConfig, consensus.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to restart blockchain: %v", err)
+	}
+	defer restarted.Stop()
+	if head := restarted.CurrentBlock(); head.Hash() != blocks[9].Hash() {
+		t.Errorf("expected head %d after restart, got %d", blocks[9].NumberU64(), head.NumberU64())
+	}
+	if !restarted.HasState(blocks[9].Root()) {
+		t.Errorf("head state should be on disk after restart")
+	}
+}
+
+func TestArchiveState(t *testing.T) {
+	chain, _, blocks := newTestStateChain(t, ArchiveCacheConfig(), 10)
+	defer chain.Stop()
+
+	for _, block := range blocks {
+		if !chain.HasState(block.Root()) {
+			t.Errorf("state of block %d should be kept", block.NumberU64())
+		}
+	}
+}
+
+func TestCacheConfigNotModified(t *testing.T) {
+	cacheConfig := &CacheConfig{TrieNodeLimit: 256}
+	chain, _ := newTestChain(t, cacheConfig)
+	defer chain.Stop()
+	if cacheConfig.TriesInMemory != 0 {
+		t.Errorf("the cache configuration of the caller should be left unchanged")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	chain, _, blocks := newTestStateChain(t, nil, 10)
+	defer chain.Stop()
+	var exported bytes.Buffer
+	if err := chain.Export(&exported); err != nil {
+		t.Fatalf("failed to export chain: %v", err)
+	}
+
+	imported, _ := newTestChain(t, nil)
+	defer imported.Stop()
+	n, err := imported.Import(bytes.NewReader(exported.Bytes()), 3, false)
+	if err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if n != len(blocks) {
+		t.Errorf("expected %d imported blocks, got %d", len(blocks), n)
+	}
+	if head := imported.CurrentBlock(); head.Hash() != blocks[9].Hash() {
+		t.Errorf("expected head %d after import, got %d", blocks[9].NumberU64(), head.NumberU64())
+	}
+	if n, err := imported.Import(bytes.NewReader(exported.Bytes()), 3, false); err != nil || n != 0 {
+		t.Errorf("known blocks should be skipped: imported %d, error %v", n, err)
+	}
+
+	// Blocks without committee signatures are rejected when checking seals.
+	unsealed, _ := newTestChain(t, nil)
+	defer unsealed.Stop()
+	if _, err := unsealed.Import(bytes.NewReader(exported.Bytes()), 3, true); err == nil {
+		t.Errorf("unsigned blocks should fail the seal check")
+	}
+	if head := unsealed.CurrentBlock(); head.NumberU64() != 0 {
+		t.Errorf("no block should be imported, head is %d", head.NumberU64())
+	}
+}
+
+// epochSealEngine accepts the seals of the blocks whose epoch committee is
+// stored, like the committee engine does with the shard state of the first
+// block of the epoch.
+type epochSealEngine struct {
+	consensus.Engine
+}
+
+func (epochSealEngine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	number, config := header.Number.Uint64(), chain.HarmonyConfig()
+	if number == 0 || config.EpochOfBlock(number-1) == 0 {
+		return nil
+	}
+	if chain.(*BlockChain).GetShardStateByNumber(config.EpochFirstBlock(config.EpochOfBlock(number-1))) == nil {
+		return consensus.ErrUnverifiedCommittee
+	}
+	return nil
+}
+
+func TestImportAcrossEpochs(t *testing.T) {
+	chain, _, blocks := newTestStateChain(t, nil, 12)
+	defer chain.Stop()
+	var exported bytes.Buffer
+	if err := chain.Export(&exported); err != nil {
+		t.Fatalf("failed to export chain: %v", err)
+	}
+
+	db := ethdb.NewMemDatabase()
+	testChainSpec.MustCommit(db)
+	imported, err := NewBlockChain(db, nil, params.TestChainConfig, epochSealEngine{consensus.NewFaker()}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer imported.Stop()
+	// The batch covers two committee changes.
+	n, err := imported.Import(bytes.NewReader(exported.Bytes()), len(blocks), true)
+	if err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if n != len(blocks) || imported.CurrentBlock().Hash() != blocks[len(blocks)-1].Hash() {
+		t.Errorf("expected %d imported blocks up to the head, got %d, head %d", len(blocks), n, imported.CurrentBlock().NumberU64())
+	}
+}
+
+// sealCountingEngine counts the seals it verifies.
+type sealCountingEngine struct {
+	consensus.Engine
+	seals int
+}
+
+func (engine *sealCountingEngine) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	engine.seals++
+	return nil
+}
+
+func TestValidateCXReceiptsProofSealOnce(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	testChainSpec.MustCommit(db)
+	engine := &sealCountingEngine{Engine: consensus.NewFaker()}
+	chain, err := NewBlockChain(db, nil, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	cxs := types.CXReceipts{{TxHash: common.Hash{1}, To: common.Address{1}, ShardID: 0, ToShardID: chain.ShardID(), Amount: big.NewInt(10)}}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(5), ShardID: types.EncodeShardID(0)}, nil, nil, cxs, nil)
+	cxp := types.NewCXReceiptsProof(block.Header(), cxs, chain.ShardID())
+	for i := 0; i < 2; i++ {
+		if err := chain.ValidateCXReceiptsProof(cxp); err != nil {
+			t.Fatalf("valid proof should verify: %v", err)
+		}
+	}
+	if engine.seals != 1 {
+		t.Errorf("expected the source block seal to be verified once, got %d", engine.seals)
+	}
+}
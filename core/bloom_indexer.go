@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/bloombits"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// BloomBitsBlocks is the number of blocks a single bloom bit section vector
+// contains.
+const BloomBitsBlocks uint64 = 4096
+
+// BloomIndexer writes the rotated bloom bits of the canonical chain in sections
+// of a fixed number of blocks, so that log filters can find the blocks which may
+// contain an address or topic without checking every header. A section is indexed
+// as soon as its last block is inserted; committed blocks are final, so there is
+// no confirmation delay. The bits of a section are keyed by the hash of its last
+// block, so a section indexed on a chain that was rewound is simply not found.
+type BloomIndexer struct {
+	db       ethdb.Database
+	size     uint64 // number of blocks in a section
+	sections uint64 // number of indexed sections
+	lock     sync.Mutex
+}
+
+// NewBloomIndexer creates a bloom bits indexer for sections of the given size,
+// which must be a multiple of 8, resuming from the sections already indexed.
+func NewBloomIndexer(db ethdb.Database, size uint64) *BloomIndexer {
+	return &BloomIndexer{
+		db:       db,
+		size:     size,
+		sections: rawdb.ReadBloomBitsSections(db),
+	}
+}
+
+// SectionSize returns the number of blocks in a section.
+func (b *BloomIndexer) SectionSize() uint64 {
+	return b.size
+}
+
+// Sections returns the number of indexed sections.
+func (b *BloomIndexer) Sections() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.sections
+}
+
+// Index indexes all the complete sections up to the given canonical head.
+func (b *BloomIndexer) Index(head uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for (b.sections+1)*b.size <= head+1 {
+		if err := b.indexSection(b.sections); err != nil {
+			return err
+		}
+		b.sections++
+		rawdb.WriteBloomBitsSections(b.db, b.sections)
+	}
+	return nil
+}
+
+// indexSection generates and writes the bloom bits of the given section.
+func (b *BloomIndexer) indexSection(section uint64) error {
+	gen, err := bloombits.NewGenerator(uint(b.size))
+	if err != nil {
+		return err
+	}
+	var head common.Hash
+	for i := uint64(0); i < b.size; i++ {
+		number := section*b.size + i
+		head = rawdb.ReadCanonicalHash(b.db, number)
+		header := rawdb.ReadHeader(b.db, head, number)
+		if header == nil {
+			return fmt.Errorf("canonical block #%d unknown", number)
+		}
+		if err := gen.AddBloom(uint(i), header.Bloom); err != nil {
+			return err
+		}
+	}
+
+	batch := b.db.NewBatch()
+	for i := 0; i < types.BloomBitLength; i++ {
+		bits, err := gen.Bitset(uint(i))
+		if err != nil {
+			return err
+		}
+		rawdb.WriteBloomBits(batch, uint(i), section, head, bitutil.CompressBytes(bits))
+	}
+	return batch.Write()
+}
+
+// Match returns the numbers of the blocks in an indexed section which may match
+// the matcher's filter. An error is returned if the section is not indexed for
+// the current canonical chain.
+func (b *BloomIndexer) Match(section uint64, matcher *bloombits.Matcher) ([]uint64, error) {
+	if section >= b.Sections() {
+		return nil, fmt.Errorf("section %d not indexed", section)
+	}
+	head := rawdb.ReadCanonicalHash(b.db, (section+1)*b.size-1)
+	bitset, err := matcher.Match(b.size, func(bit uint) ([]byte, error) {
+		compressed, err := rawdb.ReadBloomBits(b.db, bit, section, head)
+		if err != nil {
+			return nil, err
+		}
+		return bitutil.DecompressBytes(compressed, int(b.size/8))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []uint64
+	for i := uint64(0); i < b.size; i++ {
+		if bitset[i/8]&(byte(1)<<(7-i%8)) != 0 {
+			matches = append(matches, section*b.size+i)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core/bloombits"
+)
+
+func TestBloomIndexer(t *testing.T) {
+	chain, db, _ := newTestStateChain(t, nil, 20)
+	defer chain.Stop()
+
+	indexer := NewBloomIndexer(db, 8)
+	if err := indexer.Index(6); err != nil || indexer.Sections() != 0 {
+		t.Fatalf("no section should be indexed before its last block: %d sections (%v)", indexer.Sections(), err)
+	}
+	if err := indexer.Index(20); err != nil || indexer.Sections() != 2 {
+		t.Fatalf("expected 2 indexed sections, got %d (%v)", indexer.Sections(), err)
+	}
+	// The progress is persisted.
+	if sections := NewBloomIndexer(db, 8).Sections(); sections != 2 {
+		t.Errorf("expected 2 sections after restart, got %d", sections)
+	}
+
+	// The test blocks only contain transfers without logs.
+	matches, err := indexer.Match(1, bloombits.NewMatcher([][][]byte{{common.Address{1}.Bytes()}}))
+	if err != nil || len(matches) != 0 {
+		t.Errorf("expected no matching blocks, got %v (%v)", matches, err)
+	}
+	matches, err = indexer.Match(1, bloombits.NewMatcher(nil))
+	if err != nil || len(matches) != 8 || matches[0] != 8 {
+		t.Errorf("expected all blocks of the section to match the empty filter, got %v (%v)", matches, err)
+	}
+	if _, err := indexer.Match(2, bloombits.NewMatcher(nil)); err == nil {
+		t.Errorf("expected error for section not indexed")
+	}
+}
@@ -0,0 +1,110 @@
+package bloombits
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errInvalidBitVector is returned if a retrieved bit vector does not match the
+// section size.
+var errInvalidBitVector = errors.New("bloom bit vector length mismatch")
+
+// bloomIndexes represents the bit indexes inside the bloom filter that belong
+// to some key.
+type bloomIndexes [3]uint
+
+// calcBloomIndexes returns the bloom filter bit indexes belonging to the given key.
+func calcBloomIndexes(b []byte) bloomIndexes {
+	b = crypto.Keccak256(b)
+
+	var idxs bloomIndexes
+	for i := 0; i < len(idxs); i++ {
+		idxs[i] = (uint(b[2*i])<<8)&2047 + uint(b[2*i+1])
+	}
+	return idxs
+}
+
+// Matcher matches the rotated bloom bits of a section against a filter made of
+// groups of keys. A block matches if it may contain any key of every group.
+type Matcher struct {
+	filters [][]bloomIndexes // Filter the system is matching for
+}
+
+// NewMatcher creates a new matcher for the given filter groups. An empty group,
+// or a group with a nil key, is a wildcard and matches every block.
+func NewMatcher(filters [][][]byte) *Matcher {
+	m := &Matcher{}
+	for _, filter := range filters {
+		// Gather the bit indexes of the filter rule, special casing the nil filter
+		if len(filter) == 0 {
+			continue
+		}
+		bloomBits := make([]bloomIndexes, len(filter))
+		for i, clause := range filter {
+			if clause == nil {
+				bloomBits = nil
+				break
+			}
+			bloomBits[i] = calcBloomIndexes(clause)
+		}
+		// Accumulate the filter rules if no nil rule was within
+		if bloomBits != nil {
+			m.filters = append(m.filters, bloomBits)
+		}
+	}
+	return m
+}
+
+// Match returns the bitset of the blocks in a section of sectionSize blocks that
+// may match the filter. The bit vector of a bloom bit in the section is retrieved
+// with the given function.
+func (m *Matcher) Match(sectionSize uint64, bitset func(bit uint) ([]byte, error)) ([]byte, error) {
+	cache := make(map[uint][]byte)
+	fetch := func(bit uint) ([]byte, error) {
+		if vector, ok := cache[bit]; ok {
+			return vector, nil
+		}
+		vector, err := bitset(bit)
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(vector)) != sectionSize/8 {
+			return nil, errInvalidBitVector
+		}
+		cache[bit] = vector
+		return vector, nil
+	}
+
+	result := filled(sectionSize/8, 0xff)
+	for _, group := range m.filters {
+		matches := filled(sectionSize/8, 0)
+		for _, clause := range group {
+			clauseMatches := filled(sectionSize/8, 0xff)
+			for _, bit := range clause {
+				vector, err := fetch(bit)
+				if err != nil {
+					return nil, err
+				}
+				for i := range clauseMatches {
+					clauseMatches[i] &= vector[i]
+				}
+			}
+			for i := range matches {
+				matches[i] |= clauseMatches[i]
+			}
+		}
+		for i := range result {
+			result[i] &= matches[i]
+		}
+	}
+	return result, nil
+}
+
+func filled(size uint64, b byte) []byte {
+	bytes := make([]byte, size)
+	for i := range bytes {
+		bytes[i] = b
+	}
+	return bytes
+}
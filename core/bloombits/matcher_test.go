@@ -0,0 +1,72 @@
+package bloombits
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestMatcher(t *testing.T) {
+	var (
+		address = common.Address{1}
+		topic   = common.Hash{2}
+		other   = common.Hash{3}
+	)
+	gen, err := NewGenerator(16)
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	for i := uint(0); i < 16; i++ {
+		var bloom types.Bloom
+		switch i {
+		case 3:
+			bloom.Add(address.Big())
+			bloom.Add(topic.Big())
+		case 9:
+			bloom.Add(address.Big())
+		case 12:
+			bloom.Add(other.Big())
+		}
+		if err := gen.AddBloom(i, bloom); err != nil {
+			t.Fatalf("failed to add bloom %d: %v", i, err)
+		}
+	}
+	bitset := func(bit uint) ([]byte, error) {
+		return gen.Bitset(bit)
+	}
+
+	tests := []struct {
+		filters [][][]byte
+		blocks  []uint
+	}{
+		{[][][]byte{{address.Bytes()}}, []uint{3, 9}},
+		{[][][]byte{{address.Bytes()}, {topic.Bytes()}}, []uint{3}},
+		{[][][]byte{{address.Bytes()}, {topic.Bytes(), other.Bytes()}}, []uint{3}},
+		{[][][]byte{{topic.Bytes(), other.Bytes()}}, []uint{3, 12}},
+		{[][][]byte{{address.Bytes()}, {nil}}, []uint{3, 9}},
+		{[][][]byte{{common.Address{4}.Bytes()}}, nil},
+	}
+	for i, test := range tests {
+		result, err := NewMatcher(test.filters).Match(16, bitset)
+		if err != nil {
+			t.Fatalf("test %d: failed to match: %v", i, err)
+		}
+		var blocks []uint
+		for j := uint(0); j < 16; j++ {
+			if result[j/8]&(byte(1)<<(7-j%8)) != 0 {
+				blocks = append(blocks, j)
+			}
+		}
+		if len(blocks) != len(test.blocks) {
+			t.Errorf("test %d: expected blocks %v, got %v", i, test.blocks, blocks)
+			continue
+		}
+		for j := range blocks {
+			if blocks[j] != test.blocks[j] {
+				t.Errorf("test %d: expected blocks %v, got %v", i, test.blocks, blocks)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// DefaultCallGasCap is the most gas a message call executed outside of a block
+// may use.
+const DefaultCallGasCap uint64 = 25000000
+
+// ErrGasEstimation is returned when a message fails even with the maximum gas
+// allowed for the estimation.
+var ErrGasEstimation = errors.New("gas required exceeds allowance or always failing transaction")
+
+// DoCall executes a message against the given state on top of the header, as if
+// it were included in the next block, and returns the EVM return data, the gas
+// used and whether the execution failed. The state is modified by the call, so
+// callers pass a copy of the state they want to keep. A message without gas, or
+// with more gas than a non-zero gasCap, is executed with gasCap.
+func DoCall(chain ChainContext, config *params.ChainConfig, statedb *state.DB, header *types.Header, msg types.Message, gasCap uint64) ([]byte, uint64, bool, error) {
+	if gasCap != 0 && (msg.Gas() == 0 || msg.Gas() > gasCap) {
+		msg = types.NewMessage(msg.From(), msg.To(), msg.Nonce(), msg.Value(), gasCap, msg.GasPrice(), msg.Data(), false)
+	}
+	author := header.Coinbase
+	context := NewEVMContext(msg, header, chain, &author)
+	evm := vm.NewEVM(context, statedb, config, vm.Config{})
+	gp := new(GasPool).AddGas(math.MaxUint64)
+	return ApplyMessage(evm, msg, gp)
+}
+
+// EstimateGas finds the lowest gas limit with which the message executes
+// successfully against the given state. The search is bounded by the smallest of
+// the gas of the message, if set, the header's gas limit and a non-zero gasCap.
+func EstimateGas(chain ChainContext, config *params.ChainConfig, statedb *state.DB, header *types.Header, msg types.Message, gasCap uint64) (uint64, error) {
+	limit := header.GasLimit
+	if msg.Gas() >= params.TxGas && msg.Gas() < limit {
+		limit = msg.Gas()
+	}
+	if gasCap != 0 && gasCap < limit {
+		limit = gasCap
+	}
+	executable := func(gas uint64) bool {
+		attempt := types.NewMessage(msg.From(), msg.To(), msg.Nonce(), msg.Value(), gas, msg.GasPrice(), msg.Data(), false)
+		_, _, failed, err := DoCall(chain, config, statedb.Copy(), header, attempt, limit)
+		return err == nil && !failed
+	}
+
+	lo, hi := params.TxGas-1, limit
+	if !executable(hi) {
+		return 0, ErrGasEstimation
+	}
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		if executable(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// Call executes a message call against the state of the canonical block with the
+// given number, with its gas capped at DefaultCallGasCap, and returns the EVM
+// return data, the gas used and whether the execution failed.
+func (bc *BlockChain) Call(msg types.Message, number uint64) ([]byte, uint64, bool, error) {
+	statedb, header, err := bc.stateAndHeaderAt(number)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return DoCall(bc, bc.chainConfig, statedb, header, msg, DefaultCallGasCap)
+}
+
+// EstimateGas returns the lowest gas limit with which the message call succeeds
+// against the state of the canonical block with the given number. The estimation
+// is bounded by the gas of the message, if set, the block gas limit and
+// DefaultCallGasCap.
+func (bc *BlockChain) EstimateGas(msg types.Message, number uint64) (uint64, error) {
+	statedb, header, err := bc.stateAndHeaderAt(number)
+	if err != nil {
+		return 0, err
+	}
+	return EstimateGas(bc, bc.chainConfig, statedb, header, msg, DefaultCallGasCap)
+}
+
+// stateAndHeaderAt returns the state and the header of the canonical block with
+// the given number.
+func (bc *BlockChain) stateAndHeaderAt(number uint64) (*state.DB, *types.Header, error) {
+	header := bc.GetHeaderByNumber(number)
+	if header == nil {
+		return nil, nil, ErrUnknownBlock
+	}
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statedb, header, nil
+}
@@ -24,9 +24,11 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 // BlockGen creates blocks for testing.
@@ -41,10 +43,12 @@ type BlockGen struct {
 	gasPool  *GasPool
 	txs      []*types.Transaction
 	receipts []*types.Receipt
+	outcxs   types.CXReceipts
 	uncles   []*types.Header
 
-	config *params.ChainConfig
-	engine consensus.Engine
+	config      *params.ChainConfig
+	engine      consensus.Engine
+	chainReader *fakeChainReader
 }
 
 // SetCoinbase sets the coinbase of the generated block.
@@ -99,13 +103,20 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 	if b.gasPool == nil {
 		b.SetCoinbase(common.Address{})
 	}
+	var chain ChainContext = b.chainReader
+	if bc != nil {
+		chain = bc
+	}
 	b.statedb.Prepare(tx.Hash(), common.Hash{}, len(b.txs))
-	receipt, _, err := ApplyTransaction(b.config, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
+	receipt, cxReceipt, _, err := ApplyTransaction(b.config, chain, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
 	if err != nil {
 		panic(err)
 	}
 	b.txs = append(b.txs, tx)
 	b.receipts = append(b.receipts, receipt)
+	if cxReceipt != nil {
+		b.outcxs = append(b.outcxs, cxReceipt)
+	}
 }
 
 // Number returns the block number of the block being generated.
@@ -166,9 +177,13 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		config = params.TestChainConfig
 	}
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
-	chainreader := &fakeChainReader{config: config}
+	harmonyConfig := rawdb.ReadHarmonyConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if harmonyConfig == nil {
+		harmonyConfig = configs.DefaultChainConfig
+	}
+	chainreader := &fakeChainReader{config: config, harmonyConfig: harmonyConfig, engine: engine}
 	genblock := func(i int, parent *types.Block, statedb *state.DB) (*types.Block, types.Receipts) {
-		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine}
+		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine, chainReader: chainreader}
 		b.header = makeHeader(chainreader, parent, statedb, b.engine)
 
 		// Mutate the state and block according to any hard-fork specs
@@ -189,7 +204,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		}
 		if b.engine != nil {
 			// Finalize and seal the block
-			block, _ := b.engine.Finalize(chainreader, b.header, statedb, b.txs, b.receipts)
+			block, _ := b.engine.Finalize(chainreader, b.header, statedb, b.txs, b.receipts, b.outcxs, nil)
 
 			// Write state changes to db
 			root, err := statedb.Commit(config.IsEIP158(b.header.Number))
@@ -259,8 +274,15 @@ func makeBlockChain(parent *types.Block, n int, engine consensus.Engine, db ethd
 }
 
 type fakeChainReader struct {
-	config  *params.ChainConfig
-	genesis *types.Block
+	config        *params.ChainConfig
+	harmonyConfig *configs.ChainConfig
+	engine        consensus.Engine
+	genesis       *types.Block
+}
+
+// Engine returns the consensus engine of the generated chain.
+func (cr *fakeChainReader) Engine() consensus.Engine {
+	return cr.engine
 }
 
 // Config returns the chain configuration.
@@ -268,6 +290,12 @@ func (cr *fakeChainReader) Config() *params.ChainConfig {
 	return cr.config
 }
 
+// HarmonyConfig returns the Harmony chain configuration stored with the genesis
+// of the generated chain, or the default one.
+func (cr *fakeChainReader) HarmonyConfig() *configs.ChainConfig {
+	return cr.harmonyConfig
+}
+
 func (cr *fakeChainReader) CurrentHeader() *types.Header                            { return nil }
 func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header           { return nil }
 func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
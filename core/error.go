@@ -22,6 +22,9 @@ var (
 	// ErrKnownBlock is returned when a block to import is already known locally.
 	ErrKnownBlock = errors.New("block already known")
 
+	// ErrUnknownBlock is returned when a requested block is not in the local chain.
+	ErrUnknownBlock = errors.New("unknown block")
+
 	// ErrGasLimitReached is returned by the gas pool if the amount of gas required
 	// by a transaction is higher than what's left in the block.
 	ErrGasLimitReached = errors.New("gas limit reached")
@@ -35,4 +38,25 @@ var (
 
 	// ErrShardStateNotMatch is returned if the calculated shardState hash not equal that in the block header
 	ErrShardStateNotMatch = errors.New("shard state root hash not match")
+
+	// ErrBeaconShardStateMissing is returned if the new shard state of an epoch
+	// block can't be calculated because the beacon epoch block it is read from
+	// is not stored yet.
+	ErrBeaconShardStateMissing = errors.New("beacon shard state of the new epoch not stored")
+
+	// ErrHarmonyConfigMismatch is returned if the chain configuration given to a
+	// node differs from the one stored with its genesis block.
+	ErrHarmonyConfigMismatch = errors.New("chain configuration differs from the stored one")
+
+	// ErrCXReceiptsSpent is returned if the cross-shard receipts of a source block
+	// were already credited on this shard.
+	ErrCXReceiptsSpent = errors.New("cross-shard receipts already spent")
+
+	// ErrCXReceiptsSameShard is returned if a cross-shard receipts proof comes
+	// from this shard.
+	ErrCXReceiptsSameShard = errors.New("cross-shard receipts from the same shard")
+
+	// ErrStakingMismatch is returned if the staking records of the state differ
+	// from the ones rebuilt from the staking transactions of the chain.
+	ErrStakingMismatch = errors.New("staking records do not match the staking transactions")
 )
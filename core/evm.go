@@ -21,8 +21,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 // ChainContext supports retrieving headers and consensus parameters from the
@@ -33,6 +35,9 @@ type ChainContext interface {
 
 	// GetHeader returns the hash corresponding to their hash.
 	GetHeader(common.Hash, uint64) *types.Header
+
+	// HarmonyConfig retrieves the Harmony specific chain configuration.
+	HarmonyConfig() *configs.ChainConfig
 }
 
 // NewEVMContext creates a new context for use in the EVM.
@@ -47,14 +52,16 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 	return vm.Context{
 		CanTransfer: CanTransfer,
 		Transfer:    Transfer,
+		CanReceive:  CanReceive,
 		GetHash:     GetHashFn(header, chain),
 		Origin:      msg.From(),
 		Coinbase:    beneficiary,
 		BlockNumber: new(big.Int).Set(header.Number),
 		Time:        new(big.Int).Set(header.Time),
 		//Difficulty:  new(big.Int).Set(header.Difficulty),
-		GasLimit: header.GasLimit,
-		GasPrice: new(big.Int).Set(msg.GasPrice()),
+		GasLimit:       header.GasLimit,
+		GasPrice:       new(big.Int).Set(msg.GasPrice()),
+		BLSPrecompiles: chain != nil && chain.HarmonyConfig().IsBLSPrecompiles(header.Number),
 	}
 }
 
@@ -90,6 +97,13 @@ func CanTransfer(db vm.StateDB, addr common.Address, amount *big.Int) bool {
 	return db.GetBalance(addr).Cmp(amount) >= 0
 }
 
+// CanReceive checks whether a contract may send funds to the address. The balance
+// of the staking address backs the stakes, so it is only credited by the staking
+// transactions.
+func CanReceive(addr common.Address) bool {
+	return addr != staking.Address
+}
+
 // Transfer subtracts amount from sender and adds amount to recipient using the given Db
 func Transfer(db vm.StateDB, sender, recipient common.Address, amount *big.Int) {
 	db.SubBalance(sender, amount)
@@ -22,39 +22,57 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
-	"os"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
-	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 //go:generate gencodec -type Genesis -field-override genesisSpecMarshaling -out gen_genesis.go
 //go:generate gencodec -type GenesisAccount -field-override genesisAccountMarshaling -out gen_genesis_account.go
 
-var errGenesisNoConfig = errors.New("genesis has no chain configuration")
+var (
+	errGenesisNoConfig         = errors.New("genesis has no chain configuration")
+	errGenesisCorrupted        = errors.New("stored genesis block does not match its hash")
+	errGenesisCommitteeNoMatch = errors.New("stored genesis committee does not match the genesis block")
+	errGenesisCommitteeMissing = errors.New("genesis block commits to a committee which is not stored")
+)
 
 // Genesis specifies the header fields, state of a genesis block. It also defines hard
 // fork switch-over blocks through the chain configuration.
 type Genesis struct {
-	Config     *params.ChainConfig `json:"config"`
-	Nonce      uint64              `json:"nonce"`
-	ShardID    uint32              `json:"shardID"`
-	Timestamp  uint64              `json:"timestamp"`
-	ExtraData  []byte              `json:"extraData"`
-	GasLimit   uint64              `json:"gasLimit"   gencodec:"required"`
-	Difficulty *big.Int            `json:"difficulty" gencodec:"required"`
-	Mixhash    common.Hash         `json:"mixHash"`
-	Coinbase   common.Address      `json:"coinbase"`
-	Alloc      GenesisAlloc        `json:"alloc"      gencodec:"required"`
+	Config        *params.ChainConfig  `json:"config"`
+	HarmonyConfig *configs.ChainConfig `json:"harmonyConfig"`
+	Nonce         uint64               `json:"nonce"`
+	ShardID       uint32               `json:"shardID"`
+	Timestamp     uint64               `json:"timestamp"`
+	ExtraData     []byte               `json:"extraData"`
+	GasLimit      uint64               `json:"gasLimit"   gencodec:"required"`
+	Difficulty    *big.Int             `json:"difficulty" gencodec:"required"`
+	Mixhash       common.Hash          `json:"mixHash"`
+	Coinbase      common.Address       `json:"coinbase"`
+	Alloc         GenesisAlloc         `json:"alloc"      gencodec:"required"`
+
+	// ShardState is the sharding state of the first epoch and Committee holds
+	// the serialized BLS public keys of the initial committee of this shard.
+	// ShardCommittees holds the ones of the other shards, which sign the
+	// cross-shard receipts sent to this shard. The mix digest of the genesis
+	// block commits to the committees.
+	ShardState      types.ShardState           `json:"shardState"`
+	Committee       []hexutil.Bytes            `json:"committee"`
+	ShardCommittees map[uint32][]hexutil.Bytes `json:"shardCommittees"`
 
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
@@ -138,6 +156,96 @@ func (e *GenesisMismatchError) Error() string {
 	return fmt.Sprintf("database already contains an incompatible genesis block (have %x, new %x)", e.Stored[:8], e.New[:8])
 }
 
+// LoadGenesis reads a JSON encoded genesis specification from the given file.
+// A missing EVM configuration is derived from the Harmony configuration for the
+// genesis shard.
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Decode over a copy of the default configuration so that fields missing
+	// from the file keep their default values.
+	config := *configs.DefaultChainConfig
+	genesis := &Genesis{HarmonyConfig: &config}
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file %s: %v", path, err)
+	}
+	if genesis.HarmonyConfig == nil {
+		genesis.HarmonyConfig = &config
+	}
+	if err := genesis.HarmonyConfig.Validate(); err != nil {
+		return nil, err
+	}
+	if genesis.Config == nil {
+		genesis.Config = genesis.HarmonyConfig.EVMConfig(genesis.ShardID)
+	}
+	return genesis, nil
+}
+
+// VerifyGenesis checks that db has been initialized with the given genesis.
+func VerifyGenesis(db ethdb.Database, genesis *Genesis) error {
+	stored := rawdb.ReadCanonicalHash(db, 0)
+	if (stored == common.Hash{}) {
+		return ErrNoGenesis
+	}
+	if hash := genesis.ToBlock(nil).Hash(); hash != stored {
+		return &GenesisMismatchError{stored, hash}
+	}
+	return nil
+}
+
+// VerifyStoredGenesis checks that the genesis block stored in db matches its
+// hash, and that the shard state and committee stored along with it are the ones
+// the block commits to.
+func VerifyStoredGenesis(db ethdb.Database) error {
+	hash := rawdb.ReadCanonicalHash(db, 0)
+	if (hash == common.Hash{}) {
+		return ErrNoGenesis
+	}
+	header := rawdb.ReadHeader(db, hash, 0)
+	if header == nil || header.Hash() != hash {
+		return errGenesisCorrupted
+	}
+	if (header.ShardStateHash != common.Hash{}) && rawdb.ReadShardState(db, hash, 0).Hash() != header.ShardStateHash {
+		return ErrShardStateNotMatch
+	}
+	keys, shardKeys := rawdb.ReadGenesisCommittee(db, hash), rawdb.ReadGenesisShardCommittees(db, hash)
+	if len(keys) == 0 && len(shardKeys) == 0 {
+		// A genesis block without committee has an empty mix digest.
+		if (header.MixDigest != common.Hash{}) {
+			return errGenesisCommitteeMissing
+		}
+		return nil
+	}
+	if committeeHash(keys, shardKeys) != header.MixDigest {
+		return errGenesisCommitteeNoMatch
+	}
+	return nil
+}
+
+// VerifyHarmonyConfig checks that the chain configuration stored with the
+// genesis block of db, if any, is the given one. A database without genesis
+// block is created with the given configuration.
+func VerifyHarmonyConfig(db ethdb.Database, config *configs.ChainConfig) error {
+	stored := rawdb.ReadHarmonyConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if stored == nil {
+		return nil
+	}
+	storedJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(storedJSON, configJSON) {
+		return ErrHarmonyConfigMismatch
+	}
+	return nil
+}
+
 // SetupGenesisBlock writes or updates the genesis block in db.
 // The block that will be used is:
 //
@@ -223,8 +331,7 @@ func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
 // to the given database (or discards it if nil).
 func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 	if db == nil {
-		utils.GetLogInstance().Error("db should be initialized")
-		os.Exit(1)
+		db = ethdb.NewMemDatabase()
 	}
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
 	for addr, account := range g.Alloc {
@@ -251,15 +358,22 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 		Root:       root,
 	}
 	if g.GasLimit == 0 {
-		head.GasLimit = 10000000000 // TODO(RJ): figure out better solution. // params.GenesisGasLimit
+		head.GasLimit = g.harmonyConfigOrDefault().GenesisGasLimit
 	}
 	if g.Difficulty == nil {
 		head.Difficulty = params.GenesisDifficulty
 	}
+	if len(g.ShardState) > 0 {
+		head.ShardStateHash = g.ShardState.Hash()
+	}
+	if len(g.Committee) > 0 || len(g.ShardCommittees) > 0 {
+		// The mix digest is not used without proof of work.
+		head.MixDigest = committeeHash(g.committeeKeys(), g.shardCommitteeKeys())
+	}
 	statedb.Commit(false)
 	statedb.Database().TrieDB().Commit(root, true)
 
-	return types.NewBlock(head, nil, nil)
+	return types.NewBlock(head, nil, nil, nil, nil)
 }
 
 // Commit writes the block and state of a genesis specification to the database.
@@ -281,9 +395,69 @@ func (g *Genesis) Commit(db ethdb.Database) (*types.Block, error) {
 		config = params.AllEthashProtocolChanges
 	}
 	rawdb.WriteChainConfig(db, block.Hash(), config)
+	rawdb.WriteHarmonyConfig(db, block.Hash(), g.harmonyConfigOrDefault())
+	if len(g.ShardState) > 0 {
+		rawdb.WriteShardState(db, block.Hash(), block.NumberU64(), g.ShardState)
+	}
+	if len(g.Committee) > 0 {
+		rawdb.WriteGenesisCommittee(db, block.Hash(), g.committeeKeys())
+	}
+	if len(g.ShardCommittees) > 0 {
+		rawdb.WriteGenesisShardCommittees(db, block.Hash(), g.shardCommitteeKeys())
+	}
 	return block, nil
 }
 
+// committeeKeys returns the serialized BLS public keys of the genesis committee.
+func (g *Genesis) committeeKeys() [][]byte {
+	return bytesList(g.Committee)
+}
+
+// shardCommitteeKeys returns the serialized BLS public keys of the genesis
+// committees of the other shards.
+func (g *Genesis) shardCommitteeKeys() map[uint32][][]byte {
+	keys := make(map[uint32][][]byte, len(g.ShardCommittees))
+	for shardID, committee := range g.ShardCommittees {
+		keys[shardID] = bytesList(committee)
+	}
+	return keys
+}
+
+func bytesList(list []hexutil.Bytes) [][]byte {
+	keys := make([][]byte, len(list))
+	for i, key := range list {
+		keys[i] = key
+	}
+	return keys
+}
+
+// committeeHash returns the hash a genesis block commits to its committees with.
+func committeeHash(keys [][]byte, shardKeys map[uint32][][]byte) common.Hash {
+	shardIDs := make([]uint32, 0, len(shardKeys))
+	for shardID := range shardKeys {
+		shardIDs = append(shardIDs, shardID)
+	}
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+	committees := []interface{}{keys}
+	for _, shardID := range shardIDs {
+		committees = append(committees, shardID, shardKeys[shardID])
+	}
+	data, err := rlp.EncodeToBytes(committees)
+	if err != nil {
+		log.Crit("Failed to RLP encode genesis committees", "err", err)
+	}
+	return crypto.Keccak256Hash(data)
+}
+
+// harmonyConfigOrDefault returns the Harmony chain configuration of the genesis,
+// falling back to the default configuration when none is given.
+func (g *Genesis) harmonyConfigOrDefault() *configs.ChainConfig {
+	if g.HarmonyConfig != nil {
+		return g.HarmonyConfig
+	}
+	return configs.DefaultChainConfig
+}
+
 // MustCommit writes the genesis block and state to db, panicking on error.
 // The block is committed as the canonical head block.
 func (g *Genesis) MustCommit(db ethdb.Database) *types.Block {
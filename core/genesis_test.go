@@ -0,0 +1,159 @@
+package core
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/internal/configs"
+)
+
+const testGenesisJSON = `{
+	"shardID": 1,
+	"harmonyConfig": {"blocksPerEpoch": 20, "numShards": 2, "receiptLogsBlock": 0, "blsPrecompilesBlock": 0},
+	"alloc": {
+		"0x0000000000000000000000000000000000000001": {"balance": 1000}
+	},
+	"shardState": [
+		{"ShardID": 0, "NodeList": ["node0", "node1"]},
+		{"ShardID": 1, "NodeList": ["node2", "node3"]}
+	],
+	"committee": ["0x0102", "0x0304"],
+	"shardCommittees": {"0": ["0x0506"]}
+}`
+
+func writeTestGenesis(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "genesis.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadGenesis(t *testing.T) {
+	path := writeTestGenesis(t, testGenesisJSON)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("failed to load genesis: %v", err)
+	}
+	if genesis.HarmonyConfig.BlocksPerEpoch != 20 || genesis.HarmonyConfig.NumShards != 2 {
+		t.Errorf("unexpected harmony config: %v", genesis.HarmonyConfig)
+	}
+	if genesis.HarmonyConfig.MaxCommitteeSize != configs.DefaultChainConfig.MaxCommitteeSize {
+		t.Errorf("missing fields should keep their default values")
+	}
+	if !genesis.HarmonyConfig.IsReceiptLogs(common.Big0) || !genesis.HarmonyConfig.IsBLSPrecompiles(common.Big0) {
+		t.Errorf("fork blocks of the genesis file not loaded")
+	}
+	if genesis.Config == nil || genesis.Config.ChainID.Int64() != 1 {
+		t.Errorf("EVM config should be derived from the shard ID")
+	}
+	if balance := genesis.Alloc[common.BytesToAddress([]byte{1})].Balance; balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("unexpected balance %v", balance)
+	}
+	if len(genesis.ShardState) != 2 || len(genesis.Committee) != 2 {
+		t.Errorf("shard state or committee not loaded")
+	}
+
+	invalidPath := writeTestGenesis(t, `{"harmonyConfig": {"blocksPerEpoch": 0}}`)
+	defer os.RemoveAll(filepath.Dir(invalidPath))
+	if _, err := LoadGenesis(invalidPath); err != configs.ErrInvalidEpochLength {
+		t.Errorf("expected %v, got %v", configs.ErrInvalidEpochLength, err)
+	}
+}
+
+func TestCommitAndVerifyGenesis(t *testing.T) {
+	path := writeTestGenesis(t, testGenesisJSON)
+	defer os.RemoveAll(filepath.Dir(path))
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("failed to load genesis: %v", err)
+	}
+
+	db := ethdb.NewMemDatabase()
+	if err := VerifyGenesis(db, genesis); err != ErrNoGenesis {
+		t.Errorf("expected %v, got %v", ErrNoGenesis, err)
+	}
+	block := genesis.MustCommit(db)
+	if err := VerifyGenesis(db, genesis); err != nil {
+		t.Errorf("stored genesis should match: %v", err)
+	}
+	if block.Header().ShardStateHash != genesis.ShardState.Hash() {
+		t.Errorf("genesis header should commit to the shard state")
+	}
+	if shardState := rawdb.ReadShardState(db, block.Hash(), 0); len(shardState) != 2 {
+		t.Errorf("shard state not stored")
+	}
+	if keys := rawdb.ReadGenesisCommittee(db, block.Hash()); len(keys) != 2 {
+		t.Errorf("committee not stored")
+	}
+	if keys := rawdb.ReadGenesisShardCommittees(db, block.Hash()); len(keys[0]) != 1 {
+		t.Errorf("shard committees not stored")
+	}
+	if config := rawdb.ReadHarmonyConfig(db, block.Hash()); config == nil || config.BlocksPerEpoch != 20 {
+		t.Errorf("harmony config not stored")
+	}
+
+	other := *genesis
+	other.Alloc = GenesisAlloc{common.BytesToAddress([]byte{2}): {Balance: big.NewInt(1)}}
+	if _, ok := VerifyGenesis(db, &other).(*GenesisMismatchError); !ok {
+		t.Errorf("expected genesis mismatch error")
+	}
+	other = *genesis
+	other.Committee = other.Committee[:1]
+	if _, ok := VerifyGenesis(db, &other).(*GenesisMismatchError); !ok {
+		t.Errorf("expected genesis mismatch error for another committee")
+	}
+	other = *genesis
+	other.ShardCommittees = nil
+	if _, ok := VerifyGenesis(db, &other).(*GenesisMismatchError); !ok {
+		t.Errorf("expected genesis mismatch error without the shard committees")
+	}
+
+	if err := VerifyStoredGenesis(db); err != nil {
+		t.Errorf("stored genesis should be consistent: %v", err)
+	}
+	rawdb.WriteGenesisCommittee(db, block.Hash(), [][]byte{{1}})
+	if err := VerifyStoredGenesis(db); err != errGenesisCommitteeNoMatch {
+		t.Errorf("expected %v, got %v", errGenesisCommitteeNoMatch, err)
+	}
+	rawdb.WriteGenesisCommittee(db, block.Hash(), nil)
+	rawdb.WriteGenesisShardCommittees(db, block.Hash(), nil)
+	if err := VerifyStoredGenesis(db); err != errGenesisCommitteeMissing {
+		t.Errorf("expected %v, got %v", errGenesisCommitteeMissing, err)
+	}
+}
+
+func TestVerifyHarmonyConfig(t *testing.T) {
+	path := writeTestGenesis(t, testGenesisJSON)
+	defer os.RemoveAll(filepath.Dir(path))
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("failed to load genesis: %v", err)
+	}
+
+	db := ethdb.NewMemDatabase()
+	if err := VerifyHarmonyConfig(db, configs.DefaultChainConfig); err != nil {
+		t.Errorf("any configuration should be accepted without genesis: %v", err)
+	}
+	genesis.MustCommit(db)
+	config := *genesis.HarmonyConfig
+	if err := VerifyHarmonyConfig(db, &config); err != nil {
+		t.Errorf("stored configuration should match: %v", err)
+	}
+	config.BlocksPerEpoch++
+	if err := VerifyHarmonyConfig(db, &config); err != ErrHarmonyConfigMismatch {
+		t.Errorf("expected %v, got %v", ErrHarmonyConfigMismatch, err)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/configs"
 	lru "github.com/hashicorp/golang-lru"
 )
 
@@ -48,7 +49,8 @@ const (
 // It is not thread safe either, the encapsulating chain structures should do
 // the necessary mutex locking/unlocking.
 type HeaderChain struct {
-	config *params.ChainConfig
+	config        *params.ChainConfig
+	harmonyConfig *configs.ChainConfig
 
 	chainDb       ethdb.Database
 	genesisHeader *types.Header
@@ -96,6 +98,11 @@ func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine c
 	if hc.genesisHeader == nil {
 		return nil, ErrNoGenesis
 	}
+	hc.harmonyConfig = rawdb.ReadHarmonyConfig(chainDb, hc.genesisHeader.Hash())
+	if hc.harmonyConfig == nil {
+		log.Warn("Found genesis block without harmony chain config, using default")
+		hc.harmonyConfig = configs.DefaultChainConfig
+	}
 
 	hc.currentHeader.Store(hc.genesisHeader)
 	if head := rawdb.ReadHeadBlockHash(chainDb); head != (common.Hash{}) {
@@ -497,6 +504,9 @@ func (hc *HeaderChain) SetGenesis(head *types.Header) {
 // Config retrieves the header chain's chain configuration.
 func (hc *HeaderChain) Config() *params.ChainConfig { return hc.config }
 
+// HarmonyConfig retrieves the header chain's Harmony specific chain configuration.
+func (hc *HeaderChain) HarmonyConfig() *configs.ChainConfig { return hc.harmonyConfig }
+
 // Engine retrieves the header chain's consensus engine.
 func (hc *HeaderChain) Engine() consensus.Engine { return hc.engine }
 
@@ -330,7 +330,7 @@ func ReadBlock(db DatabaseReader, hash common.Hash, number uint64) *types.Block
 	if body == nil {
 		return nil
 	}
-	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles, body.IncomingReceipts)
 }
 
 // WriteBlock serializes a block into the database, header and body separately.
@@ -398,3 +398,137 @@ func WriteShardState(db DatabaseWriter, hash common.Hash, number uint64, shardSt
 		log.Crit("Failed to store sharding state", "err", err)
 	}
 }
+
+// ReadCXReceipts retrieves the outgoing cross-shard receipts of a block
+func ReadCXReceipts(db DatabaseReader, hash common.Hash, number uint64) types.CXReceipts {
+	data, _ := db.Get(cxReceiptKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	cxReceipts := types.CXReceipts{}
+	if err := rlp.DecodeBytes(data, &cxReceipts); err != nil {
+		log.Error("Invalid cross-shard receipts RLP", "hash", hash, "number", number, "err", err)
+		return nil
+	}
+	return cxReceipts
+}
+
+// WriteCXReceipts stores the outgoing cross-shard receipts of a block
+func WriteCXReceipts(db DatabaseWriter, hash common.Hash, number uint64, cxReceipts types.CXReceipts) {
+	data, err := rlp.EncodeToBytes(cxReceipts)
+	if err != nil {
+		log.Crit("Failed to encode cross-shard receipts", "err", err)
+	}
+	if err := db.Put(cxReceiptKey(number, hash), data); err != nil {
+		log.Crit("Failed to store cross-shard receipts", "err", err)
+	}
+}
+
+// ReadCXReceiptsProofSpent retrieves the hash of the source block whose receipts
+// to this shard were credited, or an empty hash if they were not credited yet
+func ReadCXReceiptsProofSpent(db DatabaseReader, shardID uint32, number uint64) common.Hash {
+	data, _ := db.Get(cxSpentKey(shardID, number))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteCXReceiptsProofSpent marks the receipts of a source block as credited
+func WriteCXReceiptsProofSpent(db DatabaseWriter, shardID uint32, number uint64, hash common.Hash) {
+	if err := db.Put(cxSpentKey(shardID, number), hash.Bytes()); err != nil {
+		log.Crit("Failed to store cross-shard receipts spent mark", "err", err)
+	}
+}
+
+// ReadBeaconHeader retrieves the beacon header with the given number followed by
+// a shard node.
+func ReadBeaconHeader(db DatabaseReader, number uint64) *types.Header {
+	data, _ := db.Get(beaconHeaderKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	header := new(types.Header)
+	if err := rlp.Decode(bytes.NewReader(data), header); err != nil {
+		log.Error("Invalid beacon header RLP", "number", number, "err", err)
+		return nil
+	}
+	return header
+}
+
+// WriteBeaconHeader stores a beacon header followed by a shard node, along with
+// the hash to number mapping.
+func WriteBeaconHeader(db DatabaseWriter, header *types.Header) {
+	var (
+		hash    = header.Hash()
+		number  = header.Number.Uint64()
+		encoded = encodeBlockNumber(number)
+	)
+	if err := db.Put(beaconHeaderNumberKey(hash), encoded); err != nil {
+		log.Crit("Failed to store beacon hash to number mapping", "err", err)
+	}
+	data, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		log.Crit("Failed to RLP encode beacon header", "err", err)
+	}
+	if err := db.Put(beaconHeaderKey(number), data); err != nil {
+		log.Crit("Failed to store beacon header", "err", err)
+	}
+}
+
+// ReadBeaconHeaderNumber returns the number of the beacon header with the given
+// hash, or nil if the header is not stored.
+func ReadBeaconHeaderNumber(db DatabaseReader, hash common.Hash) *uint64 {
+	data, _ := db.Get(beaconHeaderNumberKey(hash))
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// ReadHeadBeaconHeaderNumber retrieves the number of the latest beacon header
+// followed by a shard node, or nil if no header is stored.
+func ReadHeadBeaconHeaderNumber(db DatabaseReader) *uint64 {
+	data, _ := db.Get(headBeaconHeaderKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteHeadBeaconHeaderNumber stores the number of the latest beacon header
+// followed by a shard node.
+func WriteHeadBeaconHeaderNumber(db DatabaseWriter, number uint64) {
+	if err := db.Put(headBeaconHeaderKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store last beacon header's number", "err", err)
+	}
+}
+
+// ReadBeaconShardState retrieves the shard state stored with the beacon epoch
+// block with the given number.
+func ReadBeaconShardState(db DatabaseReader, number uint64) types.ShardState {
+	data, _ := db.Get(beaconShardStateKey(number))
+	if len(data) == 0 {
+		return nil
+	}
+	shardState := types.ShardState{}
+	if err := rlp.DecodeBytes(data, &shardState); err != nil {
+		log.Error("Fail to decode beacon sharding state", "number", number, "err", err)
+		return nil
+	}
+	return shardState
+}
+
+// WriteBeaconShardState stores the shard state of the beacon epoch block with
+// the given number.
+func WriteBeaconShardState(db DatabaseWriter, number uint64, shardState types.ShardState) {
+	data, err := rlp.EncodeToBytes(shardState)
+	if err != nil {
+		log.Crit("Failed to encode beacon sharding state", "err", err)
+	}
+	if err := db.Put(beaconShardStateKey(number), data); err != nil {
+		log.Crit("Failed to store beacon sharding state", "err", err)
+	}
+}
@@ -17,6 +17,8 @@
 package rawdb
 
 import (
+	"encoding/binary"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -105,3 +107,23 @@ func WriteBloomBits(db DatabaseWriter, bit uint, section uint64, head common.Has
 		log.Crit("Failed to store bloom bits", "err", err)
 	}
 }
+
+// ReadBloomBitsSections retrieves the number of sections indexed by the bloom
+// bits indexer.
+func ReadBloomBitsSections(db DatabaseReader) uint64 {
+	data, _ := db.Get(bloomBitsSectionsKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteBloomBitsSections stores the number of sections indexed by the bloom
+// bits indexer.
+func WriteBloomBitsSections(db DatabaseWriter, sections uint64) {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], sections)
+	if err := db.Put(bloomBitsSectionsKey, data[:]); err != nil {
+		log.Crit("Failed to store bloom bits sections", "err", err)
+	}
+}
@@ -18,11 +18,13 @@ package rawdb
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 // ReadDatabaseVersion retrieves the version number of the database.
@@ -71,6 +73,162 @@ func WriteChainConfig(db DatabaseWriter, hash common.Hash, cfg *params.ChainConf
 	}
 }
 
+// ReadHarmonyConfig retrieves the Harmony chain configuration based on the given genesis hash.
+func ReadHarmonyConfig(db DatabaseReader, hash common.Hash) *configs.ChainConfig {
+	data, _ := db.Get(harmonyConfigKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var config configs.ChainConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Error("Invalid harmony chain config JSON", "hash", hash, "err", err)
+		return nil
+	}
+	return &config
+}
+
+// WriteHarmonyConfig writes the Harmony chain configuration to the database.
+func WriteHarmonyConfig(db DatabaseWriter, hash common.Hash, cfg *configs.ChainConfig) {
+	if cfg == nil {
+		return
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		log.Crit("Failed to JSON encode harmony chain config", "err", err)
+	}
+	if err := db.Put(harmonyConfigKey(hash), data); err != nil {
+		log.Crit("Failed to store harmony chain config", "err", err)
+	}
+}
+
+// ReadGenesisCommittee retrieves the serialized BLS public keys of the initial
+// committee based on the given genesis hash.
+func ReadGenesisCommittee(db DatabaseReader, hash common.Hash) [][]byte {
+	data, _ := db.Get(genesisCommitteeKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var keys [][]byte
+	if err := rlp.DecodeBytes(data, &keys); err != nil {
+		log.Error("Invalid genesis committee RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return keys
+}
+
+// WriteGenesisCommittee writes the serialized BLS public keys of the initial
+// committee to the database.
+func WriteGenesisCommittee(db DatabaseWriter, hash common.Hash, keys [][]byte) {
+	data, err := rlp.EncodeToBytes(keys)
+	if err != nil {
+		log.Crit("Failed to RLP encode genesis committee", "err", err)
+	}
+	if err := db.Put(genesisCommitteeKey(hash), data); err != nil {
+		log.Crit("Failed to store genesis committee", "err", err)
+	}
+}
+
+// shardCommittee is the RLP encoding of the initial committee of a shard.
+type shardCommittee struct {
+	ShardID uint32
+	Keys    [][]byte
+}
+
+// ReadGenesisShardCommittees retrieves the serialized BLS public keys of the
+// initial committees of the other shards based on the given genesis hash.
+func ReadGenesisShardCommittees(db DatabaseReader, hash common.Hash) map[uint32][][]byte {
+	data, _ := db.Get(genesisShardsKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var committees []shardCommittee
+	if err := rlp.DecodeBytes(data, &committees); err != nil {
+		log.Error("Invalid genesis shard committees RLP", "hash", hash, "err", err)
+		return nil
+	}
+	keys := make(map[uint32][][]byte, len(committees))
+	for _, committee := range committees {
+		keys[committee.ShardID] = committee.Keys
+	}
+	return keys
+}
+
+// WriteGenesisShardCommittees writes the serialized BLS public keys of the
+// initial committees of the other shards to the database.
+func WriteGenesisShardCommittees(db DatabaseWriter, hash common.Hash, keys map[uint32][][]byte) {
+	committees := make([]shardCommittee, 0, len(keys))
+	for shardID, shardKeys := range keys {
+		committees = append(committees, shardCommittee{shardID, shardKeys})
+	}
+	sort.Slice(committees, func(i, j int) bool { return committees[i].ShardID < committees[j].ShardID })
+	data, err := rlp.EncodeToBytes(committees)
+	if err != nil {
+		log.Crit("Failed to RLP encode genesis shard committees", "err", err)
+	}
+	if err := db.Put(genesisShardsKey(hash), data); err != nil {
+		log.Crit("Failed to store genesis shard committees", "err", err)
+	}
+}
+
+// ReadStakingCheckpoint retrieves the hash of the last block the staking
+// records were verified at.
+func ReadStakingCheckpoint(db DatabaseReader) common.Hash {
+	data, _ := db.Get(stakingCheckpointKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteStakingCheckpoint stores the hash of the last block the staking records
+// were verified at.
+func WriteStakingCheckpoint(db DatabaseWriter, hash common.Hash) {
+	if err := db.Put(stakingCheckpointKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store staking checkpoint", "err", err)
+	}
+}
+
+// SyncProgress is the progress of the block download of the state syncing,
+// allowing a restarted node to resume the download where it stopped.
+type SyncProgress struct {
+	Target  uint64        // Block number the download is heading to
+	Pending []common.Hash // Blocks downloaded and verified but not inserted yet
+	Peers   []string      // Addresses (ip:port) of the syncing peers
+}
+
+// ReadSyncProgress retrieves the progress of the block download, or nil if no
+// download is in progress.
+func ReadSyncProgress(db DatabaseReader) *SyncProgress {
+	data, _ := db.Get(syncProgressKey)
+	if len(data) == 0 {
+		return nil
+	}
+	progress := new(SyncProgress)
+	if err := rlp.DecodeBytes(data, progress); err != nil {
+		log.Error("Invalid sync progress RLP", "err", err)
+		return nil
+	}
+	return progress
+}
+
+// WriteSyncProgress stores the progress of the block download.
+func WriteSyncProgress(db DatabaseWriter, progress *SyncProgress) {
+	data, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		log.Crit("Failed to RLP encode sync progress", "err", err)
+	}
+	if err := db.Put(syncProgressKey, data); err != nil {
+		log.Crit("Failed to store sync progress", "err", err)
+	}
+}
+
+// DeleteSyncProgress removes the progress of a completed block download.
+func DeleteSyncProgress(db DatabaseDeleter) {
+	if err := db.Delete(syncProgressKey); err != nil {
+		log.Crit("Failed to delete sync progress", "err", err)
+	}
+}
+
 // ReadPreimage retrieves a single preimage of the provided hash.
 func ReadPreimage(db DatabaseReader, hash common.Hash) []byte {
 	data, _ := db.Get(preimageKey(hash))
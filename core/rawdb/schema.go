@@ -41,6 +41,15 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// stakingCheckpointKey tracks the hash of the last block the staking records were verified at.
+	stakingCheckpointKey = []byte("StakingCheckpoint")
+
+	// syncProgressKey tracks the progress of the block download of the state syncing.
+	syncProgressKey = []byte("SyncProgress")
+
+	// headBeaconHeaderKey tracks the number of the latest beacon header followed by a shard node.
+	headBeaconHeaderKey = []byte("LastBeaconHeader")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -55,12 +64,26 @@ var (
 
 	shardStatePrefix = []byte("ss") // shardStatePrefix + num (uint64 big endian) + hash -> shardState
 
+	cxReceiptPrefix = []byte("cx") // cxReceiptPrefix + num (uint64 big endian) + hash -> outgoing cross-shard receipts
+	cxSpentPrefix   = []byte("cs") // cxSpentPrefix + shard (uint32 big endian) + num (uint64 big endian) -> source block hash
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
+	harmonyConfigPrefix    = []byte("harmony-config-")    // harmonyConfigPrefix + genesis hash -> harmony chain config
+	genesisCommitteePrefix = []byte("genesis-committee-") // genesisCommitteePrefix + genesis hash -> initial committee BLS keys
+	genesisShardsPrefix    = []byte("genesis-shards-")    // genesisShardsPrefix + genesis hash -> initial committee BLS keys of the other shards
+
+	beaconHeaderPrefix       = []byte("beacon-header-")      // beaconHeaderPrefix + num (uint64 big endian) -> beacon header
+	beaconHeaderNumberPrefix = []byte("beacon-number-")      // beaconHeaderNumberPrefix + hash -> num (uint64 big endian)
+	beaconShardStatePrefix   = []byte("beacon-shard-state-") // beaconShardStatePrefix + num (uint64 big endian) -> beacon shard state
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
+	// bloomBitsSectionsKey tracks the number of sections indexed by the bloom bits indexer.
+	bloomBitsSectionsKey = append(append([]byte{}, BloomBitsIndexPrefix...), []byte("count")...)
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
@@ -135,6 +158,48 @@ func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
 
+// harmonyConfigKey = harmonyConfigPrefix + hash
+func harmonyConfigKey(hash common.Hash) []byte {
+	return append(harmonyConfigPrefix, hash.Bytes()...)
+}
+
+// genesisCommitteeKey = genesisCommitteePrefix + hash
+func genesisCommitteeKey(hash common.Hash) []byte {
+	return append(genesisCommitteePrefix, hash.Bytes()...)
+}
+
+// genesisShardsKey = genesisShardsPrefix + hash
+func genesisShardsKey(hash common.Hash) []byte {
+	return append(genesisShardsPrefix, hash.Bytes()...)
+}
+
+// cxReceiptKey = cxReceiptPrefix + num (uint64 big endian) + hash
+func cxReceiptKey(number uint64, hash common.Hash) []byte {
+	return append(append(cxReceiptPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// cxSpentKey = cxSpentPrefix + shard (uint32 big endian) + num (uint64 big endian)
+func cxSpentKey(shardID uint32, number uint64) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, shardID)
+	return append(append(cxSpentPrefix, key...), encodeBlockNumber(number)...)
+}
+
+// beaconHeaderKey = beaconHeaderPrefix + num (uint64 big endian)
+func beaconHeaderKey(number uint64) []byte {
+	return append(beaconHeaderPrefix, encodeBlockNumber(number)...)
+}
+
+// beaconHeaderNumberKey = beaconHeaderNumberPrefix + hash
+func beaconHeaderNumberKey(hash common.Hash) []byte {
+	return append(beaconHeaderNumberPrefix, hash.Bytes()...)
+}
+
+// beaconShardStateKey = beaconShardStatePrefix + num (uint64 big endian)
+func beaconShardStateKey(number uint64) []byte {
+	return append(beaconShardStatePrefix, encodeBlockNumber(number)...)
+}
+
 func shardStateKey(number uint64, hash common.Hash) []byte {
 	return append(append(shardStatePrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
@@ -11,6 +11,11 @@ import (
 const (
 	// InitialSeed is the initial random seed, a magic number to answer everything, remove later
 	InitialSeed int64 = 42
+
+	// BeaconEpochLag is the number of epochs the beacon epoch block read by a shard
+	// following the beacon chain lags behind the epoch of the shard, so that every
+	// node of the shard holds it when the new shard state is calculated.
+	BeaconEpochLag uint64 = 1
 )
 
 // ShardingState is data structure hold the sharding state
@@ -33,13 +38,25 @@ func (ss *ShardingState) sortCommitteeBySize() {
 }
 
 // assignNewNodes add new nodes into the N/2 active committees evenly
-func (ss *ShardingState) assignNewNodes(newNodeList []types.NodeID) {
+// the committees already holding maxCommitteeSize nodes are skipped, and the new nodes
+// left once all active committees are full wait for a later epoch
+func (ss *ShardingState) assignNewNodes(newNodeList []types.NodeID, maxCommitteeSize int) {
 	ss.sortCommitteeBySize()
 	numActiveShards := ss.numShards / 2
+	if numActiveShards == 0 {
+		return
+	}
 	Shuffle(newNodeList)
-	for i, nid := range newNodeList {
-		id := i % numActiveShards
+	id := 0
+	for _, nid := range newNodeList {
+		for i := 0; i < numActiveShards && len(ss.shardState[id].NodeList) >= maxCommitteeSize; i++ {
+			id = (id + 1) % numActiveShards
+		}
+		if len(ss.shardState[id].NodeList) >= maxCommitteeSize {
+			return
+		}
 		ss.shardState[id].NodeList = append(ss.shardState[id].NodeList, nid)
+		id = (id + 1) % numActiveShards
 	}
 }
 
@@ -67,9 +84,10 @@ func (ss *ShardingState) cuckooResharding(percent float64) {
 }
 
 // UpdateShardState will first add new nodes into shards, then use cuckoo rule to reshard to get new shard state
-func (ss *ShardingState) UpdateShardState(newNodeList []types.NodeID, percent float64) {
+// no new node is added to a committee of maxCommitteeSize nodes
+func (ss *ShardingState) UpdateShardState(newNodeList []types.NodeID, percent float64, maxCommitteeSize int) {
 	rand.Seed(ss.rnd)
-	ss.assignNewNodes(newNodeList)
+	ss.assignNewNodes(newNodeList, maxCommitteeSize)
 	ss.cuckooResharding(percent)
 }
 
@@ -83,35 +101,21 @@ func Shuffle(list []types.NodeID) {
 	})
 }
 
-// GetBlockNumberFromEpoch calculates the block number where epoch sharding information is stored
-func GetBlockNumberFromEpoch(epoch uint64) uint64 {
-	number := epoch * uint64(BlocksPerEpoch) // currently we use the first block in each epoch
-	return number
-}
-
-// GetEpochFromBlockNumber calculates the epoch number the block belongs to
-func GetEpochFromBlockNumber(blockNumber uint64) uint64 {
-	return blockNumber / uint64(BlocksPerEpoch)
-}
-
-// CheckEpochBlock check whethere a given block number is the one to store epoch information
-func CheckEpochBlock(blockNumber uint64) bool {
-	return blockNumber%uint64(BlocksPerEpoch) == 0
-}
-
-// GetPreviousEpochBlockNumber gets the epoch block number of previous epoch
-func GetPreviousEpochBlockNumber(blockNumber uint64) uint64 {
-	epoch := GetEpochFromBlockNumber(blockNumber)
-	if epoch == 1 {
-		// no previous epoch
-		return epoch
-	}
-	return GetBlockNumberFromEpoch(epoch - 1)
-}
-
-// GetShardingStateFromBlockChain will retrieve random seed and shard map from beacon chain for given a epoch
+// GetShardingStateFromBlockChain will retrieve random seed and shard map from beacon chain for given a epoch.
+// A shard node following the beacon chain reads the beacon epoch block BeaconEpochLag epochs behind, so that
+// every node of the shard uses the same beacon block whatever beacon headers it followed since. It returns nil
+// if that block or its shard state is not stored yet. The epochs before the lag use the local chain.
 func GetShardingStateFromBlockChain(bc *BlockChain, epoch uint64) *ShardingState {
-	number := GetBlockNumberFromEpoch(epoch)
+	config := bc.HarmonyConfig()
+	if beacon := bc.BeaconHeaders(); beacon != nil && epoch > BeaconEpochLag {
+		number := config.EpochFirstBlock(epoch - BeaconEpochLag)
+		shardState := beacon.GetShardStateByNumber(number)
+		if shardState == nil {
+			return nil
+		}
+		return &ShardingState{epoch: epoch, rnd: beacon.GetRandSeedByNumber(number), shardState: shardState, numShards: len(shardState)}
+	}
+	number := config.EpochFirstBlock(epoch)
 	shardState := bc.GetShardStateByNumber(number)
 	rnd := bc.GetRandSeedByNumber(number)
 
@@ -119,24 +123,36 @@ func GetShardingStateFromBlockChain(bc *BlockChain, epoch uint64) *ShardingState
 }
 
 // CalculateNewShardState get sharding state from previous epoch and calcualte sharding state for new epoch
+// It returns nil if the beacon epoch block the sharding state is read from is not stored yet.
 // TODO: currently, we just mock everything
 func CalculateNewShardState(bc *BlockChain, epoch uint64) types.ShardState {
+	config := bc.HarmonyConfig()
 	if epoch == 1 {
-		return fakeGetInitShardState()
+		return fakeGetInitShardState(int(config.NumShards), config.MinCommitteeSize)
 	}
 	ss := GetShardingStateFromBlockChain(bc, epoch-1)
+	if ss == nil {
+		return nil
+	}
 	newNodeList := fakeNewNodeList(ss.rnd)
-	percent := ss.calculateKickoutRate(newNodeList)
-	ss.UpdateShardState(newNodeList, percent)
+	percent := config.ClampKickoutRate(ss.calculateKickoutRate(newNodeList))
+	ss.UpdateShardState(newNodeList, percent, config.MaxCommitteeSize)
 	return ss.shardState
 }
 
 // calculateKickoutRate calculates the cuckoo rule kick out rate in order to make committee balanced
 func (ss *ShardingState) calculateKickoutRate(newNodeList []types.NodeID) float64 {
 	numActiveCommittees := ss.numShards / 2
+	if numActiveCommittees == 0 {
+		return 0
+	}
 	newNodesPerShard := len(newNodeList) / numActiveCommittees
 	ss.sortCommitteeBySize()
-	return float64(newNodesPerShard) / float64(len(ss.shardState[numActiveCommittees].NodeList))
+	numInactiveNodes := len(ss.shardState[numActiveCommittees].NodeList)
+	if numInactiveNodes == 0 {
+		return 0
+	}
+	return float64(newNodesPerShard) / float64(numInactiveNodes)
 }
 
 // FakeGenRandSeed generate random seed based on previous rnd seed; remove later after VRF implemented
@@ -146,13 +162,13 @@ func FakeGenRandSeed(seed int64) int64 {
 }
 
 // remove later after bootstrap codes ready
-func fakeGetInitShardState() types.ShardState {
+func fakeGetInitShardState(numShards, numNodesPerShard int) types.ShardState {
 	rand.Seed(InitialSeed)
 	shardState := types.ShardState{}
-	for i := 0; i < 6; i++ {
+	for i := 0; i < numShards; i++ {
 		sid := uint32(i)
 		com := types.Committee{ShardID: sid}
-		for j := 0; j < 10; j++ {
+		for j := 0; j < numNodesPerShard; j++ {
 			nid := strconv.Itoa(int(rand.Int63()))
 			com.NodeList = append(com.NodeList, types.NodeID(nid))
 		}
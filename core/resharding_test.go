@@ -3,10 +3,12 @@ package core
 import (
 	"fmt"
 	"testing"
+
+	"github.com/harmony-one/harmony/core/types"
 )
 
 func TestFakeGetInitShardState(t *testing.T) {
-	ss := fakeGetInitShardState()
+	ss := fakeGetInitShardState(6, 10)
 	for i := range ss {
 		fmt.Printf("ShardID: %v, NodeList: %v\n", ss[i].ShardID, ss[i].NodeList)
 	}
@@ -16,3 +18,21 @@ func TestFakeNewNodeList(t *testing.T) {
 	nodeList := fakeNewNodeList(42)
 	fmt.Println("newNodeList: ", nodeList)
 }
+
+func TestAssignNewNodesMaxCommitteeSize(t *testing.T) {
+	ss := &ShardingState{numShards: 4, shardState: types.ShardState{
+		{ShardID: 0, NodeList: []types.NodeID{"1", "2", "3"}},
+		{ShardID: 1, NodeList: []types.NodeID{"4", "5"}},
+		{ShardID: 2, NodeList: []types.NodeID{"6"}},
+		{ShardID: 3, NodeList: []types.NodeID{}},
+	}}
+	ss.assignNewNodes([]types.NodeID{"7", "8", "9"}, 3)
+	for _, committee := range ss.shardState {
+		if len(committee.NodeList) > 3 {
+			t.Errorf("committee of shard %v larger than the maximum: %v", committee.ShardID, committee.NodeList)
+		}
+	}
+	if total := len(ss.shardState[0].NodeList) + len(ss.shardState[1].NodeList); total != 6 {
+		t.Errorf("expected the active committees to be filled up to 6 nodes, got %v", total)
+	}
+}
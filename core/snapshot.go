@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// InsertSnapshotChain writes blocks and their receipts to the canonical chain
+// without executing them, for a node downloading the state of a recent block
+// instead of replaying the whole chain. The head of the chain is not moved, the
+// blocks become part of it once the state of a following block is committed
+// with CommitSnapshot.
+func (bc *BlockChain) InsertSnapshotChain(blocks types.Blocks, receipts []types.Receipts) (int, error) {
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+	if len(receipts) != len(blocks) {
+		return 0, fmt.Errorf("%d receipts for %d blocks", len(receipts), len(blocks))
+	}
+	parent := blocks[0]
+	ptd := bc.GetTd(parent.ParentHash(), parent.NumberU64()-1)
+	if ptd == nil {
+		return 0, fmt.Errorf("unknown parent of block #%d [%x…]", parent.Number(), parent.ParentHash().Bytes()[:4])
+	}
+	bc.chainmu.Lock()
+	for i, block := range blocks {
+		if i > 0 && (block.NumberU64() != blocks[i-1].NumberU64()+1 || block.ParentHash() != blocks[i-1].Hash()) {
+			bc.chainmu.Unlock()
+			return i, fmt.Errorf("non contiguous insert: item %d is #%d [%x…], item %d is #%d [%x…] (parent [%x…])", i-1, blocks[i-1].NumberU64(),
+				blocks[i-1].Hash().Bytes()[:4], i, block.NumberU64(), block.Hash().Bytes()[:4], block.ParentHash().Bytes()[:4])
+		}
+		// The total difficulty is computed like the one of the executed blocks.
+		ptd = new(big.Int).Add(ptd, block.Difficulty())
+		if err := bc.hc.WriteTd(block.Hash(), block.NumberU64(), ptd); err != nil {
+			bc.chainmu.Unlock()
+			return i, err
+		}
+		rawdb.WriteHeader(bc.db, block.Header())
+		rawdb.WriteCanonicalHash(bc.db, block.Hash(), block.NumberU64())
+	}
+	bc.chainmu.Unlock()
+	return bc.InsertReceiptChain(blocks, receipts)
+}
+
+// CommitSnapshot makes the block with the given hash, whose state was
+// downloaded, the current block of the chain.
+func (bc *BlockChain) CommitSnapshot(hash common.Hash) error {
+	if err := bc.FastSyncCommitHead(hash); err != nil {
+		return err
+	}
+	block := bc.CurrentBlock()
+
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	rawdb.WriteHeadBlockHash(bc.db, hash)
+	rawdb.WriteHeadHeaderHash(bc.db, hash)
+	rawdb.WriteHeadFastBlockHash(bc.db, hash)
+	bc.hc.SetCurrentHeader(block.Header())
+	bc.currentFastBlock.Store(block)
+
+	log.Info("Committed downloaded state", "number", block.Number(), "hash", hash, "root", block.Root())
+	return nil
+}
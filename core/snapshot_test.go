@@ -0,0 +1,96 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestSnapshotChain(t *testing.T) {
+	chain, db := newTestChain(t, nil)
+	genDB := ethdb.NewMemDatabase()
+	genesis := testChainSpec.MustCommit(genDB)
+
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	blocks, receipts := GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, 4, func(i int, gen *BlockGen) {
+		tx := types.NewTransaction(uint64(i), common.BytesToAddress([]byte{byte(i + 1)}), 0, big.NewInt(1000), params.TxGas, nil, nil)
+		signedTx, err := types.SignTx(tx, signer, testChainKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(signedTx)
+	})
+	pivot := blocks[2]
+
+	if _, err := chain.InsertSnapshotChain(blocks[1:3], receipts[1:3]); err == nil {
+		t.Fatal("expected blocks without known parent to be rejected")
+	}
+	if _, err := chain.InsertSnapshotChain(blocks[:3], receipts[:3]); err != nil {
+		t.Fatalf("failed to insert snapshot chain: %v", err)
+	}
+	if chain.CurrentBlock().NumberU64() != 0 {
+		t.Fatalf("expected the head not to move, got block %d", chain.CurrentBlock().NumberU64())
+	}
+	td := chain.GetTd(genesis.Hash(), 0)
+	for _, block := range blocks[:3] {
+		td = new(big.Int).Add(td, block.Difficulty())
+		if got := chain.GetTd(block.Hash(), block.NumberU64()); got == nil || got.Cmp(td) != 0 {
+			t.Errorf("expected total difficulty %v of block %d, got %v", td, block.NumberU64(), got)
+		}
+	}
+	if err := chain.CommitSnapshot(pivot.Hash()); err == nil {
+		t.Fatal("expected the pivot without state to be rejected")
+	}
+
+	// Download the state of the pivot from the generating database.
+	srcDb := state.NewDatabase(genDB)
+	sched := state.NewStateSync(pivot.Root(), db)
+	for queue := sched.Missing(0); len(queue) > 0; queue = sched.Missing(0) {
+		results := make([]trie.SyncResult, len(queue))
+		for i, hash := range queue {
+			data, err := srcDb.TrieDB().Node(hash)
+			if err != nil {
+				t.Fatalf("failed to retrieve node data for %x: %v", hash, err)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: data}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			t.Fatalf("failed to process result #%d: %v", index, err)
+		}
+		if index, err := sched.Commit(db); err != nil {
+			t.Fatalf("failed to commit data #%d: %v", index, err)
+		}
+	}
+	if err := chain.CommitSnapshot(pivot.Hash()); err != nil {
+		t.Fatalf("failed to commit snapshot: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != pivot.Hash() || rawdb.ReadHeadBlockHash(db) != pivot.Hash() {
+		t.Fatalf("expected head %x, got %x", pivot.Hash(), chain.CurrentBlock().Hash())
+	}
+	if r := chain.GetReceiptsByHash(blocks[1].Hash()); len(r) != 1 {
+		t.Errorf("expected 1 receipt in block 2, got %d", len(r))
+	}
+	if tx, _, _, _ := rawdb.ReadTransaction(db, blocks[0].Transactions()[0].Hash()); tx == nil {
+		t.Error("transaction of a snapshot block not indexed")
+	}
+
+	// The chain goes on from the pivot by executing the blocks.
+	if _, err := chain.InsertChain(blocks[3:]); err != nil {
+		t.Fatalf("failed to insert block after the pivot: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := statedb.GetBalance(common.BytesToAddress([]byte{4})); balance.Int64() != 1000 {
+		t.Errorf("expected balance 1000, got %v", balance)
+	}
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/staking"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// errStakesInterrupted is returned if the chain is stopped while its stakes are
+// being rebuilt.
+var errStakesInterrupted = errors.New("stakes rebuild interrupted")
+
+// RebuildStakes rebuilds the staking records of the current head block by
+// replaying the successful staking transactions of the canonical chain on top
+// of the staking records of the block with the given number, or of the genesis
+// block if the state of that block is not available. The block rewards are not
+// replayed, so only the stakes of the returned state are meaningful.
+func (bc *BlockChain) RebuildStakes(from uint64) (*state.DB, error) {
+	return bc.rebuildStakes(from, bc.CurrentBlock())
+}
+
+// rebuildStakes rebuilds the staking records of the given head block, which is
+// fixed by the caller so that blocks inserted meanwhile are not replayed.
+func (bc *BlockChain) rebuildStakes(from uint64, head *types.Block) (*state.DB, error) {
+	if from > head.NumberU64() {
+		return nil, ErrUnknownBlock
+	}
+	header := bc.GetHeaderByNumber(from)
+	if header == nil {
+		return nil, ErrUnknownBlock
+	}
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil && from != 0 {
+		log.Warn("Checkpoint state not available, rebuilding stakes from genesis", "number", from, "err", err)
+		from = 0
+		statedb, err = bc.StateAt(bc.Genesis().Root())
+	}
+	if err != nil {
+		return nil, err
+	}
+	for number := from + 1; number <= head.NumberU64(); number++ {
+		if bc.getProcInterrupt() {
+			return nil, errStakesInterrupted
+		}
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return nil, ErrUnknownBlock
+		}
+		if err := replayStakingTransactions(bc, statedb, block); err != nil {
+			return nil, err
+		}
+		if config := bc.HarmonyConfig(); config != nil && config.IsEpochBlock(number) {
+			staking.ReleaseUnbonded(statedb, number, config.UnbondingReleaseBlock)
+		}
+	}
+	return statedb, nil
+}
+
+// replayStakingTransactions applies the staking messages of the successful
+// staking transactions of a block. The senders are credited with the staked
+// value beforehand, as the balances of the rebuilt state are not tracked.
+// Staking transactions can't be cross-shard, so cross-shard transactions to the
+// staking address are skipped. A successful transaction which can't be replayed
+// means the staking records can't be trusted, so its error is returned.
+func replayStakingTransactions(bc *BlockChain, statedb *state.DB, block *types.Block) error {
+	receipts := bc.GetReceiptsByHash(block.Hash())
+	signer := types.MakeSigner(bc.chainConfig, block.Number())
+	for i, tx := range block.Transactions() {
+		if tx.To() == nil || *tx.To() != staking.Address || tx.ToShardID() != tx.ShardID() {
+			continue
+		}
+		if i >= len(receipts) {
+			return fmt.Errorf("missing receipt of transaction %x in block %d", tx.Hash(), block.NumberU64())
+		}
+		if receipts[i].Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		msg, err := staking.DecodeMessage(tx.Data())
+		if err != nil {
+			return fmt.Errorf("failed to replay staking transaction %x in block %d: %v", tx.Hash(), block.NumberU64(), err)
+		}
+		if msg.Directive == staking.CollectRewards {
+			// The rewards are not replayed and collecting them leaves the stakes unchanged.
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("failed to replay staking transaction %x in block %d: %v", tx.Hash(), block.NumberU64(), err)
+		}
+		statedb.AddBalance(from, tx.Value())
+		if err := staking.Apply(statedb, block.NumberU64(), from, tx.Value(), msg); err != nil {
+			return fmt.Errorf("failed to replay staking transaction %x in block %d: %v", tx.Hash(), block.NumberU64(), err)
+		}
+	}
+	return nil
+}
+
+// VerifyStakes checks the staking records of the current head block against
+// the ones rebuilt from the block with the given number.
+func (bc *BlockChain) VerifyStakes(from uint64) error {
+	return bc.verifyStakes(from, bc.CurrentBlock())
+}
+
+// verifyStakes checks the staking records of the given head block against the
+// ones rebuilt from the block with the given number.
+func (bc *BlockChain) verifyStakes(from uint64, head *types.Block) error {
+	current, err := bc.StateAt(head.Root())
+	if err != nil {
+		return err
+	}
+	rebuilt, err := bc.rebuildStakes(from, head)
+	if err != nil {
+		return err
+	}
+	if err := staking.CompareStakes(staking.Dump(current), staking.Dump(rebuilt)); err != nil {
+		return fmt.Errorf("%v: %v", ErrStakingMismatch, err)
+	}
+	return nil
+}
+
+// VerifyStakesSinceCheckpoint verifies the staking records of the current head
+// block from the last verified canonical block, and records the head block as
+// the new checkpoint, so that only the blocks inserted since are replayed by the
+// next check. The checkpoint is left unchanged if the check fails. It returns
+// the number of the checkpoint used.
+func (bc *BlockChain) VerifyStakesSinceCheckpoint() (uint64, error) {
+	from := uint64(0)
+	if hash := rawdb.ReadStakingCheckpoint(bc.db); hash != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(bc.db, hash); number != nil && rawdb.ReadCanonicalHash(bc.db, *number) == hash {
+			from = *number
+		}
+	}
+	head := bc.CurrentBlock()
+	if err := bc.verifyStakes(from, head); err != nil {
+		return from, err
+	}
+	rawdb.WriteStakingCheckpoint(bc.db, head.Hash())
+	return from, nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/staking"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/crypto/pki"
+)
+
+func TestRebuildStakes(t *testing.T) {
+	chain, db := newTestChain(t, nil)
+	genDB := ethdb.NewMemDatabase()
+	genesis := testChainSpec.MustCommit(genDB)
+
+	sender := crypto.PubkeyToAddress(testChainKey.PublicKey)
+	messages := []*staking.Message{
+		{Directive: staking.CreateValidator, BLSPublicKey: pki.GetBLSPrivateKeyFromInt(1).GetPublicKey().Serialize()},
+		{Directive: staking.Delegate, Validator: sender},
+		{Directive: staking.Undelegate, Validator: sender, Amount: big.NewInt(300)},
+		// Fails, the stake is too low.
+		{Directive: staking.Undelegate, Validator: sender, Amount: big.NewInt(5000)},
+	}
+	values := []int64{1000, 500, 0, 0}
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, len(messages), func(i int, gen *BlockGen) {
+		tx, err := staking.NewTransaction(uint64(i), 0, big.NewInt(values[i]), 100000, nil, messages[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedTx, err := types.SignTx(tx, signer, testChainKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen.AddTx(signedTx)
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	for _, from := range []uint64{0, 2} {
+		rebuilt, err := chain.RebuildStakes(from)
+		if err != nil {
+			t.Fatalf("failed to rebuild stakes from block %d: %v", from, err)
+		}
+		if stake := staking.Stakes(rebuilt)[sender]; stake == nil || stake.Int64() != 1200 {
+			t.Errorf("expected rebuilt stake 1200 from block %d, got %v", from, stake)
+		}
+		if queue := staking.UnbondingQueue(rebuilt); len(queue) != 1 || queue[0].Block != 3 {
+			t.Errorf("unexpected rebuilt unbonding queue from block %d: %v", from, queue)
+		}
+	}
+
+	checkpoint, err := chain.VerifyStakesSinceCheckpoint()
+	if err != nil || checkpoint != 0 {
+		t.Fatalf("expected stakes verified from genesis, got checkpoint %d, error %v", checkpoint, err)
+	}
+	if hash := rawdb.ReadStakingCheckpoint(db); hash != chain.CurrentBlock().Hash() {
+		t.Errorf("expected checkpoint %x, got %x", chain.CurrentBlock().Hash(), hash)
+	}
+	if checkpoint, err := chain.VerifyStakesSinceCheckpoint(); err != nil || checkpoint != 4 {
+		t.Errorf("expected stakes verified from block 4, got checkpoint %d, error %v", checkpoint, err)
+	}
+
+	// A checkpoint which is not a canonical block is ignored.
+	rawdb.WriteStakingCheckpoint(db, common.HexToHash("0x01"))
+	if checkpoint, err := chain.VerifyStakesSinceCheckpoint(); err != nil || checkpoint != 0 {
+		t.Errorf("expected stakes verified from genesis, got checkpoint %d, error %v", checkpoint, err)
+	}
+}
+
+func TestReplaySkipsCrossShardStakingTransactions(t *testing.T) {
+	chain, _ := newTestChain(t, nil)
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := (&staking.Message{Directive: staking.Delegate, Validator: common.HexToAddress("0x01")}).Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.NewCrossShardTransaction(0, staking.Address, 0, 1, big.NewInt(1000), 100000, nil, data)
+	// The block has no stored receipts, so a replayed transaction fails.
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil, nil)
+	if err := replayStakingTransactions(chain, statedb, block); err != nil {
+		t.Errorf("expected cross-shard staking transaction skipped, got %v", err)
+	}
+}
+
+func TestReplayFailsOnInvalidStakingTransaction(t *testing.T) {
+	chain, db := newTestChain(t, nil)
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A staking transaction with a successful receipt which can't be replayed.
+	tx := types.NewTransaction(0, staking.Address, 0, big.NewInt(0), 100000, nil, []byte{0x01})
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil, nil)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteReceipts(db, block.Hash(), 1, types.Receipts{{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}})
+	err = replayStakingTransactions(chain, statedb, block)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("%x", tx.Hash())) {
+		t.Errorf("expected replay error naming transaction %x, got %v", tx.Hash(), err)
+	}
+}
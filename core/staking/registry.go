@@ -0,0 +1,86 @@
+package staking
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// Registry is a snapshot of the staking records of a state.
+type Registry struct {
+	Validators []*ValidatorRecord `json:"validators"`
+	Unbonding  []*Unbonding       `json:"unbonding"`
+}
+
+// ValidatorRecord is the record of a validator along with its delegations.
+type ValidatorRecord struct {
+	Address      common.Address `json:"address"`
+	BLSPublicKey hexutil.Bytes  `json:"blsPublicKey"`
+	Stake        *big.Int       `json:"stake"`
+	Delegations  []*Delegation  `json:"delegations"`
+}
+
+// Dump returns the staking records of a state.
+func Dump(state vm.StateDB) *Registry {
+	registry := &Registry{Unbonding: UnbondingQueue(state)}
+	for _, validator := range Validators(state) {
+		registry.Validators = append(registry.Validators, &ValidatorRecord{
+			Address:      validator.Address,
+			BLSPublicKey: validator.BLSPublicKey,
+			Stake:        validator.Stake,
+			Delegations:  Delegations(state, validator.Address),
+		})
+	}
+	return registry
+}
+
+// CompareStakes checks that two registries hold the same validators, stakes
+// and withdrawn stakes. The rewards are not compared. It returns an error
+// describing the first difference found.
+func CompareStakes(registry, expected *Registry) error {
+	if len(registry.Validators) != len(expected.Validators) {
+		return fmt.Errorf("%d validators, expected %d", len(registry.Validators), len(expected.Validators))
+	}
+	validators := make(map[common.Address]*ValidatorRecord)
+	for _, validator := range registry.Validators {
+		validators[validator.Address] = validator
+	}
+	for _, want := range expected.Validators {
+		got, ok := validators[want.Address]
+		if !ok {
+			return fmt.Errorf("validator %x missing", want.Address)
+		}
+		if !bytes.Equal(got.BLSPublicKey, want.BLSPublicKey) {
+			return fmt.Errorf("validator %x has BLS key %x, expected %x", want.Address, got.BLSPublicKey, want.BLSPublicKey)
+		}
+		if got.Stake.Cmp(want.Stake) != 0 {
+			return fmt.Errorf("validator %x has stake %v, expected %v", want.Address, got.Stake, want.Stake)
+		}
+		if len(got.Delegations) != len(want.Delegations) {
+			return fmt.Errorf("validator %x has %d delegations, expected %d", want.Address, len(got.Delegations), len(want.Delegations))
+		}
+		amounts := make(map[common.Address]*big.Int)
+		for _, delegation := range got.Delegations {
+			amounts[delegation.Delegator] = delegation.Amount
+		}
+		for _, delegation := range want.Delegations {
+			if amount, ok := amounts[delegation.Delegator]; !ok || amount.Cmp(delegation.Amount) != 0 {
+				return fmt.Errorf("delegation of %x on validator %x is %v, expected %v", delegation.Delegator, want.Address, amount, delegation.Amount)
+			}
+		}
+	}
+	if len(registry.Unbonding) != len(expected.Unbonding) {
+		return fmt.Errorf("%d withdrawn stakes, expected %d", len(registry.Unbonding), len(expected.Unbonding))
+	}
+	for i, want := range expected.Unbonding {
+		got := registry.Unbonding[i]
+		if got.Validator != want.Validator || got.Delegator != want.Delegator || got.Amount.Cmp(want.Amount) != 0 || got.Block != want.Block {
+			return fmt.Errorf("withdrawn stake %d is %+v, expected %+v", i, got, want)
+		}
+	}
+	return nil
+}
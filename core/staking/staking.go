@@ -0,0 +1,427 @@
+// Package staking implements the native staking transactions. A staking
+// transaction is a transaction sent to the staking address, with an RLP encoded
+// Message as data. The validator records and the delegations are kept in the
+// storage of the staking address, which also holds the staked funds and the
+// rewards not collected yet.
+package staking
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/crypto/pki"
+)
+
+// Address is the address the staking transactions are sent to.
+var Address = common.HexToAddress("0x000000000000000000000000000000005374616b")
+
+// ShardID is the shard the staking transactions are applied on, the beacon chain.
+const ShardID uint32 = 0
+
+// Gas is the gas used by a staking transaction on top of its intrinsic gas.
+const Gas uint64 = 20000
+
+// BLSPublicKeyLength is the length of a serialized BLS public key.
+const BLSPublicKeyLength = 96
+
+// Errors of the staking transactions.
+var (
+	ErrInvalidMessage     = errors.New("invalid staking message")
+	ErrInvalidAmount      = errors.New("invalid staking amount")
+	ErrInvalidBLSKey      = errors.New("invalid BLS public key")
+	ErrBLSKeyInUse        = errors.New("BLS public key already used by a validator")
+	ErrValidatorExists    = errors.New("validator already exists")
+	ErrValidatorNotFound  = errors.New("validator not found")
+	ErrInsufficientStake  = errors.New("insufficient stake")
+	ErrUnexpectedValue    = errors.New("staking directive does not take a value")
+	ErrInsufficientFunds  = errors.New("insufficient funds for the stake")
+	ErrUnknownDirective   = errors.New("unknown staking directive")
+	ErrNoRewardsToCollect = errors.New("no rewards to collect")
+	ErrWrongShard         = errors.New("staking transaction not on the beacon chain")
+)
+
+// Directive is the kind of a staking message.
+type Directive byte
+
+// Staking directives.
+const (
+	// CreateValidator registers the sender as a validator signing with the BLS
+	// key of the message, staking the value of the transaction.
+	CreateValidator Directive = iota
+	// Delegate stakes the value of the transaction on the validator of the message.
+	Delegate
+	// Undelegate withdraws the amount of the message from the stake of the sender
+	// on the validator of the message. The amount is returned to the sender at
+	// the end of the unbonding period.
+	Undelegate
+	// CollectRewards pays the sender the rewards earned by its stake on the
+	// validator of the message.
+	CollectRewards
+)
+
+// Unbonding is a withdrawn stake, locked and slashable until it is released at
+// the end of the unbonding period.
+type Unbonding struct {
+	Validator common.Address `json:"validator"`
+	Delegator common.Address `json:"delegator"`
+	Amount    *big.Int       `json:"amount"`
+	// Block is the number of the block the stake was withdrawn in.
+	Block uint64 `json:"block"`
+}
+
+// Message is the data of a staking transaction.
+type Message struct {
+	Directive    Directive
+	Validator    common.Address
+	Amount       *big.Int
+	BLSPublicKey []byte
+}
+
+// Validator is the record of a validator.
+type Validator struct {
+	Address      common.Address
+	BLSPublicKey []byte
+	// Stake is the total stake of the validator, including the delegations.
+	Stake *big.Int
+}
+
+// Delegation is the stake of a delegator on a validator, the validator itself
+// included.
+type Delegation struct {
+	Delegator common.Address `json:"delegator"`
+	Amount    *big.Int       `json:"amount"`
+	Reward    *big.Int       `json:"reward"`
+}
+
+// Encode returns the RLP encoding of the message, used as transaction data.
+func (msg *Message) Encode() ([]byte, error) {
+	return rlp.EncodeToBytes(msg)
+}
+
+// DecodeMessage decodes the data of a staking transaction.
+func DecodeMessage(data []byte) (*Message, error) {
+	msg := new(Message)
+	if err := rlp.DecodeBytes(data, msg); err != nil {
+		return nil, ErrInvalidMessage
+	}
+	if msg.Amount == nil {
+		msg.Amount = new(big.Int)
+	}
+	return msg, nil
+}
+
+// NewTransaction returns an unsigned staking transaction carrying the message.
+func NewTransaction(nonce uint64, shardID uint32, value *big.Int, gasLimit uint64, gasPrice *big.Int, msg *Message) (*types.Transaction, error) {
+	data, err := msg.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTransaction(nonce, Address, shardID, value, gasLimit, gasPrice, data), nil
+}
+
+// GetValidator returns the record of a validator, or nil if the address is not
+// a validator.
+func GetValidator(state vm.StateDB, address common.Address) *Validator {
+	if !addressSet(validatorsKey).contains(state, address) {
+		return nil
+	}
+	key := validatorKey(address)
+	return &Validator{
+		Address:      address,
+		BLSPublicKey: getBytes(state, offset(key, 1), blsPublicKeySlots)[:BLSPublicKeyLength],
+		Stake:        getBig(state, key),
+	}
+}
+
+// GetValidatorByCoinbase returns the record of the validator whose BLS key
+// derives the given coinbase, or nil if there is none.
+func GetValidatorByCoinbase(state vm.StateDB, coinbase common.Address) *Validator {
+	address := state.GetState(Address, coinbaseKey(coinbase))
+	if address == (common.Hash{}) {
+		return nil
+	}
+	return GetValidator(state, common.BytesToAddress(address.Bytes()))
+}
+
+// Validators returns the records of all the validators.
+func Validators(state vm.StateDB) []*Validator {
+	addresses := addressSet(validatorsKey).list(state)
+	validators := make([]*Validator, 0, len(addresses))
+	for _, address := range addresses {
+		validators = append(validators, GetValidator(state, address))
+	}
+	return validators
+}
+
+// Stakes returns the total stake of every validator.
+func Stakes(state vm.StateDB) map[common.Address]*big.Int {
+	stakes := make(map[common.Address]*big.Int)
+	for _, validator := range Validators(state) {
+		stakes[validator.Address] = validator.Stake
+	}
+	return stakes
+}
+
+// GetDelegation returns the stake of a delegator on a validator, or nil if the
+// delegator has no stake on the validator.
+func GetDelegation(state vm.StateDB, validator, delegator common.Address) *Delegation {
+	if !addressSet(delegatorsKey(validator)).contains(state, delegator) {
+		return nil
+	}
+	key := delegationKey(validator, delegator)
+	return &Delegation{
+		Delegator: delegator,
+		Amount:    getBig(state, key),
+		Reward:    getBig(state, offset(key, 1)),
+	}
+}
+
+// Delegations returns the stakes on a validator.
+func Delegations(state vm.StateDB, validator common.Address) []*Delegation {
+	delegators := addressSet(delegatorsKey(validator)).list(state)
+	delegations := make([]*Delegation, 0, len(delegators))
+	for _, delegator := range delegators {
+		delegations = append(delegations, GetDelegation(state, validator, delegator))
+	}
+	return delegations
+}
+
+// AddReward shares a reward of a validator between its delegators, in proportion
+// of their stake. The rounding remainder goes to the validator itself. The reward
+// is held by the staking address until it is collected.
+func AddReward(state vm.StateDB, validator common.Address, reward *big.Int) {
+	v := GetValidator(state, validator)
+	if v == nil || v.Stake.Sign() == 0 {
+		return
+	}
+	delegations := Delegations(state, validator)
+	// The remainder goes to the first delegator if the validator withdrew its stake.
+	recipient := delegations[0].Delegator
+	remainder := new(big.Int).Set(reward)
+	for _, delegation := range delegations {
+		if delegation.Delegator == validator {
+			recipient = validator
+		}
+		share := new(big.Int).Mul(reward, delegation.Amount)
+		share.Div(share, v.Stake)
+		remainder.Sub(remainder, share)
+		setBig(state, offset(delegationKey(validator, delegation.Delegator), 1), share.Add(share, delegation.Reward))
+	}
+	if remainder.Sign() > 0 {
+		key := offset(delegationKey(validator, recipient), 1)
+		setBig(state, key, remainder.Add(remainder, getBig(state, key)))
+	}
+	state.AddBalance(Address, reward)
+}
+
+// Apply applies a staking message sent by from with the given value in the block
+// with the given number. The state is left unchanged if an error is returned.
+func Apply(state vm.StateDB, blockNumber uint64, from common.Address, value *big.Int, msg *Message) error {
+	if value.Sign() < 0 {
+		return ErrInvalidAmount
+	}
+	switch msg.Directive {
+	case CreateValidator, Delegate:
+		if value.Sign() == 0 {
+			return ErrInvalidAmount
+		}
+		if state.GetBalance(from).Cmp(value) < 0 {
+			return ErrInsufficientFunds
+		}
+	default:
+		if value.Sign() != 0 {
+			return ErrUnexpectedValue
+		}
+	}
+
+	switch msg.Directive {
+	case CreateValidator:
+		return createValidator(state, from, value, msg.BLSPublicKey)
+	case Delegate:
+		if GetValidator(state, msg.Validator) == nil {
+			return ErrValidatorNotFound
+		}
+		delegate(state, msg.Validator, from, value)
+		return nil
+	case Undelegate:
+		return undelegate(state, blockNumber, msg.Validator, from, msg.Amount)
+	case CollectRewards:
+		return collectRewards(state, msg.Validator, from)
+	}
+	return ErrUnknownDirective
+}
+
+func createValidator(state vm.StateDB, from common.Address, value *big.Int, blsPublicKey []byte) error {
+	if GetValidator(state, from) != nil {
+		return ErrValidatorExists
+	}
+	pubKey := new(bls.PublicKey)
+	if len(blsPublicKey) != BLSPublicKeyLength || pubKey.Deserialize(blsPublicKey) != nil {
+		return ErrInvalidBLSKey
+	}
+	coinbase := common.Address(pki.GetAddressFromPublicKey(pubKey))
+	if GetValidatorByCoinbase(state, coinbase) != nil {
+		return ErrBLSKeyInUse
+	}
+	// The staking account is given a nonce so that it is never removed as empty.
+	if state.GetNonce(Address) == 0 {
+		state.SetNonce(Address, 1)
+	}
+	addressSet(validatorsKey).add(state, from)
+	setBytes(state, offset(validatorKey(from), 1), blsPublicKey, blsPublicKeySlots)
+	state.SetState(Address, coinbaseKey(coinbase), from.Hash())
+	delegate(state, from, from, value)
+	return nil
+}
+
+func delegate(state vm.StateDB, validator, delegator common.Address, amount *big.Int) {
+	state.SubBalance(delegator, amount)
+	state.AddBalance(Address, amount)
+
+	addressSet(delegatorsKey(validator)).add(state, delegator)
+	key := delegationKey(validator, delegator)
+	setBig(state, key, new(big.Int).Add(getBig(state, key), amount))
+	setBig(state, validatorKey(validator), new(big.Int).Add(getBig(state, validatorKey(validator)), amount))
+}
+
+// undelegate moves an amount of stake to the unbonding queue. A delegation fully
+// withdrawn is removed, paying its rewards, and a validator is removed with its
+// last delegation.
+func undelegate(state vm.StateDB, blockNumber uint64, validator, delegator common.Address, amount *big.Int) error {
+	delegation := GetDelegation(state, validator, delegator)
+	if delegation == nil {
+		return ErrValidatorNotFound
+	}
+	if amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	if delegation.Amount.Cmp(amount) < 0 {
+		return ErrInsufficientStake
+	}
+	key := delegationKey(validator, delegator)
+	remaining := new(big.Int).Sub(delegation.Amount, amount)
+	setBig(state, key, remaining)
+	setBig(state, validatorKey(validator), new(big.Int).Sub(getBig(state, validatorKey(validator)), amount))
+	n := unbondingLen(state)
+	setUnbonding(state, n, &Unbonding{Validator: validator, Delegator: delegator, Amount: new(big.Int).Set(amount), Block: blockNumber})
+	setBig(state, unbondingKey, new(big.Int).SetUint64(n+1))
+
+	if remaining.Sign() == 0 {
+		if delegation.Reward.Sign() > 0 {
+			collectRewards(state, validator, delegator)
+		}
+		addressSet(delegatorsKey(validator)).remove(state, delegator)
+		if addressSet(delegatorsKey(validator)).len(state) == 0 {
+			removeValidator(state, validator)
+		}
+	}
+	return nil
+}
+
+func collectRewards(state vm.StateDB, validator, delegator common.Address) error {
+	delegation := GetDelegation(state, validator, delegator)
+	if delegation == nil {
+		return ErrValidatorNotFound
+	}
+	if delegation.Reward.Sign() == 0 {
+		return ErrNoRewardsToCollect
+	}
+	setBig(state, offset(delegationKey(validator, delegator), 1), new(big.Int))
+	state.SubBalance(Address, delegation.Reward)
+	state.AddBalance(delegator, delegation.Reward)
+	return nil
+}
+
+func removeValidator(state vm.StateDB, validator common.Address) {
+	v := GetValidator(state, validator)
+	pubKey := new(bls.PublicKey)
+	if pubKey.Deserialize(v.BLSPublicKey) == nil {
+		state.SetState(Address, coinbaseKey(common.Address(pki.GetAddressFromPublicKey(pubKey))), common.Hash{})
+	}
+	setBytes(state, offset(validatorKey(validator), 1), nil, blsPublicKeySlots)
+	setBig(state, validatorKey(validator), new(big.Int))
+	addressSet(validatorsKey).remove(state, validator)
+}
+
+// UnbondingQueue returns the withdrawn stakes not released yet, oldest first.
+func UnbondingQueue(state vm.StateDB) []*Unbonding {
+	head, n := unbondingHead(state), unbondingLen(state)
+	queue := make([]*Unbonding, 0, n-head)
+	for i := head; i < n; i++ {
+		queue = append(queue, getUnbonding(state, i))
+	}
+	return queue
+}
+
+// GetUnbondings returns the stakes withdrawn by a delegator not released yet.
+func GetUnbondings(state vm.StateDB, delegator common.Address) []*Unbonding {
+	var unbondings []*Unbonding
+	for _, unbonding := range UnbondingQueue(state) {
+		if unbonding.Delegator == delegator {
+			unbondings = append(unbondings, unbonding)
+		}
+	}
+	return unbondings
+}
+
+// ReleaseUnbonded returns to their delegators the withdrawn stakes whose release
+// block, given by releaseBlock from the block they were withdrawn in, is not
+// after blockNumber. The queue is ordered by release block, so only its matured
+// head is popped. It returns the released stakes.
+func ReleaseUnbonded(state vm.StateDB, blockNumber uint64, releaseBlock func(uint64) uint64) []*Unbonding {
+	var released []*Unbonding
+	head, n := unbondingHead(state), unbondingLen(state)
+	for ; head < n; head++ {
+		unbonding := getUnbonding(state, head)
+		if releaseBlock(unbonding.Block) > blockNumber {
+			break
+		}
+		state.SubBalance(Address, unbonding.Amount)
+		state.AddBalance(unbonding.Delegator, unbonding.Amount)
+		clearUnbonding(state, head)
+		released = append(released, unbonding)
+	}
+	if len(released) > 0 {
+		setBig(state, unbondingHeadKey, new(big.Int).SetUint64(head))
+	}
+	return released
+}
+
+// Slash burns the given percentage of the stake of a validator, including the
+// stakes withdrawn from it and not released yet, which are cut in place in the
+// unbonding queue. It returns the amount burnt.
+func Slash(state vm.StateDB, validator common.Address, percent uint64) *big.Int {
+	if percent > 100 {
+		percent = 100
+	}
+	cut := func(amount *big.Int) *big.Int {
+		return new(big.Int).Div(new(big.Int).Mul(amount, new(big.Int).SetUint64(percent)), big.NewInt(100))
+	}
+	slashed := new(big.Int)
+	if v := GetValidator(state, validator); v != nil {
+		for _, delegation := range Delegations(state, validator) {
+			amount := cut(delegation.Amount)
+			setBig(state, delegationKey(validator, delegation.Delegator), new(big.Int).Sub(delegation.Amount, amount))
+			slashed.Add(slashed, amount)
+		}
+		setBig(state, validatorKey(validator), new(big.Int).Sub(v.Stake, slashed))
+	}
+	head, n := unbondingHead(state), unbondingLen(state)
+	for i := head; i < n; i++ {
+		unbonding := getUnbonding(state, i)
+		if unbonding.Validator != validator {
+			continue
+		}
+		amount := cut(unbonding.Amount)
+		unbonding.Amount.Sub(unbonding.Amount, amount)
+		slashed.Add(slashed, amount)
+		setUnbonding(state, i, unbonding)
+	}
+	state.SubBalance(Address, slashed)
+	return slashed
+}
@@ -0,0 +1,330 @@
+package staking
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/crypto/pki"
+)
+
+var (
+	validator = common.HexToAddress("0x1000")
+	delegator = common.HexToAddress("0x2000")
+)
+
+func newTestState() *state.DB {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(validator, big.NewInt(1000))
+	statedb.AddBalance(delegator, big.NewInt(1000))
+	return statedb
+}
+
+func blsKey(i int) []byte {
+	return pki.GetBLSPrivateKeyFromInt(i).GetPublicKey().Serialize()
+}
+
+func mustCreateValidator(t *testing.T, statedb *state.DB, address common.Address, stake int64, key []byte) {
+	if err := Apply(statedb, 0, address, big.NewInt(stake), &Message{Directive: CreateValidator, BLSPublicKey: key}); err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+}
+
+func TestMessageEncoding(t *testing.T) {
+	msg := &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(10)}
+	data, err := msg.Encode()
+	if err != nil {
+		t.Fatalf("Failed to encode message: %v", err)
+	}
+	decoded, err := DecodeMessage(data)
+	if err != nil {
+		t.Fatalf("Failed to decode message: %v", err)
+	}
+	if decoded.Directive != Undelegate || decoded.Validator != validator || decoded.Amount.Int64() != 10 {
+		t.Errorf("Unexpected decoded message: %+v", decoded)
+	}
+	if _, err := DecodeMessage([]byte{0xd0, 0x0e}); err != ErrInvalidMessage {
+		t.Errorf("Expected %v, got %v", ErrInvalidMessage, err)
+	}
+}
+
+func TestCreateValidator(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+
+	v := GetValidator(statedb, validator)
+	if v == nil || v.Stake.Int64() != 100 || !bytes.Equal(v.BLSPublicKey, blsKey(1)) {
+		t.Fatalf("Unexpected validator: %+v", v)
+	}
+	if balance := statedb.GetBalance(validator).Int64(); balance != 900 {
+		t.Errorf("Expected balance 900, got %d", balance)
+	}
+	if balance := statedb.GetBalance(Address).Int64(); balance != 100 {
+		t.Errorf("Expected staked balance 100, got %d", balance)
+	}
+	pubKey := pki.GetBLSPrivateKeyFromInt(1).GetPublicKey()
+	if v := GetValidatorByCoinbase(statedb, common.Address(pki.GetAddressFromPublicKey(pubKey))); v == nil || v.Address != validator {
+		t.Errorf("Validator not found by coinbase: %+v", v)
+	}
+
+	tests := []struct {
+		from  common.Address
+		value int64
+		key   []byte
+		err   error
+	}{
+		{validator, 10, blsKey(2), ErrValidatorExists},
+		{delegator, 10, blsKey(1), ErrBLSKeyInUse},
+		{delegator, 10, []byte{1, 2, 3}, ErrInvalidBLSKey},
+		{delegator, 0, blsKey(2), ErrInvalidAmount},
+		{delegator, 2000, blsKey(2), ErrInsufficientFunds},
+	}
+	for i, test := range tests {
+		err := Apply(statedb, 0, test.from, big.NewInt(test.value), &Message{Directive: CreateValidator, BLSPublicKey: test.key})
+		if err != test.err {
+			t.Errorf("test %d: expected %v, got %v", i, test.err, err)
+		}
+	}
+	if len(Validators(statedb)) != 1 {
+		t.Errorf("Expected 1 validator, got %d", len(Validators(statedb)))
+	}
+}
+
+func TestDelegateAndUndelegate(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+
+	if err := Apply(statedb, 0, delegator, big.NewInt(50), &Message{Directive: Delegate, Validator: delegator}); err != ErrValidatorNotFound {
+		t.Errorf("Expected %v, got %v", ErrValidatorNotFound, err)
+	}
+	if err := Apply(statedb, 0, delegator, big.NewInt(50), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	if stake := Stakes(statedb)[validator]; stake == nil || stake.Int64() != 150 {
+		t.Errorf("Expected stake 150, got %v", stake)
+	}
+
+	undelegate := func(from common.Address, amount int64) error {
+		return Apply(statedb, 0, from, new(big.Int), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(amount)})
+	}
+	if err := undelegate(delegator, 60); err != ErrInsufficientStake {
+		t.Errorf("Expected %v, got %v", ErrInsufficientStake, err)
+	}
+	if err := Apply(statedb, 0, delegator, big.NewInt(1), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(1)}); err != ErrUnexpectedValue {
+		t.Errorf("Expected %v, got %v", ErrUnexpectedValue, err)
+	}
+	if err := undelegate(delegator, 20); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if d := GetDelegation(statedb, validator, delegator); d == nil || d.Amount.Int64() != 30 {
+		t.Errorf("Unexpected delegation: %+v", d)
+	}
+	// The withdrawn stake stays locked until it is released.
+	if balance := statedb.GetBalance(delegator).Int64(); balance != 950 {
+		t.Errorf("Expected balance 950, got %d", balance)
+	}
+	if unbondings := GetUnbondings(statedb, delegator); len(unbondings) != 1 || unbondings[0].Amount.Int64() != 20 {
+		t.Errorf("Unexpected unbondings: %v", unbondings)
+	}
+
+	// The validator is removed with its last delegation.
+	if err := undelegate(validator, 100); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if err := undelegate(delegator, 30); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if GetValidator(statedb, validator) != nil || len(Validators(statedb)) != 0 {
+		t.Errorf("Validator was not removed")
+	}
+	if balance := statedb.GetBalance(Address).Int64(); balance != 150 {
+		t.Errorf("Expected locked balance 150, got %d", balance)
+	}
+	// The BLS key can be used again.
+	mustCreateValidator(t, statedb, delegator, 10, blsKey(1))
+}
+
+func TestRewards(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+	if err := Apply(statedb, 0, delegator, big.NewInt(200), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	AddReward(statedb, validator, big.NewInt(31))
+
+	// The delegator earns 2/3 of the reward, the validator gets the remainder.
+	if d := GetDelegation(statedb, validator, delegator); d.Reward.Int64() != 20 {
+		t.Errorf("Expected delegator reward 20, got %v", d.Reward)
+	}
+	if d := GetDelegation(statedb, validator, validator); d.Reward.Int64() != 11 {
+		t.Errorf("Expected validator reward 11, got %v", d.Reward)
+	}
+
+	collect := &Message{Directive: CollectRewards, Validator: validator}
+	if err := Apply(statedb, 0, delegator, new(big.Int), collect); err != nil {
+		t.Fatalf("Failed to collect rewards: %v", err)
+	}
+	if balance := statedb.GetBalance(delegator).Int64(); balance != 820 {
+		t.Errorf("Expected balance 820, got %d", balance)
+	}
+	if err := Apply(statedb, 0, delegator, new(big.Int), collect); err != ErrNoRewardsToCollect {
+		t.Errorf("Expected %v, got %v", ErrNoRewardsToCollect, err)
+	}
+
+	// A fully withdrawn delegation is paid its rewards.
+	if err := Apply(statedb, 0, validator, new(big.Int), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(100)}); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if balance := statedb.GetBalance(validator).Int64(); balance != 911 {
+		t.Errorf("Expected balance 911, got %d", balance)
+	}
+	if balance := statedb.GetBalance(Address).Int64(); balance != 300 {
+		t.Errorf("Expected staked balance 300, got %d", balance)
+	}
+}
+
+func TestUnbonding(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+	if err := Apply(statedb, 0, delegator, big.NewInt(200), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	undelegate := func(from common.Address, amount int64, block uint64) {
+		if err := Apply(statedb, block, from, new(big.Int), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(amount)}); err != nil {
+			t.Fatalf("Failed to undelegate: %v", err)
+		}
+	}
+	undelegate(delegator, 50, 3)
+	undelegate(validator, 40, 12)
+	undelegate(delegator, 10, 15)
+	if queue := UnbondingQueue(statedb); len(queue) != 3 || queue[1].Delegator != validator || queue[1].Block != 12 {
+		t.Fatalf("Unexpected unbonding queue: %v", queue)
+	}
+
+	// Stakes are released 10 blocks after the start of the epoch they were withdrawn in.
+	releaseBlock := func(block uint64) uint64 {
+		return block/10*10 + 10
+	}
+	if released := ReleaseUnbonded(statedb, 9, releaseBlock); len(released) != 0 {
+		t.Errorf("Unexpected released stakes: %v", released)
+	}
+	released := ReleaseUnbonded(statedb, 10, releaseBlock)
+	if len(released) != 1 || released[0].Delegator != delegator || released[0].Amount.Int64() != 50 {
+		t.Fatalf("Unexpected released stakes: %v", released)
+	}
+	if balance := statedb.GetBalance(delegator).Int64(); balance != 850 {
+		t.Errorf("Expected balance 850, got %d", balance)
+	}
+	if entry := getUnbonding(statedb, 0); entry.Amount.Sign() != 0 || entry.Delegator != (common.Address{}) {
+		t.Errorf("Expected the released entry to be cleared, got %v", entry)
+	}
+	if queue := UnbondingQueue(statedb); len(queue) != 2 || queue[0].Block != 12 || queue[1].Block != 15 {
+		t.Errorf("Unexpected unbonding queue: %v", queue)
+	}
+	if released := ReleaseUnbonded(statedb, 19, releaseBlock); len(released) != 0 {
+		t.Errorf("Unexpected released stakes: %v", released)
+	}
+	ReleaseUnbonded(statedb, 20, releaseBlock)
+	if queue := UnbondingQueue(statedb); len(queue) != 0 {
+		t.Errorf("Unexpected unbonding queue: %v", queue)
+	}
+	if balance := statedb.GetBalance(Address).Int64(); balance != 200 {
+		t.Errorf("Expected staked balance 200, got %d", balance)
+	}
+
+	// Stakes withdrawn after a release are appended behind the released ones.
+	undelegate(delegator, 5, 21)
+	if queue := UnbondingQueue(statedb); len(queue) != 1 || queue[0].Block != 21 {
+		t.Errorf("Unexpected unbonding queue: %v", queue)
+	}
+}
+
+func TestSlash(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+	mustCreateValidator(t, statedb, delegator, 100, blsKey(2))
+	if err := Apply(statedb, 0, delegator, big.NewInt(200), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	undelegate := func(from, to common.Address, amount int64, block uint64) {
+		if err := Apply(statedb, block, from, new(big.Int), &Message{Directive: Undelegate, Validator: to, Amount: big.NewInt(amount)}); err != nil {
+			t.Fatalf("Failed to undelegate: %v", err)
+		}
+	}
+	undelegate(delegator, validator, 50, 3)
+	undelegate(validator, validator, 40, 12)
+	undelegate(delegator, delegator, 20, 13)
+	undelegate(delegator, validator, 10, 15)
+	releaseBlock := func(block uint64) uint64 {
+		return block/10*10 + 10
+	}
+	if released := ReleaseUnbonded(statedb, 10, releaseBlock); len(released) != 1 {
+		t.Fatalf("Unexpected released stakes: %v", released)
+	}
+
+	// Half of the stake of the validator is slashed, its withdrawn stakes still
+	// queued included. The released stake and the other validator are untouched.
+	if slashed := Slash(statedb, validator, 50); slashed.Int64() != 125 {
+		t.Errorf("Expected 125 slashed, got %v", slashed)
+	}
+	if stake := GetValidator(statedb, validator).Stake.Int64(); stake != 100 {
+		t.Errorf("Expected stake 100, got %d", stake)
+	}
+	if d := GetDelegation(statedb, validator, delegator); d.Amount.Int64() != 70 {
+		t.Errorf("Expected delegation 70, got %v", d.Amount)
+	}
+	if stake := GetValidator(statedb, delegator).Stake.Int64(); stake != 80 {
+		t.Errorf("Expected stake 80 of the other validator, got %d", stake)
+	}
+	queue := UnbondingQueue(statedb)
+	if len(queue) != 3 || queue[0].Amount.Int64() != 20 || queue[1].Amount.Int64() != 20 || queue[2].Amount.Int64() != 5 {
+		t.Errorf("Unexpected unbonding queue: %v", queue)
+	}
+	if entry := getUnbonding(statedb, 0); entry.Amount.Sign() != 0 {
+		t.Errorf("Expected the released entry to stay cleared, got %v", entry)
+	}
+	if balance := statedb.GetBalance(Address).Int64(); balance != 225 {
+		t.Errorf("Expected staked balance 225, got %d", balance)
+	}
+
+	ReleaseUnbonded(statedb, 20, releaseBlock)
+	if balance := statedb.GetBalance(Address).Int64(); balance != 180 {
+		t.Errorf("Expected staked balance 180, got %d", balance)
+	}
+}
+
+func TestCompareStakes(t *testing.T) {
+	statedb := newTestState()
+	mustCreateValidator(t, statedb, validator, 100, blsKey(1))
+	expected := statedb.Copy()
+	AddReward(statedb, validator, big.NewInt(10))
+	if err := CompareStakes(Dump(statedb), Dump(expected)); err != nil {
+		t.Errorf("Rewards should not be compared, got %v", err)
+	}
+
+	if err := Apply(statedb, 0, delegator, big.NewInt(50), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	if err := CompareStakes(Dump(statedb), Dump(expected)); err == nil {
+		t.Errorf("Expected a stake mismatch")
+	}
+	if err := Apply(expected, 0, delegator, big.NewInt(50), &Message{Directive: Delegate, Validator: validator}); err != nil {
+		t.Fatalf("Failed to delegate: %v", err)
+	}
+	if err := CompareStakes(Dump(statedb), Dump(expected)); err != nil {
+		t.Errorf("Unexpected mismatch: %v", err)
+	}
+
+	if err := Apply(statedb, 5, delegator, new(big.Int), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(10)}); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if err := Apply(expected, 6, delegator, new(big.Int), &Message{Directive: Undelegate, Validator: validator, Amount: big.NewInt(10)}); err != nil {
+		t.Fatalf("Failed to undelegate: %v", err)
+	}
+	if err := CompareStakes(Dump(statedb), Dump(expected)); err == nil {
+		t.Errorf("Expected an unbonding queue mismatch")
+	}
+}
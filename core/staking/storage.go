@@ -0,0 +1,176 @@
+package staking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// Layout of the storage of the staking address:
+//
+//	keccak("validators")                              set of the validator addresses
+//	keccak("validator", validator) + 0                total stake of the validator
+//	keccak("validator", validator) + 1..3             BLS public key of the validator
+//	keccak("delegators", validator)                   set of the delegators of the validator
+//	keccak("delegation", validator, delegator) + 0    amount delegated
+//	keccak("delegation", validator, delegator) + 1    reward not collected yet
+//	keccak("coinbase", coinbase)                      validator signing with the coinbase key
+//	keccak("unbonding")                               number of stakes ever withdrawn
+//	keccak("unbonding-head")                          index of the first withdrawn stake not released yet
+//	keccak(keccak("unbonding")) + 4i + 0..3           validator, delegator, amount and block
+//	                                                  of the i-th withdrawn stake
+//
+// The withdrawn stakes are appended in block order, so the unbonding queue is
+// ordered by release block and the matured stakes are always at its head.
+//
+// A set of addresses stores its length at its key, its i-th element at
+// keccak(key) + i and the index plus one of an element at keccak(key, element).
+var (
+	validatorsKey    = crypto.Keccak256Hash([]byte("validators"))
+	unbondingKey     = crypto.Keccak256Hash([]byte("unbonding"))
+	unbondingHeadKey = crypto.Keccak256Hash([]byte("unbonding-head"))
+)
+
+const blsPublicKeySlots = (BLSPublicKeyLength + common.HashLength - 1) / common.HashLength
+
+func validatorKey(validator common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("validator"), validator.Bytes())
+}
+
+func delegatorsKey(validator common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("delegators"), validator.Bytes())
+}
+
+func delegationKey(validator, delegator common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("delegation"), validator.Bytes(), delegator.Bytes())
+}
+
+func coinbaseKey(coinbase common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("coinbase"), coinbase.Bytes())
+}
+
+// offset returns the slot at the given offset from a key.
+func offset(key common.Hash, i uint64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(key.Big(), new(big.Int).SetUint64(i)))
+}
+
+func getBig(state vm.StateDB, key common.Hash) *big.Int {
+	return state.GetState(Address, key).Big()
+}
+
+func setBig(state vm.StateDB, key common.Hash, value *big.Int) {
+	state.SetState(Address, key, common.BigToHash(value))
+}
+
+func getBytes(state vm.StateDB, key common.Hash, slots int) []byte {
+	data := make([]byte, 0, slots*common.HashLength)
+	for i := 0; i < slots; i++ {
+		data = append(data, state.GetState(Address, offset(key, uint64(i))).Bytes()...)
+	}
+	return data
+}
+
+func setBytes(state vm.StateDB, key common.Hash, data []byte, slots int) {
+	padded := common.RightPadBytes(data, slots*common.HashLength)
+	for i := 0; i < slots; i++ {
+		state.SetState(Address, offset(key, uint64(i)), common.BytesToHash(padded[i*common.HashLength:(i+1)*common.HashLength]))
+	}
+}
+
+// addressSet is a set of addresses kept in the storage of the staking address.
+type addressSet common.Hash
+
+func (set addressSet) len(state vm.StateDB) uint64 {
+	return getBig(state, common.Hash(set)).Uint64()
+}
+
+func (set addressSet) elementKey(i uint64) common.Hash {
+	return offset(crypto.Keccak256Hash(set[:]), i)
+}
+
+func (set addressSet) indexKey(address common.Address) common.Hash {
+	return crypto.Keccak256Hash(set[:], address.Bytes())
+}
+
+func (set addressSet) contains(state vm.StateDB, address common.Address) bool {
+	return state.GetState(Address, set.indexKey(address)) != (common.Hash{})
+}
+
+func (set addressSet) list(state vm.StateDB) []common.Address {
+	n := set.len(state)
+	addresses := make([]common.Address, 0, n)
+	for i := uint64(0); i < n; i++ {
+		addresses = append(addresses, common.BytesToAddress(state.GetState(Address, set.elementKey(i)).Bytes()))
+	}
+	return addresses
+}
+
+func (set addressSet) add(state vm.StateDB, address common.Address) {
+	if set.contains(state, address) {
+		return
+	}
+	n := set.len(state)
+	state.SetState(Address, set.elementKey(n), address.Hash())
+	setBig(state, set.indexKey(address), new(big.Int).SetUint64(n+1))
+	setBig(state, common.Hash(set), new(big.Int).SetUint64(n+1))
+}
+
+// remove removes an address from the set, moving the last element in its place.
+func (set addressSet) remove(state vm.StateDB, address common.Address) {
+	index := getBig(state, set.indexKey(address)).Uint64()
+	if index == 0 {
+		return
+	}
+	n := set.len(state)
+	last := common.BytesToAddress(state.GetState(Address, set.elementKey(n-1)).Bytes())
+	if last != address {
+		state.SetState(Address, set.elementKey(index-1), last.Hash())
+		setBig(state, set.indexKey(last), new(big.Int).SetUint64(index))
+	}
+	state.SetState(Address, set.elementKey(n-1), common.Hash{})
+	state.SetState(Address, set.indexKey(address), common.Hash{})
+	setBig(state, common.Hash(set), new(big.Int).SetUint64(n-1))
+}
+
+// unbondingFields is the number of slots of an entry of the unbonding queue.
+const unbondingFields = 4
+
+func unbondingLen(state vm.StateDB) uint64 {
+	return getBig(state, unbondingKey).Uint64()
+}
+
+func unbondingEntryKey(i uint64) common.Hash {
+	return offset(crypto.Keccak256Hash(unbondingKey[:]), i*unbondingFields)
+}
+
+func getUnbonding(state vm.StateDB, i uint64) *Unbonding {
+	key := unbondingEntryKey(i)
+	return &Unbonding{
+		Validator: common.BytesToAddress(state.GetState(Address, key).Bytes()),
+		Delegator: common.BytesToAddress(state.GetState(Address, offset(key, 1)).Bytes()),
+		Amount:    getBig(state, offset(key, 2)),
+		Block:     getBig(state, offset(key, 3)).Uint64(),
+	}
+}
+
+func setUnbonding(state vm.StateDB, i uint64, unbonding *Unbonding) {
+	key := unbondingEntryKey(i)
+	state.SetState(Address, key, unbonding.Validator.Hash())
+	state.SetState(Address, offset(key, 1), unbonding.Delegator.Hash())
+	setBig(state, offset(key, 2), unbonding.Amount)
+	setBig(state, offset(key, 3), new(big.Int).SetUint64(unbonding.Block))
+}
+
+func unbondingHead(state vm.StateDB) uint64 {
+	return getBig(state, unbondingHeadKey).Uint64()
+}
+
+// clearUnbonding clears the slots of the i-th withdrawn stake.
+func clearUnbonding(state vm.StateDB, i uint64) {
+	key := unbondingEntryKey(i)
+	for j := uint64(0); j < unbondingFields; j++ {
+		state.SetState(Address, offset(key, j), common.Hash{})
+	}
+}
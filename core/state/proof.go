@@ -0,0 +1,139 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Errors returned by VerifyAccountProof.
+var (
+	ErrAccountMismatch = errors.New("account doesn't match the proof")
+	ErrStorageMismatch = errors.New("storage value doesn't match the proof")
+)
+
+// AccountProof is the Merkle proof of an account and some of its storage slots
+// against a state root, in the style of eth_getProof.
+type AccountProof struct {
+	Address      common.Address
+	Balance      *big.Int
+	Nonce        uint64
+	CodeHash     common.Hash
+	StorageHash  common.Hash
+	AccountProof [][]byte
+	StorageProof []StorageProof
+}
+
+// StorageProof is the Merkle proof of one storage slot against the storage root
+// of an account.
+type StorageProof struct {
+	Key   common.Hash
+	Value common.Hash
+	Proof [][]byte
+}
+
+// GetAccountProof returns the proof of the given account and storage slots. The
+// proofs are taken from the committed tries, so the state must not have
+// uncommitted changes.
+func (stateDB *DB) GetAccountProof(addr common.Address, storageKeys []common.Hash) (*AccountProof, error) {
+	accountProof, err := stateDB.GetProof(addr)
+	if err != nil {
+		return nil, err
+	}
+	result := &AccountProof{
+		Address:      addr,
+		Balance:      new(big.Int),
+		AccountProof: accountProof,
+	}
+	stateObject := stateDB.getStateObject(addr)
+	if stateObject != nil {
+		result.Balance.Set(stateObject.Balance())
+		result.Nonce = stateObject.Nonce()
+		result.CodeHash = common.BytesToHash(stateObject.CodeHash())
+		result.StorageHash = stateObject.data.Root
+	}
+	for _, key := range storageKeys {
+		storageProof := StorageProof{Key: key}
+		if stateObject != nil {
+			storageProof.Value = stateDB.GetState(addr, key)
+			if storageProof.Proof, err = stateDB.GetStorageProof(addr, key); err != nil {
+				return nil, err
+			}
+		}
+		result.StorageProof = append(result.StorageProof, storageProof)
+	}
+	return result, nil
+}
+
+// proofDB returns a database holding the given proof nodes keyed by their hash.
+func proofDB(proof [][]byte) *ethdb.MemDatabase {
+	db := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
+
+// VerifyAccountProof checks the account and storage values in the given proof
+// against the state root of a block. A missing account must be proven absent
+// and have all its values empty.
+func VerifyAccountProof(root common.Hash, proof *AccountProof) error {
+	value, _, err := trie.VerifyProof(root, crypto.Keccak256(proof.Address.Bytes()), proofDB(proof.AccountProof))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		if proof.Nonce != 0 || (proof.Balance != nil && proof.Balance.Sign() != 0) || proof.StorageHash != (common.Hash{}) {
+			return ErrAccountMismatch
+		}
+		for _, storageProof := range proof.StorageProof {
+			if storageProof.Value != (common.Hash{}) {
+				return ErrStorageMismatch
+			}
+		}
+		return nil
+	}
+	var account Account
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return err
+	}
+	codeHash := common.BytesToHash(account.CodeHash)
+	if len(account.CodeHash) == 0 {
+		codeHash = common.BytesToHash(emptyCodeHash)
+	}
+	if account.Nonce != proof.Nonce || proof.Balance == nil || account.Balance.Cmp(proof.Balance) != 0 ||
+		account.Root != proof.StorageHash || codeHash != proof.CodeHash {
+		return ErrAccountMismatch
+	}
+	for _, storageProof := range proof.StorageProof {
+		if err := verifyStorageProof(account.Root, storageProof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyStorageProof checks one storage value against the storage root of an account.
+func verifyStorageProof(root common.Hash, proof StorageProof) error {
+	value, _, err := trie.VerifyProof(root, crypto.Keccak256(proof.Key.Bytes()), proofDB(proof.Proof))
+	if err != nil {
+		return err
+	}
+	var stored common.Hash
+	if value != nil {
+		_, content, _, err := rlp.Split(value)
+		if err != nil {
+			return err
+		}
+		stored = common.BytesToHash(content)
+	}
+	if stored != proof.Value {
+		return ErrStorageMismatch
+	}
+	return nil
+}
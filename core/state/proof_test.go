@@ -0,0 +1,56 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestAccountProof(t *testing.T) {
+	db := NewDatabase(ethdb.NewMemDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	addr := common.BytesToAddress([]byte{1})
+	key := common.BytesToHash([]byte{2})
+	for i := byte(1); i < 20; i++ {
+		statedb.AddBalance(common.BytesToAddress([]byte{i}), big.NewInt(int64(i)*100))
+	}
+	statedb.SetNonce(addr, 5)
+	statedb.SetState(addr, key, common.BytesToHash([]byte{3}))
+	root, _ := statedb.Commit(false)
+	statedb, _ = New(root, db)
+
+	proof, err := statedb.GetAccountProof(addr, []common.Hash{key, common.BytesToHash([]byte{4})})
+	if err != nil {
+		t.Fatalf("failed to get proof: %v", err)
+	}
+	if proof.Balance.Int64() != 100 || proof.Nonce != 5 {
+		t.Fatalf("unexpected account in proof: balance %v nonce %d", proof.Balance, proof.Nonce)
+	}
+	if err := VerifyAccountProof(root, proof); err != nil {
+		t.Errorf("valid proof should verify: %v", err)
+	}
+
+	proof.Balance = big.NewInt(1000)
+	if err := VerifyAccountProof(root, proof); err != ErrAccountMismatch {
+		t.Errorf("expected %v, got %v", ErrAccountMismatch, err)
+	}
+	proof.Balance = big.NewInt(100)
+	proof.StorageProof[0].Value = common.BytesToHash([]byte{9})
+	if err := VerifyAccountProof(root, proof); err != ErrStorageMismatch {
+		t.Errorf("expected %v, got %v", ErrStorageMismatch, err)
+	}
+
+	missing, err := statedb.GetAccountProof(common.BytesToAddress([]byte{99}), nil)
+	if err != nil {
+		t.Fatalf("failed to get proof of missing account: %v", err)
+	}
+	if err := VerifyAccountProof(root, missing); err != nil {
+		t.Errorf("proof of absence should verify: %v", err)
+	}
+	missing.Nonce = 1
+	if err := VerifyAccountProof(root, missing); err != ErrAccountMismatch {
+		t.Errorf("expected %v, got %v", ErrAccountMismatch, err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeTestState creates a state with accounts holding a balance, a code and a
+// storage, and commits it to a new database.
+func makeTestState(t *testing.T) (Database, common.Hash) {
+	db := NewDatabase(ethdb.NewMemDatabase())
+	statedb, _ := New(common.Hash{}, db)
+	for i := byte(0); i < 32; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		statedb.SetBalance(addr, big.NewInt(int64(11*i)))
+		statedb.SetNonce(addr, uint64(42*i))
+		if i%3 == 0 {
+			statedb.SetCode(addr, []byte{i, i, i, i, i})
+		}
+		if i%5 == 0 {
+			for j := byte(0); j < 5; j++ {
+				statedb.SetState(addr, common.BytesToHash([]byte{i, j}), common.BytesToHash([]byte{i, j, i}))
+			}
+		}
+	}
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit state trie: %v", err)
+	}
+	return db, root
+}
+
+func TestStateSync(t *testing.T) {
+	srcDb, root := makeTestState(t)
+	src, _ := New(root, srcDb)
+
+	dstDb := ethdb.NewMemDatabase()
+	sched := NewStateSync(root, dstDb)
+	for queue := sched.Missing(0); len(queue) > 0; queue = sched.Missing(0) {
+		results := make([]trie.SyncResult, len(queue))
+		for i, hash := range queue {
+			data, err := srcDb.TrieDB().Node(hash)
+			if err != nil {
+				t.Fatalf("failed to retrieve node data for %x: %v", hash, err)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: data}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			t.Fatalf("failed to process result #%d: %v", index, err)
+		}
+		if index, err := sched.Commit(dstDb); err != nil {
+			t.Fatalf("failed to commit data #%d: %v", index, err)
+		}
+	}
+
+	dst, err := New(root, NewDatabase(dstDb))
+	if err != nil {
+		t.Fatalf("failed to open synced state: %v", err)
+	}
+	for i := byte(0); i < 32; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		if want, got := src.GetBalance(addr), dst.GetBalance(addr); want.Cmp(got) != 0 {
+			t.Errorf("account %d: balance mismatch: have %v, want %v", i, got, want)
+		}
+		if want, got := src.GetNonce(addr), dst.GetNonce(addr); want != got {
+			t.Errorf("account %d: nonce mismatch: have %v, want %v", i, got, want)
+		}
+		if want, got := src.GetCode(addr), dst.GetCode(addr); !bytes.Equal(want, got) {
+			t.Errorf("account %d: code mismatch: have %x, want %x", i, got, want)
+		}
+		for j := byte(0); j < 5; j++ {
+			key := common.BytesToHash([]byte{i, j})
+			if want, got := src.GetState(addr, key), dst.GetState(addr, key); want != got {
+				t.Errorf("account %d: storage %d mismatch: have %x, want %x", i, j, got, want)
+			}
+		}
+	}
+}
@@ -49,12 +49,15 @@ func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consen
 // the transaction messages using the statedb and applying any rewards to both
 // the processor (coinbase) and any included uncles.
 //
-// Process returns the receipts and logs accumulated during the process and
-// returns the amount of gas that was used in the process. If any of the
-// transactions failed to execute due to insufficient gas it will return an error.
-func (p *StateProcessor) Process(block *types.Block, statedb *state.DB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+// Process returns the receipts, outgoing cross-shard receipts and logs
+// accumulated during the process and returns the amount of gas that was used in
+// the process. If any of the transactions failed to execute due to insufficient
+// gas it will return an error. Incoming cross-shard receipts of the block are
+// credited after the transactions.
+func (p *StateProcessor) Process(block *types.Block, statedb *state.DB, cfg vm.Config) (types.Receipts, types.CXReceipts, []*types.Log, uint64, error) {
 	var (
 		receipts types.Receipts
+		outcxs   types.CXReceipts
 		usedGas  = new(uint64)
 		header   = block.Header()
 		allLogs  []*types.Log
@@ -67,27 +70,46 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.DB, cfg vm.C
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
-		receipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
+		receipt, cxReceipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
 		if err != nil {
-			return nil, nil, 0, err
+			return nil, nil, nil, 0, err
 		}
 		receipts = append(receipts, receipt)
+		if cxReceipt != nil {
+			outcxs = append(outcxs, cxReceipt)
+		}
 		allLogs = append(allLogs, receipt.Logs...)
 	}
+	// Credit the incoming cross-shard transfers
+	for _, cxp := range block.IncomingReceipts() {
+		ApplyIncomingReceipt(statedb, cxp)
+	}
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
-	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), receipts)
+	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), receipts, outcxs, block.IncomingReceipts())
 
-	return receipts, allLogs, *usedGas, nil
+	return receipts, outcxs, allLogs, *usedGas, nil
+}
+
+// ApplyIncomingReceipt credits the recipients of the cross-shard receipts in the
+// given proof. The proof must have been validated before.
+func ApplyIncomingReceipt(statedb *state.DB, cxp *types.CXReceiptsProof) {
+	for _, cx := range cxp.Receipts {
+		if !statedb.Exist(cx.To) {
+			statedb.CreateAccount(cx.To)
+		}
+		statedb.AddBalance(cx.To, cx.Amount)
+	}
 }
 
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
-// for the transaction, gas used and an error if the transaction failed,
-// indicating the block was invalid.
-func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.DB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
+// for the transaction, the cross-shard receipt if the transaction is a
+// successful cross-shard transfer, gas used and an error if the transaction
+// failed, indicating the block was invalid.
+func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.DB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, *types.CXReceipt, uint64, error) {
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, 0, err
 	}
 	// Create a new context to be used in the EVM environment
 	context := NewEVMContext(msg, header, bc, author)
@@ -97,7 +119,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	// Apply the transaction to the current state (included in the env)
 	_, gas, failed, err := ApplyMessage(vmenv, msg, gp)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, 0, err
 	}
 	// Update the state with pending changes
 	var root []byte
@@ -118,8 +140,22 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
 	}
 	// Set the receipt logs and create a bloom for filtering
-	//receipt.Logs = statedb.GetLogs(tx.Hash())
+	if bc.HarmonyConfig().IsReceiptLogs(header.Number) {
+		receipt.Logs = statedb.GetLogs(tx.Hash())
+	}
 	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
 
-	return receipt, gas, err
+	var cxReceipt *types.CXReceipt
+	if tx.IsCrossShard() && !failed {
+		cxReceipt = &types.CXReceipt{
+			TxHash:    tx.Hash(),
+			From:      msg.From(),
+			To:        *msg.To(),
+			ShardID:   tx.ShardID(),
+			ToShardID: tx.ToShardID(),
+			Amount:    msg.Value(),
+		}
+	}
+
+	return receipt, cxReceipt, gas, err
 }
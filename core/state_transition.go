@@ -24,11 +24,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/vm"
 )
 
 var (
-	errInsufficientBalanceForGas = errors.New("insufficient balance to pay for gas")
+	errInsufficientBalanceForGas  = errors.New("insufficient balance to pay for gas")
+	errCrossShardContractCreation = errors.New("contract creation can't be cross-shard")
 )
 
 /*
@@ -73,6 +75,9 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+
+	ShardID() uint32
+	ToShardID() uint32
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
@@ -205,8 +210,27 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		// error.
 		vmerr error
 	)
+	if !contractCreation && st.to() == staking.Address && (msg.ShardID() != staking.ShardID || msg.ToShardID() != staking.ShardID) {
+		return nil, 0, false, staking.ErrWrongShard
+	}
 	if contractCreation {
+		if msg.ToShardID() != msg.ShardID() {
+			return nil, 0, false, errCrossShardContractCreation
+		}
 		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value)
+	} else if msg.ToShardID() != msg.ShardID() {
+		// Cross-shard transfer: the value leaves this shard here and is credited
+		// to the recipient on the destination shard with a cross-shard receipt.
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		if !evm.Context.CanTransfer(st.state, msg.From(), st.value) {
+			vmerr = vm.ErrInsufficientBalance
+		} else {
+			st.state.SubBalance(msg.From(), st.value)
+		}
+	} else if st.to() == staking.Address {
+		// Staking transactions are applied natively, without running the EVM.
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		vmerr = st.applyStaking()
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
@@ -227,6 +251,22 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// applyStaking applies the staking message carried by the data of the message.
+// A staking message which can't be applied fails the transaction like a vm error.
+func (st *StateTransition) applyStaking() error {
+	if err := st.useGas(staking.Gas); err != nil {
+		return err
+	}
+	if !st.evm.Context.CanTransfer(st.state, st.msg.From(), st.value) {
+		return vm.ErrInsufficientBalance
+	}
+	msg, err := staking.DecodeMessage(st.data)
+	if err != nil {
+		return err
+	}
+	return staking.Apply(st.state, st.evm.BlockNumber.Uint64(), st.msg.From(), st.value, msg)
+}
+
 func (st *StateTransition) refundGas() {
 	// Apply refund counter, capped to half of the used gas.
 	refund := st.gasUsed() / 2
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/staking"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/crypto/pki"
+)
+
+func TestStakingTransition(t *testing.T) {
+	var (
+		sender = common.HexToAddress("0x1000")
+		header = &types.Header{Number: big.NewInt(1), GasLimit: 10000000}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(sender, big.NewInt(1000))
+
+	data, err := (&staking.Message{
+		Directive:    staking.CreateValidator,
+		BLSPublicKey: pki.GetBLSPrivateKeyFromInt(1).GetPublicKey().Serialize(),
+	}).Encode()
+	if err != nil {
+		t.Fatalf("Failed to encode staking message: %v", err)
+	}
+	msg := types.NewMessage(sender, &staking.Address, 0, big.NewInt(100), 100000, new(big.Int), data, true)
+	_, gas, failed, err := DoCall(nil, params.TestChainConfig, statedb, header, msg, 0)
+	if err != nil || failed {
+		t.Fatalf("Staking transaction failed: %v", err)
+	}
+	intrinsic, _ := IntrinsicGas(data, false, true)
+	if gas != intrinsic+staking.Gas {
+		t.Errorf("Expected gas %d, got %d", intrinsic+staking.Gas, gas)
+	}
+	if stake := staking.Stakes(statedb)[sender]; stake == nil || stake.Int64() != 100 {
+		t.Errorf("Expected stake 100, got %v", stake)
+	}
+	if nonce := statedb.GetNonce(sender); nonce != 1 {
+		t.Errorf("Expected nonce 1, got %d", nonce)
+	}
+
+	// A staking message which can't be applied fails the transaction and keeps the value.
+	msg = types.NewMessage(sender, &staking.Address, 1, big.NewInt(100), 100000, new(big.Int), []byte{0x01}, true)
+	if _, _, failed, err := DoCall(nil, params.TestChainConfig, statedb, header, msg, 0); err != nil || !failed {
+		t.Fatalf("Invalid staking transaction should fail, got failed %v, error %v", failed, err)
+	}
+	if balance := statedb.GetBalance(sender).Int64(); balance != 900 {
+		t.Errorf("Expected balance 900, got %d", balance)
+	}
+}
+
+func TestContractTransferToStaking(t *testing.T) {
+	var (
+		sender = common.HexToAddress("0x1000")
+		header = &types.Header{Number: big.NewInt(1), GasLimit: 10000000}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(sender, big.NewInt(1000))
+
+	// callCode calls the recipient with a value of 1 and stores the call result at slot 0.
+	callCode := func(recipient common.Address) []byte {
+		code := common.Hex2Bytes("60006000600060006001")
+		code = append(code, 0x73)
+		code = append(code, recipient.Bytes()...)
+		return append(code, common.Hex2Bytes("61fffff160005500")...)
+	}
+	for i, test := range []struct {
+		recipient common.Address
+		success   bool
+	}{
+		{common.HexToAddress("0x2000"), true},
+		{staking.Address, false},
+	} {
+		contract := common.BigToAddress(big.NewInt(int64(0x3000 + i)))
+		statedb.SetCode(contract, callCode(test.recipient))
+		statedb.AddBalance(contract, big.NewInt(10))
+
+		msg := types.NewMessage(sender, &contract, uint64(i), new(big.Int), 100000, new(big.Int), nil, true)
+		if _, _, failed, err := DoCall(nil, params.TestChainConfig, statedb, header, msg, 0); err != nil || failed {
+			t.Fatalf("Call %d failed: %v", i, err)
+		}
+		if success := statedb.GetState(contract, common.Hash{}) != (common.Hash{}); success != test.success {
+			t.Errorf("Transfer %d to %x: expected success %v, got %v", i, test.recipient, test.success, success)
+		}
+	}
+	if balance := statedb.GetBalance(staking.Address); balance.Sign() != 0 {
+		t.Errorf("Expected no balance on the staking address, got %v", balance)
+	}
+}
+
+func TestEstimateGasCap(t *testing.T) {
+	var (
+		sender    = common.HexToAddress("0x1000")
+		recipient = common.HexToAddress("0x2000")
+		header    = &types.Header{Number: big.NewInt(1), GasLimit: 10000000}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(sender, big.NewInt(100000))
+
+	// The sender can only pay for the gas up to the cap, so a huge gas in the
+	// message has to be clamped for the estimation to succeed.
+	msg := types.NewMessage(sender, &recipient, 0, new(big.Int), math.MaxUint64, big.NewInt(1), nil, false)
+	gas, err := EstimateGas(nil, params.TestChainConfig, statedb, header, msg, 50000)
+	if err != nil || gas != params.TxGas {
+		t.Errorf("Expected gas %d, got %d (%v)", params.TxGas, gas, err)
+	}
+	if _, err := EstimateGas(nil, params.TestChainConfig, statedb, header, msg, 0); err != ErrGasEstimation {
+		t.Errorf("Expected %v without cap below the block gas limit, got %v", ErrGasEstimation, err)
+	}
+	if balance := statedb.GetBalance(sender).Int64(); balance != 100000 {
+		t.Errorf("Estimation changed the state, balance %d", balance)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
 )
@@ -52,8 +53,9 @@ type testBlockChain struct {
 
 func (bc *testBlockChain) CurrentBlock() *types.Block {
 	return types.NewBlock(&types.Header{
+		Number:   new(big.Int),
 		GasLimit: bc.gasLimit,
-	}, nil, nil)
+	}, nil, nil, nil, nil)
 }
 
 func (bc *testBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
@@ -64,6 +66,10 @@ func (bc *testBlockChain) StateAt(common.Hash) (*state.DB, error) {
 	return bc.statedb, nil
 }
 
+func (bc *testBlockChain) ShardID() uint32 {
+	return 0
+}
+
 func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
 	return bc.chainHeadFeed.Subscribe(ch)
 }
@@ -259,6 +265,38 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+func TestInvalidShardTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, 1, big.NewInt(100), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	from, _ := deriveSender(tx)
+	pool.currentState.AddBalance(from, big.NewInt(0xffffffffffffff))
+	if err := pool.AddRemote(tx); err != ErrInvalidShard {
+		t.Error("expected", ErrInvalidShard, "got", err)
+	}
+	if err := pool.AddLocal(tx); err != ErrInvalidShard {
+		t.Error("expected", ErrInvalidShard, "got", err)
+	}
+
+	// Cross-shard transactions are accepted by their source shard.
+	tx, _ = types.SignTx(types.NewCrossShardTransaction(0, common.Address{}, 0, 1, big.NewInt(100), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err := pool.AddRemote(tx); err != nil {
+		t.Error("expected", nil, "got", err)
+	}
+	if err := pool.AddRemote(tx); err != ErrKnownTransaction {
+		t.Error("expected", ErrKnownTransaction, "got", err)
+	}
+
+	// Staking transactions are only applied on the beacon chain.
+	tx, _ = types.SignTx(types.NewCrossShardTransaction(1, staking.Address, 0, 1, big.NewInt(100), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	if err := pool.AddRemote(tx); err != staking.ErrWrongShard {
+		t.Error("expected", staking.ErrWrongShard, "got", err)
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	t.Parallel()
 
@@ -356,6 +394,33 @@ func TestTransactionChainFork(t *testing.T) {
 	}
 }
 
+// Tests that the pending transactions of a block proposer are the ones of the new
+// head, even if the pool has not handled the head event yet.
+func TestTransactionPendingAtNewHead(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(addr, big.NewInt(1000000))
+	if err := pool.AddRemote(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// The transaction is included by a new head the event loop doesn't know of.
+	pool.chain.(*testBlockChain).statedb.SetNonce(addr, 1)
+	if pending, _ := pool.Pending(); len(pending) != 1 {
+		t.Fatalf("pending transactions mismatch before the reset: have %d, want %d", len(pending), 1)
+	}
+	pending, _ := pool.PendingAt(&types.Header{Number: big.NewInt(1), GasLimit: 1000000})
+	if len(pending) != 0 {
+		t.Errorf("pending transactions mismatch at the new head: have %d, want %d", len(pending), 0)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 func TestTransactionDoubleNonce(t *testing.T) {
 	t.Parallel()
 
@@ -30,17 +30,17 @@ type Validator interface {
 	// ValidateBody validates the given block's content.
 	ValidateBody(block *types.Block) error
 
-	// ValidateState validates the given statedb and optionally the receipts and
-	// gas used.
-	ValidateState(block, parent *types.Block, state *state.DB, receipts types.Receipts, usedGas uint64) error
+	// ValidateState validates the given statedb and optionally the receipts,
+	// outgoing cross-shard receipts and gas used.
+	ValidateState(block, parent *types.Block, state *state.DB, receipts types.Receipts, cxReceipts types.CXReceipts, usedGas uint64) error
 }
 
 // Processor is an interface for processing blocks using a given initial state.
 //
 // Process takes the block to be processed and the statedb upon which the
-// initial state is based. It should return the receipts generated, amount
-// of gas used in the process and return an error if any of the internal rules
-// failed.
+// initial state is based. It should return the receipts and cross-shard
+// receipts generated, amount of gas used in the process and return an error if
+// any of the internal rules failed.
 type Processor interface {
-	Process(block *types.Block, statedb *state.DB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error)
+	Process(block *types.Block, statedb *state.DB, cfg vm.Config) (types.Receipts, types.CXReceipts, []*types.Log, uint64, error)
 }
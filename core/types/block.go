@@ -100,6 +100,10 @@ type Header struct {
 
 	RandSeed       uint64      `json:"randomSeed"`
 	ShardStateHash common.Hash `json:"shardStateRoot"`
+
+	// Cross-shard receipts sent by and received in this block
+	OutgoingReceiptHash common.Hash `json:"outgoingReceiptsRoot"`
+	IncomingReceiptHash common.Hash `json:"incomingReceiptsRoot"`
 }
 
 // field type overrides for gencodec
@@ -119,6 +123,17 @@ func (h *Header) Hash() common.Hash {
 	return rlpHash(h)
 }
 
+// HashWithoutSignatures returns the hash of the header before the consensus
+// signatures were added, which is the hash signed by the committee.
+func (h *Header) HashWithoutSignatures() common.Hash {
+	cpy := *h
+	cpy.PrepareSignature = [48]byte{}
+	cpy.PrepareBitmap = nil
+	cpy.CommitSignature = [48]byte{}
+	cpy.CommitBitmap = nil
+	return cpy.Hash()
+}
+
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (h *Header) Size() common.StorageSize {
@@ -133,17 +148,20 @@ func rlpHash(x interface{}) (h common.Hash) {
 }
 
 // Body is a simple (mutable, non-safe) data container for storing and moving
-// a block's data contents (transactions and uncles) together.
+// a block's data contents (transactions, uncles and incoming cross-shard
+// receipts) together.
 type Body struct {
-	Transactions []*Transaction
-	Uncles       []*Header
+	Transactions     []*Transaction
+	Uncles           []*Header
+	IncomingReceipts CXReceiptsProofs
 }
 
 // Block represents an entire block in the Ethereum blockchain.
 type Block struct {
-	header       *Header
-	uncles       []*Header
-	transactions Transactions
+	header           *Header
+	uncles           []*Header
+	transactions     Transactions
+	incomingReceipts CXReceiptsProofs
 
 	// caches
 	hash atomic.Value
@@ -174,9 +192,10 @@ type StorageBlock Block
 
 // "external" block encoding. used for eth protocol, etc.
 type extblock struct {
-	Header *Header
-	Txs    []*Transaction
-	Uncles []*Header
+	Header           *Header
+	Txs              []*Transaction
+	Uncles           []*Header
+	IncomingReceipts CXReceiptsProofs
 }
 
 // [deprecated by eth/63]
@@ -192,10 +211,10 @@ type storageblock struct {
 // changes to header and to the field values will not affect the
 // block.
 //
-// The values of TxHash, UncleHash, ReceiptHash and Bloom in header
-// are ignored and set to values derived from the given txs,
-// and receipts.
-func NewBlock(header *Header, txs []*Transaction, receipts []*Receipt) *Block {
+// The values of TxHash, UncleHash, ReceiptHash, Bloom and the cross-shard
+// receipt hashes in header are ignored and set to values derived from the
+// given txs, receipts, outgoing and incoming cross-shard receipts.
+func NewBlock(header *Header, txs []*Transaction, receipts []*Receipt, outcxs CXReceipts, incxs CXReceiptsProofs) *Block {
 	b := &Block{header: CopyHeader(header), td: new(big.Int)}
 
 	// TODO: panic if len(txs) != len(receipts)
@@ -214,6 +233,15 @@ func NewBlock(header *Header, txs []*Transaction, receipts []*Receipt) *Block {
 		b.header.Bloom = CreateBloom(receipts)
 	}
 
+	b.header.OutgoingReceiptHash = DeriveOutgoingReceiptHash(outcxs)
+	if len(incxs) == 0 {
+		b.header.IncomingReceiptHash = EmptyRootHash
+	} else {
+		b.header.IncomingReceiptHash = DeriveSha(incxs)
+		b.incomingReceipts = make(CXReceiptsProofs, len(incxs))
+		copy(b.incomingReceipts, incxs)
+	}
+
 	return b
 }
 
@@ -251,7 +279,7 @@ func (b *Block) DecodeRLP(s *rlp.Stream) error {
 	if err := s.Decode(&eb); err != nil {
 		return err
 	}
-	b.header, b.uncles, b.transactions = eb.Header, eb.Uncles, eb.Txs
+	b.header, b.uncles, b.transactions, b.incomingReceipts = eb.Header, eb.Uncles, eb.Txs, eb.IncomingReceipts
 	b.size.Store(common.StorageSize(rlp.ListSize(size)))
 	return nil
 }
@@ -259,9 +287,10 @@ func (b *Block) DecodeRLP(s *rlp.Stream) error {
 // EncodeRLP serializes b into the Ethereum RLP block format.
 func (b *Block) EncodeRLP(w io.Writer) error {
 	return rlp.Encode(w, extblock{
-		Header: b.header,
-		Txs:    b.transactions,
-		Uncles: b.uncles,
+		Header:           b.header,
+		Txs:              b.transactions,
+		Uncles:           b.uncles,
+		IncomingReceipts: b.incomingReceipts,
 	})
 }
 
@@ -286,6 +315,11 @@ func (b *Block) Transactions() Transactions {
 	return b.transactions
 }
 
+// IncomingReceipts returns the cross-shard receipts received in the block.
+func (b *Block) IncomingReceipts() CXReceiptsProofs {
+	return b.incomingReceipts
+}
+
 // Transaction returns Transaction.
 func (b *Block) Transaction(hash common.Hash) *Transaction {
 	for _, transaction := range b.transactions {
@@ -348,7 +382,7 @@ func (b *Block) Extra() []byte { return common.CopyBytes(b.header.Extra) }
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
-func (b *Block) Body() *Body { return &Body{b.transactions, b.uncles} }
+func (b *Block) Body() *Body { return &Body{b.transactions, b.uncles, b.incomingReceipts} }
 
 // Size returns the true RLP encoded storage size of the block, either by encoding
 // and returning it, or returning a previsouly cached value.
@@ -380,20 +414,24 @@ func (b *Block) WithSeal(header *Header) *Block {
 	cpy := *header
 
 	return &Block{
-		header:       &cpy,
-		transactions: b.transactions,
-		uncles:       b.uncles,
+		header:           &cpy,
+		transactions:     b.transactions,
+		uncles:           b.uncles,
+		incomingReceipts: b.incomingReceipts,
 	}
 }
 
-// WithBody returns a new block with the given transaction and uncle contents.
-func (b *Block) WithBody(transactions []*Transaction, uncles []*Header) *Block {
+// WithBody returns a new block with the given transaction, uncle and incoming
+// cross-shard receipt contents.
+func (b *Block) WithBody(transactions []*Transaction, uncles []*Header, incomingReceipts CXReceiptsProofs) *Block {
 	block := &Block{
-		header:       CopyHeader(b.header),
-		transactions: make([]*Transaction, len(transactions)),
-		uncles:       make([]*Header, len(uncles)),
+		header:           CopyHeader(b.header),
+		transactions:     make([]*Transaction, len(transactions)),
+		uncles:           make([]*Header, len(uncles)),
+		incomingReceipts: make(CXReceiptsProofs, len(incomingReceipts)),
 	}
 	copy(block.transactions, transactions)
+	copy(block.incomingReceipts, incomingReceipts)
 	for i := range uncles {
 		block.uncles[i] = CopyHeader(uncles[i])
 	}
@@ -460,3 +498,19 @@ func (b *Block) AddRandSeed(randSeed int64) {
 func (b *Block) AddShardStateHash(shardStateHash common.Hash) {
 	b.header.ShardStateHash = shardStateHash
 }
+
+// SetPrepareSig sets the aggregated prepare signature and the bitmap of the
+// signers into the block header. The cached block hash is updated.
+func (b *Block) SetPrepareSig(sig []byte, bitmap []byte) {
+	copy(b.header.PrepareSignature[:], sig)
+	b.header.PrepareBitmap = append(bitmap[:0:0], bitmap...)
+	b.hash.Store(b.header.Hash())
+}
+
+// SetCommitSig sets the aggregated commit signature and the bitmap of the
+// signers into the block header. The cached block hash is updated.
+func (b *Block) SetCommitSig(sig []byte, bitmap []byte) {
+	copy(b.header.CommitSignature[:], sig)
+	b.header.CommitBitmap = append(bitmap[:0:0], bitmap...)
+	b.hash.Store(b.header.Hash())
+}
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Errors returned when verifying a cross-shard receipts proof.
+var (
+	ErrInvalidCXReceiptsProof = errors.New("incomplete cross-shard receipts proof")
+	ErrCXReceiptWrongShard    = errors.New("cross-shard receipt is not between the proof's shards")
+	ErrCXReceiptsMismatch     = errors.New("cross-shard receipts don't match the merkle proof")
+	ErrCXReceiptRootMismatch  = errors.New("merkle proof doesn't match the outgoing receipt root")
+)
+
+// CXReceipt is the receipt of a cross-shard transfer. The amount was debited from
+// the sender on the source shard and is credited to the recipient on the
+// destination shard.
+type CXReceipt struct {
+	TxHash    common.Hash
+	From      common.Address
+	To        common.Address
+	ShardID   uint32
+	ToShardID uint32
+	Amount    *big.Int
+}
+
+// CXReceipts is a list of cross-shard receipts.
+type CXReceipts []*CXReceipt
+
+// Len returns the length of the list.
+func (cs CXReceipts) Len() int { return len(cs) }
+
+// GetRlp returns the RLP encoding of one receipt from the list.
+func (cs CXReceipts) GetRlp(i int) []byte {
+	enc, _ := rlp.EncodeToBytes(cs[i])
+	return enc
+}
+
+// ToShard returns the receipts destined to the given shard, keeping their order.
+func (cs CXReceipts) ToShard(shardID uint32) CXReceipts {
+	var result CXReceipts
+	for _, cx := range cs {
+		if cx.ToShardID == shardID {
+			result = append(result, cx)
+		}
+	}
+	return result
+}
+
+// ToShardIDs returns the sorted list of distinct destination shards.
+func (cs CXReceipts) ToShardIDs() []uint32 {
+	seen := make(map[uint32]bool)
+	var shardIDs []uint32
+	for _, cx := range cs {
+		if !seen[cx.ToShardID] {
+			seen[cx.ToShardID] = true
+			shardIDs = append(shardIDs, cx.ToShardID)
+		}
+	}
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+	return shardIDs
+}
+
+// CXMerkleProof lists the receipt root of every destination shard of a block. The
+// outgoing receipt root in the block header is the hash of this list, so it proves
+// the receipts to one shard without revealing the receipts to the other shards.
+type CXMerkleProof struct {
+	ShardIDs      []uint32      // destination shards, sorted
+	CXShardHashes []common.Hash // receipt root of each destination shard
+}
+
+// NewCXMerkleProof creates the merkle proof of the given outgoing receipts.
+func NewCXMerkleProof(cxs CXReceipts) *CXMerkleProof {
+	proof := &CXMerkleProof{}
+	for _, shardID := range cxs.ToShardIDs() {
+		proof.ShardIDs = append(proof.ShardIDs, shardID)
+		proof.CXShardHashes = append(proof.CXShardHashes, DeriveSha(cxs.ToShard(shardID)))
+	}
+	return proof
+}
+
+// Root returns the outgoing receipt root the proof commits to.
+func (p *CXMerkleProof) Root() common.Hash {
+	return rlpHash(p)
+}
+
+// DeriveOutgoingReceiptHash calculates the outgoing receipt root of a block.
+func DeriveOutgoingReceiptHash(cxs CXReceipts) common.Hash {
+	if len(cxs) == 0 {
+		return EmptyRootHash
+	}
+	return NewCXMerkleProof(cxs).Root()
+}
+
+// CXReceiptsProof carries the receipts sent from one source block to one
+// destination shard, together with the proof that the source shard committed them.
+type CXReceiptsProof struct {
+	Receipts    CXReceipts
+	MerkleProof *CXMerkleProof
+	Header      *Header // signed header of the source block
+}
+
+// NewCXReceiptsProof creates the proof of the receipts sent to toShardID by the
+// block with the given header and outgoing receipts.
+func NewCXReceiptsProof(header *Header, cxs CXReceipts, toShardID uint32) *CXReceiptsProof {
+	return &CXReceiptsProof{
+		Receipts:    cxs.ToShard(toShardID),
+		MerkleProof: NewCXMerkleProof(cxs),
+		Header:      CopyHeader(header),
+	}
+}
+
+// ShardID returns the source shard of the receipts.
+func (cxp *CXReceiptsProof) ShardID() uint32 {
+	return binary.BigEndian.Uint32(cxp.Header.ShardID[:])
+}
+
+// BlockNumber returns the number of the source block.
+func (cxp *CXReceiptsProof) BlockNumber() uint64 {
+	return cxp.Header.Number.Uint64()
+}
+
+// Verify checks that the receipts go from the source shard to toShardID and are
+// part of the outgoing receipt root of the source block. The signature of the
+// source block header is not checked here.
+func (cxp *CXReceiptsProof) Verify(toShardID uint32) error {
+	if cxp.Header == nil || cxp.Header.Number == nil || cxp.MerkleProof == nil || len(cxp.Receipts) == 0 {
+		return ErrInvalidCXReceiptsProof
+	}
+	for _, cx := range cxp.Receipts {
+		if cx.ShardID != cxp.ShardID() || cx.ToShardID != toShardID || cx.Amount == nil {
+			return ErrCXReceiptWrongShard
+		}
+	}
+	proof := cxp.MerkleProof
+	if len(proof.ShardIDs) != len(proof.CXShardHashes) {
+		return ErrInvalidCXReceiptsProof
+	}
+	found := false
+	for i, shardID := range proof.ShardIDs {
+		if shardID == toShardID {
+			if proof.CXShardHashes[i] != DeriveSha(cxp.Receipts) {
+				return ErrCXReceiptsMismatch
+			}
+			found = true
+		}
+	}
+	if !found {
+		return ErrCXReceiptsMismatch
+	}
+	if proof.Root() != cxp.Header.OutgoingReceiptHash {
+		return ErrCXReceiptRootMismatch
+	}
+	return nil
+}
+
+// CXReceiptsProofs is a list of cross-shard receipts proofs.
+type CXReceiptsProofs []*CXReceiptsProof
+
+// Len returns the length of the list.
+func (cs CXReceiptsProofs) Len() int { return len(cs) }
+
+// GetRlp returns the RLP encoding of one proof from the list.
+func (cs CXReceiptsProofs) GetRlp(i int) []byte {
+	enc, _ := rlp.EncodeToBytes(cs[i])
+	return enc
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func testCXReceipts() CXReceipts {
+	return CXReceipts{
+		{TxHash: common.Hash{1}, To: common.Address{1}, ShardID: 0, ToShardID: 2, Amount: big.NewInt(10)},
+		{TxHash: common.Hash{2}, To: common.Address{2}, ShardID: 0, ToShardID: 1, Amount: big.NewInt(20)},
+		{TxHash: common.Hash{3}, To: common.Address{3}, ShardID: 0, ToShardID: 2, Amount: big.NewInt(30)},
+	}
+}
+
+func TestCXReceiptsProof(t *testing.T) {
+	cxs := testCXReceipts()
+	header := &Header{Number: big.NewInt(5), ShardID: EncodeShardID(0)}
+	block := NewBlock(header, nil, nil, cxs, nil)
+	if block.Header().OutgoingReceiptHash != DeriveOutgoingReceiptHash(cxs) {
+		t.Fatal("block should commit to its outgoing receipts")
+	}
+
+	cxp := NewCXReceiptsProof(block.Header(), cxs, 2)
+	if len(cxp.Receipts) != 2 {
+		t.Fatalf("expected 2 receipts to shard 2, got %d", len(cxp.Receipts))
+	}
+	if cxp.ShardID() != 0 || cxp.BlockNumber() != 5 {
+		t.Errorf("unexpected source shard %d or block %d", cxp.ShardID(), cxp.BlockNumber())
+	}
+
+	data, err := rlp.EncodeToBytes(cxp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &CXReceiptsProof{}
+	if err := rlp.DecodeBytes(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(2); err != nil {
+		t.Errorf("valid proof should verify: %v", err)
+	}
+	if err := decoded.Verify(1); err != ErrCXReceiptWrongShard {
+		t.Errorf("expected %v, got %v", ErrCXReceiptWrongShard, err)
+	}
+}
+
+func TestCXReceiptsProofTampered(t *testing.T) {
+	cxs := testCXReceipts()
+	header := NewBlock(&Header{Number: big.NewInt(5)}, nil, nil, cxs, nil).Header()
+
+	cxp := NewCXReceiptsProof(header, cxs, 2)
+	cxp.Receipts[0] = &CXReceipt{TxHash: common.Hash{1}, To: common.Address{1}, ToShardID: 2, Amount: big.NewInt(1000)}
+	if err := cxp.Verify(2); err != ErrCXReceiptsMismatch {
+		t.Errorf("expected %v, got %v", ErrCXReceiptsMismatch, err)
+	}
+
+	cxp = NewCXReceiptsProof(header, cxs, 2)
+	cxp.MerkleProof.ShardIDs = cxp.MerkleProof.ShardIDs[1:]
+	cxp.MerkleProof.CXShardHashes = cxp.MerkleProof.CXShardHashes[1:]
+	if err := cxp.Verify(2); err != ErrCXReceiptRootMismatch {
+		t.Errorf("expected %v, got %v", ErrCXReceiptRootMismatch, err)
+	}
+
+	if err := (&CXReceiptsProof{Header: header}).Verify(2); err != ErrInvalidCXReceiptsProof {
+		t.Errorf("expected %v, got %v", ErrInvalidCXReceiptsProof, err)
+	}
+}
@@ -48,6 +48,7 @@ type Transaction struct {
 type txdata struct {
 	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
 	ShardID      uint32          `json:"shardID"  gencodec:"required"`
+	ToShardID    uint32          `json:"toShardID"`
 	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
 	GasLimit     uint64          `json:"gas"      gencodec:"required"`
 	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
@@ -76,15 +77,21 @@ type txdataMarshaling struct {
 
 // NewTransaction returns new transaction.
 func NewTransaction(nonce uint64, to common.Address, shardID uint32, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	return newTransaction(nonce, &to, shardID, amount, gasLimit, gasPrice, data)
+	return newTransaction(nonce, &to, shardID, shardID, amount, gasLimit, gasPrice, data)
+}
+
+// NewCrossShardTransaction returns a new transaction moving funds from shardID to
+// the recipient on toShardID.
+func NewCrossShardTransaction(nonce uint64, to common.Address, shardID uint32, toShardID uint32, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
+	return newTransaction(nonce, &to, shardID, toShardID, amount, gasLimit, gasPrice, data)
 }
 
 // NewContractCreation returns contract transaction.
 func NewContractCreation(nonce uint64, shardID uint32, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
-	return newTransaction(nonce, nil, shardID, amount, gasLimit, gasPrice, data)
+	return newTransaction(nonce, nil, shardID, shardID, amount, gasLimit, gasPrice, data)
 }
 
-func newTransaction(nonce uint64, to *common.Address, shardID uint32, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
+func newTransaction(nonce uint64, to *common.Address, shardID uint32, toShardID uint32, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
 	}
@@ -92,6 +99,7 @@ func newTransaction(nonce uint64, to *common.Address, shardID uint32, amount *bi
 		AccountNonce: nonce,
 		Recipient:    to,
 		ShardID:      shardID,
+		ToShardID:    toShardID,
 		Payload:      data,
 		Amount:       new(big.Int),
 		GasLimit:     gasLimit,
@@ -120,6 +128,16 @@ func (tx *Transaction) ShardID() uint32 {
 	return tx.data.ShardID
 }
 
+// ToShardID returns the destination shard id of the transaction.
+func (tx *Transaction) ToShardID() uint32 {
+	return tx.data.ToShardID
+}
+
+// IsCrossShard returns whether the transaction moves funds to another shard.
+func (tx *Transaction) IsCrossShard() bool {
+	return tx.data.ToShardID != tx.data.ShardID
+}
+
 // Protected returns whether the transaction is protected from replay protection.
 func (tx *Transaction) Protected() bool {
 	return isProtectedV(tx.data.V)
@@ -260,6 +278,8 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 		amount:     tx.data.Amount,
 		data:       tx.data.Payload,
 		checkNonce: true,
+		shardID:    tx.data.ShardID,
+		toShardID:  tx.data.ToShardID,
 	}
 
 	var err error
@@ -429,6 +449,8 @@ type Message struct {
 	gasPrice   *big.Int
 	data       []byte
 	checkNonce bool
+	shardID    uint32
+	toShardID  uint32
 }
 
 // NewMessage returns new message.
@@ -484,3 +506,13 @@ func (m Message) Data() []byte {
 func (m Message) CheckNonce() bool {
 	return m.checkNonce
 }
+
+// ShardID returns the shard the Message is executed on.
+func (m Message) ShardID() uint32 {
+	return m.shardID
+}
+
+// ToShardID returns the destination shard of the value of the Message.
+func (m Message) ToShardID() uint32 {
+	return m.toShardID
+}
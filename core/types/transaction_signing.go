@@ -157,15 +157,7 @@ func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
-		s.chainID, uint(0), uint(0),
-	})
+	return rlpHash(append(sigHashFields(tx), s.chainID, uint(0), uint(0)))
 }
 
 // HomesteadSigner implements TransactionInterface using the
@@ -213,14 +205,25 @@ func (fs FrontierSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	return rlpHash(sigHashFields(tx))
+}
+
+// sigHashFields returns the transaction fields covered by the signature. The shard
+// IDs are signed only for cross-shard transactions, so that the destination shard
+// can't be altered while intra-shard transactions keep the Ethereum signing hash.
+func sigHashFields(tx *Transaction) []interface{} {
+	fields := []interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
 		tx.data.Recipient,
 		tx.data.Amount,
 		tx.data.Payload,
-	})
+	}
+	if tx.IsCrossShard() {
+		fields = append(fields, tx.data.ShardID, tx.data.ToShardID)
+	}
+	return fields
 }
 
 // Sender returns the sender address of the given transaction.
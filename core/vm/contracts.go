@@ -26,6 +26,8 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/bn256"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -59,6 +61,33 @@ var PrecompiledContractsByzantium = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
 }
 
+// PrecompiledContractsBLS contains the Byzantium set of pre-compiled contracts
+// and the BLS contracts, used from the BLS precompiles activation block of the
+// chain on. The BLS contracts are at the end of the one byte addresses, away
+// from the ones Ethereum allocates.
+var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256Add{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMul{},
+	common.BytesToAddress([]byte{8}): &bn256Pairing{},
+
+	common.BytesToAddress([]byte{0xfb}): &blsVerify{},
+	common.BytesToAddress([]byte{0xfc}): &blsAggregateVerify{},
+	common.BytesToAddress([]byte{0xfd}): &blsAggregatePubKeys{},
+}
+
+// Gas schedule of the BLS contracts. A verification is priced for its two
+// pairings, and each public key for its deserialization and addition.
+const (
+	blsVerifyGas        uint64 = 150000 // Base price for a BLS signature verification
+	blsVerifyPerWordGas uint64 = 12     // Per-word price for hashing the signed message
+	blsPublicKeyGas     uint64 = 1500   // Price per public key deserialized and aggregated
+)
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -358,3 +387,120 @@ func (c *bn256Pairing) Run(input []byte) ([]byte, error) {
 	}
 	return false32Byte, nil
 }
+
+const (
+	// blsPublicKeyLength is the length of a BLS public key serialized by
+	// harmony-one/bls on BLS12-381, a G2 point.
+	blsPublicKeyLength = 96
+
+	// blsSignatureLength is the length of a BLS signature serialized by
+	// harmony-one/bls on BLS12-381, a G1 point as in the block headers.
+	blsSignatureLength = 48
+)
+
+// errBadBLSInput is returned if the input of a BLS contract is invalid.
+var errBadBLSInput = errors.New("bad BLS input")
+
+// blsVerify implements BLS signature verification as a native contract. The
+// input is the public key, the signature and the signed message, the message
+// being signed as a hash like the consensus messages.
+type blsVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsVerify) RequiredGas(input []byte) uint64 {
+	return blsVerifyGas + blsPublicKeyGas + uint64(len(input)+31)/32*blsVerifyPerWordGas
+}
+
+func (c *blsVerify) Run(input []byte) ([]byte, error) {
+	if len(input) < blsPublicKeyLength+blsSignatureLength {
+		return nil, errBadBLSInput
+	}
+	pubKey := &bls.PublicKey{}
+	if err := pubKey.Deserialize(input[:blsPublicKeyLength]); err != nil {
+		return nil, errBadBLSInput
+	}
+	return verifyBLS(pubKey, input[blsPublicKeyLength:])
+}
+
+// blsAggregateVerify implements BLS multi-signature verification as a native
+// contract. The input is the number of public keys as a 32 byte word, the public
+// keys, the aggregated signature and the signed message.
+type blsAggregateVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsAggregateVerify) RequiredGas(input []byte) uint64 {
+	keys := uint64(len(input) / blsPublicKeyLength)
+	if count := new(big.Int).SetBytes(getData(input, 0, 32)); count.IsUint64() && count.Uint64() < keys {
+		keys = count.Uint64()
+	}
+	return blsVerifyGas + keys*blsPublicKeyGas + uint64(len(input)+31)/32*blsVerifyPerWordGas
+}
+
+func (c *blsAggregateVerify) Run(input []byte) ([]byte, error) {
+	if len(input) < 32 {
+		return nil, errBadBLSInput
+	}
+	count := new(big.Int).SetBytes(input[:32])
+	if count.Sign() == 0 || count.Cmp(big.NewInt(int64((len(input)-32-blsSignatureLength)/blsPublicKeyLength))) > 0 {
+		return nil, errBadBLSInput
+	}
+	end := 32 + int(count.Int64())*blsPublicKeyLength
+	pubKey, err := aggregateBLSPubKeys(input[32:end])
+	if err != nil {
+		return nil, err
+	}
+	return verifyBLS(pubKey, input[end:])
+}
+
+// blsAggregatePubKeys implements BLS public key aggregation as a native contract.
+// The input is the public keys and the output the aggregated public key, which
+// verifies the multi-signature of the keys.
+type blsAggregatePubKeys struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *blsAggregatePubKeys) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)/blsPublicKeyLength) * blsPublicKeyGas
+}
+
+func (c *blsAggregatePubKeys) Run(input []byte) ([]byte, error) {
+	if len(input) == 0 {
+		return nil, errBadBLSInput
+	}
+	pubKey, err := aggregateBLSPubKeys(input)
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.Serialize(), nil
+}
+
+// aggregateBLSPubKeys aggregates the serialized public keys of the input.
+func aggregateBLSPubKeys(input []byte) (*bls.PublicKey, error) {
+	if len(input)%blsPublicKeyLength != 0 {
+		return nil, errBadBLSInput
+	}
+	pubKeys := make([]*bls.PublicKey, 0, len(input)/blsPublicKeyLength)
+	for i := 0; i < len(input); i += blsPublicKeyLength {
+		pubKey := &bls.PublicKey{}
+		if err := pubKey.Deserialize(input[i : i+blsPublicKeyLength]); err != nil {
+			return nil, errBadBLSInput
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return bls_cosi.AggregatePubKeys(pubKeys), nil
+}
+
+// verifyBLS verifies the signature followed by the signed message of the input
+// with the public key.
+func verifyBLS(pubKey *bls.PublicKey, input []byte) ([]byte, error) {
+	if len(input) < blsSignatureLength {
+		return nil, errBadBLSInput
+	}
+	sig := &bls.Sign{}
+	if err := sig.Deserialize(input[:blsSignatureLength]); err != nil {
+		return nil, errBadBLSInput
+	}
+	if sig.VerifyHash(pubKey, input[blsSignatureLength:]) {
+		return true32Byte, nil
+	}
+	return false32Byte, nil
+}
@@ -22,6 +22,11 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/bls/ffi/go/bls"
+	bls_cosi "github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/crypto/pki"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -337,7 +342,7 @@ var bn256PairingTests = []precompiledTest{
 }
 
 func testPrecompiled(addr string, test precompiledTest, t *testing.T) {
-	p := PrecompiledContractsByzantium[common.HexToAddress(addr)]
+	p := PrecompiledContractsBLS[common.HexToAddress(addr)]
 	in := common.Hex2Bytes(test.input)
 	contract := NewContract(AccountRef(common.HexToAddress("1337")),
 		nil, new(big.Int), p.RequiredGas(in))
@@ -354,7 +359,7 @@ func benchmarkPrecompiled(addr string, test precompiledTest, bench *testing.B) {
 	if test.noBenchmark {
 		return
 	}
-	p := PrecompiledContractsByzantium[common.HexToAddress(addr)]
+	p := PrecompiledContractsBLS[common.HexToAddress(addr)]
 	in := common.Hex2Bytes(test.input)
 	reqGas := p.RequiredGas(in)
 	contract := NewContract(AccountRef(common.HexToAddress("1337")),
@@ -481,3 +486,106 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+// blsTests returns the test data for the BLS precompiled contracts, signed by
+// keys of crypto/pki over a consensus like message hash.
+func blsTests() (verifyTests, aggregateVerifyTests, aggregatePubKeysTests []precompiledTest) {
+	var (
+		message  = crypto.Keccak256([]byte("harmony"))
+		keys     = []*bls.SecretKey{pki.GetBLSPrivateKeyFromInt(333), pki.GetBLSPrivateKeyFromInt(444), pki.GetBLSPrivateKeyFromInt(555)}
+		pubKeys  []*bls.PublicKey
+		sigs     []*bls.Sign
+		keysData []byte
+	)
+	for _, key := range keys {
+		pubKeys = append(pubKeys, key.GetPublicKey())
+		sigs = append(sigs, key.SignHash(message))
+		keysData = append(keysData, key.GetPublicKey().Serialize()...)
+	}
+	var (
+		pubKey     = common.Bytes2Hex(pubKeys[0].Serialize())
+		sig        = common.Bytes2Hex(sigs[0].Serialize())
+		wrongSig   = common.Bytes2Hex(sigs[1].Serialize())
+		aggSig     = common.Bytes2Hex(bls_cosi.AggregateSig(sigs).Serialize())
+		msg        = common.Bytes2Hex(message)
+		wrongMsg   = common.Bytes2Hex(crypto.Keccak256([]byte("ethereum")))
+		true32     = common.Bytes2Hex(true32Byte)
+		false32    = common.Bytes2Hex(false32Byte)
+		count      = func(n int64) string { return common.Bytes2Hex(common.LeftPadBytes(big.NewInt(n).Bytes(), 32)) }
+		twoKeys    = common.Bytes2Hex(keysData[:2*blsPublicKeyLength])
+		threeKeys  = common.Bytes2Hex(keysData)
+		aggPubKey  = common.Bytes2Hex(bls_cosi.AggregatePubKeys(pubKeys).Serialize())
+		twoAggKeys = common.Bytes2Hex(bls_cosi.AggregatePubKeys(pubKeys[:2]).Serialize())
+	)
+	verifyTests = []precompiledTest{
+		{input: pubKey + sig + msg, expected: true32, name: "valid"},
+		{input: pubKey + wrongSig + msg, expected: false32, name: "wrong_signer"},
+		{input: pubKey + sig + wrongMsg, expected: false32, name: "wrong_message"},
+	}
+	aggregateVerifyTests = []precompiledTest{
+		{input: count(3) + threeKeys + aggSig + msg, expected: true32, name: "three_signers"},
+		{input: count(2) + twoKeys + aggSig + msg, expected: false32, name: "missing_signer"},
+		{input: count(3) + threeKeys + aggSig + wrongMsg, expected: false32, name: "wrong_message"},
+		{input: count(1) + pubKey + sig + msg, expected: true32, name: "one_signer"},
+	}
+	aggregatePubKeysTests = []precompiledTest{
+		{input: threeKeys, expected: aggPubKey, name: "three_keys"},
+		{input: twoKeys, expected: twoAggKeys, name: "two_keys"},
+		{input: pubKey, expected: pubKey, name: "one_key"},
+	}
+	return verifyTests, aggregateVerifyTests, aggregatePubKeysTests
+}
+
+// Tests the BLS precompiled contracts with signatures of crypto/pki keys.
+func TestPrecompiledBLS(t *testing.T) {
+	verifyTests, aggregateVerifyTests, aggregatePubKeysTests := blsTests()
+	for _, test := range verifyTests {
+		testPrecompiled("fb", test, t)
+	}
+	for _, test := range aggregateVerifyTests {
+		testPrecompiled("fc", test, t)
+	}
+	for _, test := range aggregatePubKeysTests {
+		testPrecompiled("fd", test, t)
+	}
+}
+
+// Tests that the BLS precompiled contracts reject malformed inputs.
+func TestPrecompiledBLSBadInput(t *testing.T) {
+	verifyTests, aggregateVerifyTests, _ := blsTests()
+	valid := common.Hex2Bytes(aggregateVerifyTests[0].input)
+	for addr, input := range map[string][]byte{
+		"fb": common.Hex2Bytes(verifyTests[0].input)[:blsPublicKeyLength+blsSignatureLength-1],
+		"fc": append(common.LeftPadBytes([]byte{4}, 32), valid[32:]...),
+		"fd": make([]byte, blsPublicKeyLength+1),
+	} {
+		p := PrecompiledContractsBLS[common.HexToAddress(addr)]
+		contract := NewContract(AccountRef(common.HexToAddress("1337")), nil, new(big.Int), p.RequiredGas(input))
+		if _, err := RunPrecompiledContract(p, input, contract); err != errBadBLSInput {
+			t.Errorf("Expected %v for contract %s, got %v", errBadBLSInput, addr, err)
+		}
+	}
+}
+
+// Benchmarks the BLS verification of a three signers multi-signature.
+func BenchmarkPrecompiledBLSAggregateVerify(bench *testing.B) {
+	_, aggregateVerifyTests, _ := blsTests()
+	benchmarkPrecompiled("fc", aggregateVerifyTests[0], bench)
+}
+
+// Tests that the BLS precompiled contracts are only active from their activation
+// block on.
+func TestPrecompiledBLSActivation(t *testing.T) {
+	blsVerifyAddr := common.HexToAddress("fb")
+	evm := NewEVM(Context{BlockNumber: big.NewInt(1)}, nil, params.TestChainConfig, Config{})
+	if evm.precompiles()[blsVerifyAddr] != nil {
+		t.Errorf("BLS contracts active before their activation block")
+	}
+	if evm.precompiles()[common.HexToAddress("08")] == nil {
+		t.Errorf("Byzantium contracts not active")
+	}
+	evm = NewEVM(Context{BlockNumber: big.NewInt(1), BLSPrecompiles: true}, nil, params.TestChainConfig, Config{})
+	if evm.precompiles()[blsVerifyAddr] == nil {
+		t.Errorf("BLS contracts not active after their activation block")
+	}
+}
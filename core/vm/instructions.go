@@ -875,7 +875,11 @@ func opStop(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 func opSuicide(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	balance := interpreter.evm.StateDB.GetBalance(contract.Address())
-	interpreter.evm.StateDB.AddBalance(common.BigToAddress(stack.pop()), balance)
+	beneficiary := common.BigToAddress(stack.pop())
+	if balance.Sign() != 0 && !interpreter.evm.canReceive(beneficiary) {
+		return nil, ErrTransferRejected
+	}
+	interpreter.evm.StateDB.AddBalance(beneficiary, balance)
 
 	interpreter.evm.StateDB.Suicide(contract.Address())
 	return nil, nil
@@ -0,0 +1,216 @@
+package tracers
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// CallFrame is a message call or a contract creation in the call tree of a
+// traced execution.
+type CallFrame struct {
+	Type string         `json:"type"`
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+	// Value is nil for the calls not transferring value (DELEGATECALL, STATICCALL).
+	Value *hexutil.Big `json:"value,omitempty"`
+	// Gas and GasUsed are zero for calls to accounts without code, where the gas
+	// given to the callee is not known.
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*CallFrame   `json:"calls,omitempty"`
+
+	gasIn   uint64 // gas of the caller before the call
+	gasCost uint64 // cost of the call, including the gas given to the callee
+	outOff  int64  // offset of the memory receiving the output
+	outLen  int64  // length of the memory receiving the output
+}
+
+// CallTracer is a tracer building the tree of the message calls and contract
+// creations made during an execution.
+type CallTracer struct {
+	callstack []*CallFrame // frames of the calls being executed, the top-level call first
+	descended bool         // whether the last step entered a new call
+}
+
+// NewCallTracer returns a new call tracer.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// CaptureStart implements the vm.Tracer interface to record the top-level call.
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	frame := &CallFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.callstack = []*CallFrame{frame}
+	return nil
+}
+
+// CaptureState implements the vm.Tracer interface to follow the calls entered
+// and returned from.
+func (t *CallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	// The gas given to the callee is only known from its first step, as the call
+	// stipend and the 63/64 rule apply to the requested gas.
+	if t.descended {
+		if depth >= len(t.callstack) {
+			t.callstack[len(t.callstack)-1].Gas = hexutil.Uint64(gas)
+		}
+		t.descended = false
+	}
+	// Back in the caller, the call has returned.
+	if depth == len(t.callstack)-1 && len(stack.Data()) > 0 {
+		t.pop(env, gas, memory, stack.Back(0))
+	}
+	if err != nil {
+		t.fault(err)
+		return nil
+	}
+
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		offset, size := stack.Back(1), stack.Back(2)
+		t.push(&CallFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			Value:   (*hexutil.Big)(new(big.Int).Set(stack.Back(0))),
+			Input:   memory.Get(offset.Int64(), size.Int64()),
+			gasIn:   gas,
+			gasCost: cost,
+		})
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// Precompiled contracts don't run in the interpreter, they are just
+		// expensive opcodes.
+		to := common.BigToAddress(stack.Back(1))
+		if isPrecompiled(env, to) {
+			return nil
+		}
+		frame := &CallFrame{
+			Type:    op.String(),
+			From:    contract.Address(),
+			To:      to,
+			gasIn:   gas,
+			gasCost: cost,
+		}
+		arg := 2
+		if op == vm.CALL || op == vm.CALLCODE {
+			frame.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+			arg = 3
+		}
+		frame.Input = memory.Get(stack.Back(arg).Int64(), stack.Back(arg+1).Int64())
+		frame.outOff, frame.outLen = stack.Back(arg+2).Int64(), stack.Back(arg+3).Int64()
+		t.push(frame)
+	case vm.SELFDESTRUCT:
+		current := t.callstack[len(t.callstack)-1]
+		current.Calls = append(current.Calls, &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    common.BigToAddress(stack.Back(0)),
+			Value: (*hexutil.Big)(env.StateDB.GetBalance(contract.Address())),
+		})
+	case vm.REVERT:
+		t.callstack[len(t.callstack)-1].Error = "execution reverted"
+	}
+	return nil
+}
+
+// CaptureFault implements the vm.Tracer interface to fail the call in which an
+// executed opcode failed.
+func (t *CallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	t.fault(err)
+	return nil
+}
+
+// CaptureEnd implements the vm.Tracer interface to record the result of the
+// top-level call.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if len(t.callstack) == 0 {
+		return nil
+	}
+	frame := t.callstack[0]
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err != nil && frame.Error == "" {
+		frame.Error = err.Error()
+	}
+	return nil
+}
+
+// GetResult returns the top-level call with its nested calls.
+func (t *CallTracer) GetResult() (interface{}, error) {
+	if len(t.callstack) != 1 {
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+	return t.callstack[0], nil
+}
+
+func (t *CallTracer) push(frame *CallFrame) {
+	t.callstack = append(t.callstack, frame)
+	t.descended = true
+}
+
+// pop removes the returned call from the stack and adds it to the calls of its
+// caller, with its result pushed on the stack of the caller.
+func (t *CallTracer) pop(env *vm.EVM, gas uint64, memory *vm.Memory, result *big.Int) {
+	frame := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	if frame.Type == vm.CREATE.String() || frame.Type == vm.CREATE2.String() {
+		frame.GasUsed = hexutil.Uint64(frame.gasIn - frame.gasCost - gas)
+		if result.Sign() != 0 {
+			frame.To = common.BigToAddress(result)
+			frame.Output = env.StateDB.GetCode(frame.To)
+		} else if frame.Error == "" {
+			frame.Error = "internal failure"
+		}
+	} else {
+		if frame.Gas != 0 {
+			frame.GasUsed = hexutil.Uint64(frame.gasIn - frame.gasCost + uint64(frame.Gas) - gas)
+		}
+		if result.Sign() != 0 {
+			frame.Output = memory.Get(frame.outOff, frame.outLen)
+		} else if frame.Error == "" {
+			frame.Error = "internal failure"
+		}
+	}
+	caller := t.callstack[len(t.callstack)-1]
+	caller.Calls = append(caller.Calls, frame)
+}
+
+// fault fails the current call, consuming all its gas. A reverted call already
+// has its error set and returns normally.
+func (t *CallTracer) fault(err error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	frame := t.callstack[len(t.callstack)-1]
+	if frame.Error != "" {
+		return
+	}
+	frame.Error = err.Error()
+	frame.GasUsed = frame.Gas
+	// The top-level call stays on the stack, its gas used is set at the end.
+	if len(t.callstack) == 1 {
+		return
+	}
+	t.callstack = t.callstack[:len(t.callstack)-1]
+	caller := t.callstack[len(t.callstack)-1]
+	caller.Calls = append(caller.Calls, frame)
+}
@@ -0,0 +1,108 @@
+package tracers
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// PrestateAccount is the state of an account before a traced execution, with
+// the storage slots accessed by the execution.
+type PrestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// PrestateTracer is a tracer collecting the state, before the execution, of the
+// accounts touched by the execution. Accounts created by the execution are not
+// part of the result.
+type PrestateTracer struct {
+	state    vm.StateDB
+	prestate map[common.Address]*PrestateAccount
+}
+
+// NewPrestateTracer returns a new prestate tracer reading the accounts from the
+// given state, which must be the state before the execution.
+func NewPrestateTracer(state vm.StateDB) *PrestateTracer {
+	return &PrestateTracer{
+		state:    state,
+		prestate: make(map[common.Address]*PrestateAccount),
+	}
+}
+
+// CaptureStart implements the vm.Tracer interface to record the sender and the
+// recipient of the top-level call.
+func (t *PrestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+	return nil
+}
+
+// CaptureState implements the vm.Tracer interface to record the accounts and the
+// storage slots accessed by the opcodes.
+func (t *PrestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)))
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH, vm.SELFDESTRUCT:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.BigToAddress(stack.Back(1)))
+	case vm.CREATE:
+		t.lookupAccount(crypto.CreateAddress(contract.Address(), env.StateDB.GetNonce(contract.Address())))
+	case vm.CREATE2:
+		code := memory.Get(stack.Back(1).Int64(), stack.Back(2).Int64())
+		t.lookupAccount(crypto.CreateAddress2(contract.Address(), common.BigToHash(stack.Back(3)), crypto.Keccak256(code)))
+	}
+	return nil
+}
+
+// CaptureFault implements the vm.Tracer interface.
+func (t *PrestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the vm.Tracer interface.
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the prestate of the touched accounts.
+func (t *PrestateTracer) GetResult() (interface{}, error) {
+	return t.prestate, nil
+}
+
+// lookupAccount records the prestate of an account the first time it is touched.
+func (t *PrestateTracer) lookupAccount(address common.Address) {
+	if _, ok := t.prestate[address]; ok || !t.state.Exist(address) {
+		return
+	}
+	t.prestate[address] = &PrestateAccount{
+		Balance: (*hexutil.Big)(new(big.Int).Set(t.state.GetBalance(address))),
+		Nonce:   t.state.GetNonce(address),
+		Code:    common.CopyBytes(t.state.GetCode(address)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage records the prestate of a storage slot the first time it is
+// accessed. The slots of created accounts are skipped.
+func (t *PrestateTracer) lookupStorage(address common.Address, key common.Hash) {
+	account, ok := t.prestate[address]
+	if !ok {
+		return
+	}
+	if _, ok := account.Storage[key]; ok {
+		return
+	}
+	account.Storage[key] = t.state.GetState(address, key)
+}
@@ -0,0 +1,40 @@
+// Package tracers implements the EVM tracers that can be selected by name in the
+// debug API, next to the opcode level vm.StructLogger.
+package tracers
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/harmony/core/vm"
+)
+
+// Tracer is an EVM tracer with a JSON serializable result.
+type Tracer interface {
+	vm.Tracer
+	// GetResult returns the result of the trace once the execution is over.
+	GetResult() (interface{}, error)
+}
+
+// New returns the tracer with the given name. The state is the state before the
+// traced execution, read by the tracers reporting it, and must not be modified
+// by the execution.
+func New(name string, state vm.StateDB) (Tracer, error) {
+	switch name {
+	case "callTracer":
+		return NewCallTracer(), nil
+	case "prestateTracer":
+		return NewPrestateTracer(state), nil
+	}
+	return nil, fmt.Errorf("unknown tracer %q", name)
+}
+
+// isPrecompiled returns whether the address is a precompiled contract at the
+// block of the EVM.
+func isPrecompiled(env *vm.EVM, address common.Address) bool {
+	precompiles := vm.PrecompiledContractsHomestead
+	if env.ChainConfig().IsByzantium(env.BlockNumber) {
+		precompiles = vm.PrecompiledContractsByzantium
+	}
+	return precompiles[address] != nil
+}
@@ -0,0 +1,135 @@
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/core/vm/runtime"
+)
+
+var (
+	origin  = common.HexToAddress("0xaa")
+	caller  = common.HexToAddress("0xbb")
+	storer  = common.HexToAddress("0xcc")
+	reverts = common.HexToAddress("0xdd")
+
+	// callerCode calls the account at 0xcc with all its gas and returns the first
+	// 32 bytes of the output.
+	callerCode = common.FromHex("60206000600060006000" + "60cc5af150" + "60206000f3")
+	// storerCode stores 1 in slot 0 and returns 7.
+	storerCode = common.FromHex("6001600055600760005260206000f3")
+	// revertCode reverts without output.
+	revertCode = common.FromHex("60006000fd")
+)
+
+func newTestState() *state.DB {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	statedb.AddBalance(origin, big.NewInt(1000))
+	statedb.SetCode(caller, callerCode)
+	statedb.SetCode(storer, storerCode)
+	statedb.SetState(storer, common.Hash{}, common.BigToHash(big.NewInt(5)))
+	statedb.SetCode(reverts, revertCode)
+	statedb.Finalise(true)
+	return statedb
+}
+
+func trace(t *testing.T, statedb *state.DB, tracer vm.Tracer, to common.Address) []byte {
+	ret, _, err := runtime.Call(to, nil, &runtime.Config{
+		ChainConfig: params.TestChainConfig,
+		Origin:      origin,
+		GasLimit:    1000000,
+		State:       statedb,
+		EVMConfig:   vm.Config{Debug: true, Tracer: tracer},
+	})
+	if err != nil {
+		t.Fatalf("Failed to execute call: %v", err)
+	}
+	return ret
+}
+
+func TestCallTracer(t *testing.T) {
+	tracer := NewCallTracer()
+	ret := trace(t, newTestState(), tracer, caller)
+	if new(big.Int).SetBytes(ret).Int64() != 7 {
+		t.Fatalf("Unexpected return value %x", ret)
+	}
+	result, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("Failed to get result: %v", err)
+	}
+	root := result.(*CallFrame)
+	if root.Type != "CALL" || root.From != origin || root.To != caller || root.Error != "" || !bytes.Equal(root.Output, ret) {
+		t.Errorf("Unexpected top-level call: %+v", root)
+	}
+	if len(root.Calls) != 1 {
+		t.Fatalf("Expected 1 nested call, got %d", len(root.Calls))
+	}
+	call := root.Calls[0]
+	if call.Type != "CALL" || call.From != caller || call.To != storer || call.Error != "" || !bytes.Equal(call.Output, ret) {
+		t.Errorf("Unexpected nested call: %+v", call)
+	}
+	if call.Gas == 0 || call.GasUsed == 0 || call.GasUsed >= call.Gas {
+		t.Errorf("Unexpected gas %d used of %d", call.GasUsed, call.Gas)
+	}
+	if _, err := json.Marshal(root); err != nil {
+		t.Errorf("Failed to marshal result: %v", err)
+	}
+}
+
+func TestCallTracerRevert(t *testing.T) {
+	statedb := newTestState()
+	// Make the caller call the reverting account instead.
+	statedb.SetCode(caller, bytes.Replace(callerCode, []byte{0x60, 0xcc}, []byte{0x60, 0xdd}, 1))
+	tracer := NewCallTracer()
+	trace(t, statedb, tracer, caller)
+	result, _ := tracer.GetResult()
+	root := result.(*CallFrame)
+	if root.Error != "" || len(root.Calls) != 1 {
+		t.Fatalf("Unexpected top-level call: %+v", root)
+	}
+	if call := root.Calls[0]; call.To != reverts || call.Error != "execution reverted" || len(call.Output) != 0 {
+		t.Errorf("Unexpected nested call: %+v", call)
+	}
+}
+
+func TestPrestateTracer(t *testing.T) {
+	statedb := newTestState()
+	tracer := NewPrestateTracer(statedb.Copy())
+	trace(t, statedb, tracer, caller)
+	if statedb.GetState(storer, common.Hash{}) != common.BigToHash(big.NewInt(1)) {
+		t.Fatalf("Call did not store the value")
+	}
+	result, _ := tracer.GetResult()
+	prestate := result.(map[common.Address]*PrestateAccount)
+	if len(prestate) != 3 {
+		t.Errorf("Expected 3 accounts, got %d", len(prestate))
+	}
+	if account := prestate[origin]; account == nil || account.Balance.ToInt().Int64() != 1000 {
+		t.Errorf("Unexpected origin prestate: %+v", account)
+	}
+	if account := prestate[caller]; account == nil || !bytes.Equal(account.Code, callerCode) {
+		t.Errorf("Unexpected caller prestate: %+v", account)
+	}
+	account := prestate[storer]
+	if account == nil || account.Storage[common.Hash{}] != common.BigToHash(big.NewInt(5)) {
+		t.Errorf("Unexpected storer prestate: %+v", account)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"callTracer", "prestateTracer"} {
+		if _, err := New(name, newTestState()); err != nil {
+			t.Errorf("Failed to create %s: %v", name, err)
+		}
+	}
+	if _, err := New("unknown", newTestState()); err == nil {
+		t.Errorf("Unknown tracer should not be created")
+	}
+}
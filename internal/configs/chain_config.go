@@ -0,0 +1,219 @@
+// Package configs contains the Harmony specific chain configuration shared by
+// core, node and consensus.
+package configs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Errors returned by ChainConfig.Validate.
+var (
+	ErrInvalidEpochLength   = errors.New("blocks per epoch must be positive")
+	ErrInvalidNumShards     = errors.New("number of shards must be positive")
+	ErrInvalidCommitteeSize = errors.New("invalid committee size bounds")
+	ErrInvalidKickoutRate   = errors.New("invalid kick-out rate bounds")
+	ErrInvalidGasLimits     = errors.New("gas floor must not be greater than gas ceil")
+	ErrInvalidUnbonding     = errors.New("unbonding epochs must be positive")
+)
+
+// ChainConfig is the Harmony specific part of the chain configuration. It is
+// written to the database together with the genesis block so that different
+// networks can run with different parameters without recompiling.
+type ChainConfig struct {
+	// BlocksPerEpoch is the number of blocks in one epoch. The first block of
+	// every epoch carries the shard state and randomness of that epoch.
+	BlocksPerEpoch uint64 `json:"blocksPerEpoch"`
+
+	// NumShards is the total number of shards, including the beacon chain.
+	NumShards uint32 `json:"numShards"`
+
+	// MinCommitteeSize and MaxCommitteeSize bound the number of nodes in a
+	// committee after resharding.
+	MinCommitteeSize int `json:"minCommitteeSize"`
+	MaxCommitteeSize int `json:"maxCommitteeSize"`
+
+	// MinKickoutRate and MaxKickoutRate bound the fraction of active committee
+	// members moved by the cuckoo rule on each resharding.
+	MinKickoutRate float64 `json:"minKickoutRate"`
+	MaxKickoutRate float64 `json:"maxKickoutRate"`
+
+	// GenesisGasLimit is the gas limit of the genesis block. GasFloor and
+	// GasCeil are the targets the block gas limit moves towards.
+	GenesisGasLimit uint64 `json:"genesisGasLimit"`
+	GasFloor        uint64 `json:"gasFloor"`
+	GasCeil         uint64 `json:"gasCeil"`
+
+	// BlockReward is paid to the block proposer in the first reward era. The
+	// reward is halved every RewardHalvingEpochs epochs; zero disables halving.
+	BlockReward         *big.Int `json:"blockReward"`
+	RewardHalvingEpochs uint64   `json:"rewardHalvingEpochs"`
+
+	// UnbondingEpochs is the number of epochs a withdrawn stake stays locked
+	// before it is returned to its owner at an epoch block. It is at least one,
+	// so that a stake is never released in the epoch it was withdrawn in.
+	UnbondingEpochs uint64 `json:"unbondingEpochs"`
+
+	// ReceiptLogsBlock is the first block whose receipts keep the logs of their
+	// transaction, which changes the receipts root. Nil never keeps them, so the
+	// chains created before the logs were kept stay valid.
+	ReceiptLogsBlock *big.Int `json:"receiptLogsBlock"`
+
+	// BLSPrecompilesBlock is the first block where the BLS precompiled contracts
+	// can be called. Nil never enables them.
+	BLSPrecompilesBlock *big.Int `json:"blsPrecompilesBlock"`
+
+	// BeaconGenesisHash is the hash of the genesis block of the beacon chain,
+	// which the beacon headers followed by the nodes of the other shards must
+	// descend from. Zero on a test network uses the beacon test genesis.
+	BeaconGenesisHash common.Hash `json:"beaconGenesisHash"`
+}
+
+// DefaultChainConfig is the configuration used by test networks when no other
+// configuration is stored in the database. Forks changing the validity of the
+// existing chains are left off, new genesis files set their blocks explicitly.
+var DefaultChainConfig = &ChainConfig{
+	BlocksPerEpoch:      5,
+	NumShards:           6,
+	MinCommitteeSize:    10,
+	MaxCommitteeSize:    100,
+	MinKickoutRate:      0,
+	MaxKickoutRate:      0.5,
+	GenesisGasLimit:     10000000000,
+	GasFloor:            500000000000000000,
+	GasCeil:             1000000000000000000,
+	BlockReward:         big.NewInt(0),
+	RewardHalvingEpochs: 0,
+	UnbondingEpochs:     2,
+}
+
+// LoadChainConfig reads a JSON encoded chain configuration from the given file.
+// Fields missing from the file keep their default values.
+func LoadChainConfig(path string) (*ChainConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := *DefaultChainConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks the configuration for values that would break the chain.
+func (c *ChainConfig) Validate() error {
+	switch {
+	case c.BlocksPerEpoch == 0:
+		return ErrInvalidEpochLength
+	case c.NumShards == 0:
+		return ErrInvalidNumShards
+	case c.MinCommitteeSize <= 0 || c.MinCommitteeSize > c.MaxCommitteeSize:
+		return ErrInvalidCommitteeSize
+	case c.MinKickoutRate < 0 || c.MaxKickoutRate > 1 || c.MinKickoutRate > c.MaxKickoutRate:
+		return ErrInvalidKickoutRate
+	case c.GasFloor > c.GasCeil:
+		return ErrInvalidGasLimits
+	case c.UnbondingEpochs == 0:
+		return ErrInvalidUnbonding
+	}
+	return nil
+}
+
+// String implements the fmt.Stringer interface.
+func (c *ChainConfig) String() string {
+	return fmt.Sprintf("{BlocksPerEpoch: %v NumShards: %v Committee: [%v, %v] Kickout: [%v, %v] Gas: [%v, %v] BlockReward: %v HalvingEpochs: %v UnbondingEpochs: %v ReceiptLogsBlock: %v BLSPrecompilesBlock: %v}",
+		c.BlocksPerEpoch, c.NumShards, c.MinCommitteeSize, c.MaxCommitteeSize,
+		c.MinKickoutRate, c.MaxKickoutRate, c.GasFloor, c.GasCeil, c.BlockReward, c.RewardHalvingEpochs, c.UnbondingEpochs, c.ReceiptLogsBlock, c.BLSPrecompilesBlock)
+}
+
+// EpochFirstBlock returns the number of the first block of the given epoch.
+// This block stores the sharding information of the epoch.
+func (c *ChainConfig) EpochFirstBlock(epoch uint64) uint64 {
+	return epoch * c.BlocksPerEpoch
+}
+
+// EpochOfBlock returns the epoch the given block number belongs to.
+func (c *ChainConfig) EpochOfBlock(blockNumber uint64) uint64 {
+	return blockNumber / c.BlocksPerEpoch
+}
+
+// IsEpochBlock checks whether the given block number is the one that stores
+// epoch information.
+func (c *ChainConfig) IsEpochBlock(blockNumber uint64) bool {
+	return blockNumber%c.BlocksPerEpoch == 0
+}
+
+// PreviousEpochBlock returns the epoch block number of the previous epoch. The
+// genesis block is returned for the blocks of the first two epochs.
+func (c *ChainConfig) PreviousEpochBlock(blockNumber uint64) uint64 {
+	epoch := c.EpochOfBlock(blockNumber)
+	if epoch <= 1 {
+		// no previous epoch
+		return 0
+	}
+	return c.EpochFirstBlock(epoch - 1)
+}
+
+// ClampKickoutRate limits the given kick-out rate to the configured bounds.
+func (c *ChainConfig) ClampKickoutRate(rate float64) float64 {
+	if rate < c.MinKickoutRate {
+		return c.MinKickoutRate
+	}
+	if rate > c.MaxKickoutRate {
+		return c.MaxKickoutRate
+	}
+	return rate
+}
+
+// BlockRewardAt returns the proposer reward for a block of the given number.
+func (c *ChainConfig) BlockRewardAt(blockNumber uint64) *big.Int {
+	reward := new(big.Int)
+	if c.BlockReward == nil {
+		return reward
+	}
+	reward.Set(c.BlockReward)
+	if c.RewardHalvingEpochs == 0 {
+		return reward
+	}
+	halvings := c.EpochOfBlock(blockNumber) / c.RewardHalvingEpochs
+	if halvings >= uint64(reward.BitLen()) {
+		return reward.SetUint64(0)
+	}
+	return reward.Rsh(reward, uint(halvings))
+}
+
+// UnbondingReleaseBlock returns the number of the epoch block at which a stake
+// withdrawn in the given block is released.
+func (c *ChainConfig) UnbondingReleaseBlock(withdrawBlock uint64) uint64 {
+	return c.EpochFirstBlock(c.EpochOfBlock(withdrawBlock) + c.UnbondingEpochs)
+}
+
+// IsReceiptLogs returns whether the receipts of the given block keep the logs of
+// their transaction.
+func (c *ChainConfig) IsReceiptLogs(blockNumber *big.Int) bool {
+	return c.ReceiptLogsBlock != nil && blockNumber != nil && c.ReceiptLogsBlock.Cmp(blockNumber) <= 0
+}
+
+// IsBLSPrecompiles returns whether the BLS precompiled contracts can be called in
+// the given block.
+func (c *ChainConfig) IsBLSPrecompiles(blockNumber *big.Int) bool {
+	return c.BLSPrecompilesBlock != nil && blockNumber != nil && c.BLSPrecompilesBlock.Cmp(blockNumber) <= 0
+}
+
+// EVMConfig returns the go-ethereum chain configuration used by the EVM for the
+// given shard. The shard ID is piggybacked as the chain ID.
+func (c *ChainConfig) EVMConfig(shardID uint32) *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.ChainID = big.NewInt(int64(shardID))
+	return &config
+}
@@ -0,0 +1,126 @@
+package configs
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEpochCalculation(t *testing.T) {
+	config := &ChainConfig{BlocksPerEpoch: 10}
+	if config.EpochFirstBlock(3) != 30 {
+		t.Errorf("wrong first block of epoch 3: %v", config.EpochFirstBlock(3))
+	}
+	if config.EpochOfBlock(29) != 2 || config.EpochOfBlock(30) != 3 {
+		t.Errorf("wrong epoch of block")
+	}
+	if !config.IsEpochBlock(40) || config.IsEpochBlock(41) {
+		t.Errorf("wrong epoch block check")
+	}
+	if config.PreviousEpochBlock(45) != 30 {
+		t.Errorf("wrong previous epoch block: %v", config.PreviousEpochBlock(45))
+	}
+	if config.PreviousEpochBlock(15) != 0 {
+		t.Errorf("wrong previous epoch block of the first epoch: %v", config.PreviousEpochBlock(15))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := DefaultChainConfig.Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+	config := *DefaultChainConfig
+	config.BlocksPerEpoch = 0
+	if err := config.Validate(); err != ErrInvalidEpochLength {
+		t.Errorf("expected %v, got %v", ErrInvalidEpochLength, err)
+	}
+	config = *DefaultChainConfig
+	config.MinCommitteeSize = config.MaxCommitteeSize + 1
+	if err := config.Validate(); err != ErrInvalidCommitteeSize {
+		t.Errorf("expected %v, got %v", ErrInvalidCommitteeSize, err)
+	}
+	config = *DefaultChainConfig
+	config.MaxKickoutRate = 1.5
+	if err := config.Validate(); err != ErrInvalidKickoutRate {
+		t.Errorf("expected %v, got %v", ErrInvalidKickoutRate, err)
+	}
+	config = *DefaultChainConfig
+	config.UnbondingEpochs = 0
+	if err := config.Validate(); err != ErrInvalidUnbonding {
+		t.Errorf("expected %v, got %v", ErrInvalidUnbonding, err)
+	}
+}
+
+func TestBlockRewardAt(t *testing.T) {
+	config := &ChainConfig{BlocksPerEpoch: 10, BlockReward: big.NewInt(100), RewardHalvingEpochs: 2}
+	tests := []struct {
+		number uint64
+		reward int64
+	}{
+		{0, 100},
+		{19, 100},
+		{20, 50},
+		{45, 25},
+		{10000, 0},
+	}
+	for _, test := range tests {
+		if reward := config.BlockRewardAt(test.number); reward.Int64() != test.reward {
+			t.Errorf("block %v: expected reward %v, got %v", test.number, test.reward, reward)
+		}
+	}
+}
+
+func TestUnbondingReleaseBlock(t *testing.T) {
+	config := &ChainConfig{BlocksPerEpoch: 10, UnbondingEpochs: 2}
+	tests := []struct {
+		withdrawBlock uint64
+		releaseBlock  uint64
+	}{
+		{0, 20},
+		{9, 20},
+		{10, 30},
+		{25, 40},
+	}
+	for _, test := range tests {
+		if block := config.UnbondingReleaseBlock(test.withdrawBlock); block != test.releaseBlock {
+			t.Errorf("block %v: expected release block %v, got %v", test.withdrawBlock, test.releaseBlock, block)
+		}
+	}
+}
+
+func TestEVMConfig(t *testing.T) {
+	config := DefaultChainConfig.EVMConfig(3)
+	if config.ChainID.Int64() != 3 {
+		t.Errorf("expected chain ID 3, got %v", config.ChainID)
+	}
+	if other := DefaultChainConfig.EVMConfig(0); other.ChainID.Int64() != 0 || config.ChainID.Int64() != 3 {
+		t.Errorf("EVM configs should not share the chain ID")
+	}
+}
+
+func TestIsReceiptLogs(t *testing.T) {
+	if DefaultChainConfig.IsReceiptLogs(big.NewInt(100)) {
+		t.Errorf("receipt logs kept by default")
+	}
+	config := &ChainConfig{}
+	if config.IsReceiptLogs(big.NewInt(100)) {
+		t.Errorf("receipt logs kept without activation block")
+	}
+	config.ReceiptLogsBlock = big.NewInt(10)
+	if config.IsReceiptLogs(big.NewInt(9)) || !config.IsReceiptLogs(big.NewInt(10)) || !config.IsReceiptLogs(big.NewInt(11)) {
+		t.Errorf("wrong receipt logs activation")
+	}
+}
+
+func TestIsBLSPrecompiles(t *testing.T) {
+	if DefaultChainConfig.IsBLSPrecompiles(big.NewInt(100)) {
+		t.Errorf("BLS precompiles enabled by default")
+	}
+	config := &ChainConfig{}
+	if config.IsBLSPrecompiles(big.NewInt(100)) {
+		t.Errorf("BLS precompiles enabled without activation block")
+	}
+	config.BLSPrecompilesBlock = big.NewInt(10)
+	if config.IsBLSPrecompiles(big.NewInt(9)) || !config.IsBLSPrecompiles(big.NewInt(10)) {
+		t.Errorf("wrong BLS precompiles activation")
+	}
+}
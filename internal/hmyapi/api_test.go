@@ -0,0 +1,228 @@
+package hmyapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/core/vm/tracers"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testFunds   = big.NewInt(1000000000000000)
+	testSpec    = core.Genesis{
+		Config:   params.TestChainConfig,
+		GasLimit: 10000000,
+		Alloc:    core.GenesisAlloc{testAddress: {Balance: testFunds}},
+	}
+	testSigner = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+)
+
+// newTestBackend creates a chain with one transfer in each of n blocks, and a
+// transaction pool on top of it.
+func newTestBackend(t *testing.T, n int) (*core.BlockChain, *core.TxPool, []*types.Block) {
+	db := ethdb.NewMemDatabase()
+	testSpec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, consensus.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	genDB := ethdb.NewMemDatabase()
+	genesis := testSpec.MustCommit(genDB)
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, n, func(i int, gen *core.BlockGen) {
+		gen.AddTx(signTx(t, uint64(i), chain.ShardID()))
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	return chain, core.NewTxPool(txPoolConfig, params.TestChainConfig, chain), blocks
+}
+
+func signTx(t *testing.T, nonce uint64, shardID uint32) *types.Transaction {
+	tx := types.NewTransaction(nonce, common.Address{1}, shardID, big.NewInt(1000), params.TxGas, big.NewInt(1), nil)
+	signedTx, err := types.SignTx(tx, testSigner, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedTx
+}
+
+func TestBlockChainAPI(t *testing.T) {
+	chain, txPool, blocks := newTestBackend(t, 3)
+	defer chain.Stop()
+	defer txPool.Stop()
+	api := NewPublicBlockChainAPI(chain)
+	ctx := context.Background()
+
+	if number := api.BlockNumber(); uint64(number) != 3 {
+		t.Errorf("expected block number 3, got %d", number)
+	}
+	if chainID := api.ChainId(); uint32(chainID) != chain.ShardID() {
+		t.Errorf("expected chain ID %d, got %d", chain.ShardID(), chainID)
+	}
+	balance, err := api.GetBalance(ctx, common.Address{1}, rpc.LatestBlockNumber)
+	if err != nil || balance.ToInt().Int64() != 3000 {
+		t.Errorf("expected latest balance 3000, got %v (%v)", balance, err)
+	}
+	balance, err = api.GetBalance(ctx, common.Address{1}, rpc.BlockNumber(1))
+	if err != nil || balance.ToInt().Int64() != 1000 {
+		t.Errorf("expected balance 1000 at block 1, got %v (%v)", balance, err)
+	}
+	if _, err := api.GetBalance(ctx, common.Address{1}, rpc.BlockNumber(10)); err == nil {
+		t.Errorf("expected error for unknown block")
+	}
+
+	block, err := api.GetBlockByNumber(ctx, rpc.BlockNumber(2), false)
+	if err != nil || block["hash"] != blocks[1].Hash() {
+		t.Fatalf("unexpected block 2: %v (%v)", block, err)
+	}
+	if txs := block["transactions"].([]interface{}); len(txs) != 1 || txs[0] != blocks[1].Transactions()[0].Hash() {
+		t.Errorf("unexpected transactions %v", txs)
+	}
+	block, err = api.GetBlockByHash(ctx, blocks[2].Hash(), true)
+	if err != nil || block["number"].(*hexutil.Big).ToInt().Int64() != 3 {
+		t.Fatalf("unexpected block 3: %v (%v)", block, err)
+	}
+	if txs := block["transactions"].([]interface{}); txs[0].(*RPCTransaction).From != testAddress {
+		t.Errorf("unexpected full transactions %v", txs)
+	}
+	if block, _ := api.GetBlockByHash(ctx, common.Hash{1}, false); block != nil {
+		t.Errorf("expected no block for unknown hash")
+	}
+
+	gas, err := api.EstimateGas(ctx, CallArgs{From: &testAddress, To: &common.Address{1}})
+	if err != nil || uint64(gas) != params.TxGas {
+		t.Errorf("expected transfer gas %d, got %d (%v)", params.TxGas, gas, err)
+	}
+	if _, err := api.Call(ctx, CallArgs{From: &testAddress, To: &common.Address{1}}, rpc.LatestBlockNumber); err != nil {
+		t.Errorf("failed to call: %v", err)
+	}
+}
+
+func TestTransactionPoolAPI(t *testing.T) {
+	chain, txPool, blocks := newTestBackend(t, 2)
+	defer chain.Stop()
+	defer txPool.Stop()
+	api := NewPublicTransactionPoolAPI(chain, txPool)
+	ctx := context.Background()
+
+	included := blocks[1].Transactions()[0]
+	tx := api.GetTransactionByHash(ctx, included.Hash())
+	if tx == nil || tx.BlockHash != blocks[1].Hash() || tx.From != testAddress {
+		t.Fatalf("unexpected transaction %v", tx)
+	}
+	receipt, err := api.GetTransactionReceipt(ctx, included.Hash())
+	if err != nil || receipt["status"] != hexutil.Uint(types.ReceiptStatusSuccessful) || receipt["gasUsed"] != hexutil.Uint64(params.TxGas) {
+		t.Errorf("unexpected receipt %v (%v)", receipt, err)
+	}
+
+	pending := signTx(t, 2, chain.ShardID())
+	encoded, _ := rlp.EncodeToBytes(pending)
+	hash, err := api.SendRawTransaction(ctx, encoded)
+	if err != nil || hash != pending.Hash() {
+		t.Fatalf("failed to send raw transaction: %v", err)
+	}
+	if tx := api.GetTransactionByHash(ctx, hash); tx == nil || tx.BlockNumber != nil {
+		t.Errorf("expected pending transaction, got %v", tx)
+	}
+	if receipt, _ := api.GetTransactionReceipt(ctx, hash); receipt != nil {
+		t.Errorf("expected no receipt for pending transaction")
+	}
+	if _, err := api.SendRawTransaction(ctx, encoded); err == nil {
+		t.Errorf("expected error for known transaction")
+	}
+
+	latest, _ := api.GetTransactionCount(ctx, testAddress, rpc.LatestBlockNumber)
+	pendingCount, _ := api.GetTransactionCount(ctx, testAddress, rpc.PendingBlockNumber)
+	if uint64(*latest) != 2 || uint64(*pendingCount) != 3 {
+		t.Errorf("expected nonces 2 and 3, got %d and %d", *latest, *pendingCount)
+	}
+}
+
+func TestPrivateDebugAPI(t *testing.T) {
+	chain, txPool, blocks := newTestBackend(t, 3)
+	defer chain.Stop()
+	defer txPool.Stop()
+	api := NewPrivateDebugAPI(chain)
+	ctx := context.Background()
+	tx := blocks[1].Transactions()[0]
+
+	result, err := api.TraceTransaction(ctx, tx.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	if execution := result.(*ExecutionResult); execution.Gas != params.TxGas || execution.Failed || len(execution.StructLogs) != 0 {
+		t.Errorf("unexpected execution result: %+v", execution)
+	}
+
+	callTracer := "callTracer"
+	result, err = api.TraceTransaction(ctx, tx.Hash(), &TraceConfig{Tracer: &callTracer})
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	if call := result.(*tracers.CallFrame); call.Type != "CALL" || call.From != testAddress || call.To != (common.Address{1}) || call.Value.ToInt().Int64() != 1000 {
+		t.Errorf("unexpected call: %+v", call)
+	}
+
+	prestateTracer := "prestateTracer"
+	result, err = api.TraceTransaction(ctx, tx.Hash(), &TraceConfig{Tracer: &prestateTracer})
+	if err != nil {
+		t.Fatalf("failed to trace transaction: %v", err)
+	}
+	prestate := result.(map[common.Address]*tracers.PrestateAccount)
+	sender := prestate[testAddress]
+	if sender == nil || sender.Nonce != 1 || sender.Balance.ToInt().Int64() != testFunds.Int64()-1000-int64(params.TxGas) {
+		t.Errorf("unexpected sender prestate: %+v", sender)
+	}
+	if recipient := prestate[common.Address{1}]; recipient == nil || recipient.Balance.ToInt().Int64() != 1000 {
+		t.Errorf("unexpected recipient prestate: %+v", recipient)
+	}
+
+	unknownTracer := "unknownTracer"
+	if _, err := api.TraceTransaction(ctx, tx.Hash(), &TraceConfig{Tracer: &unknownTracer}); err == nil {
+		t.Errorf("expected an error for an unknown tracer")
+	}
+	if _, err := api.TraceTransaction(ctx, common.Hash{1}, nil); err == nil {
+		t.Errorf("expected an error for an unknown transaction")
+	}
+
+	results, err := api.TraceBlockByNumber(ctx, 2, &TraceConfig{Tracer: &callTracer})
+	if err != nil || len(results) != 1 || results[0].Error != "" {
+		t.Fatalf("unexpected block trace: %v (%v)", results, err)
+	}
+	if results, err := api.TraceBlockByHash(ctx, blocks[1].Hash(), nil); err != nil || len(results) != 1 {
+		t.Errorf("unexpected block trace: %v (%v)", results, err)
+	}
+	if _, err := api.TraceBlockByNumber(ctx, 0, nil); err == nil {
+		t.Errorf("expected an error for the genesis block")
+	}
+}
+
+func TestTraceReexec(t *testing.T) {
+	small, large := uint64(10), maxTraceReexec+1
+	if reexec := traceReexec(nil); reexec != defaultTraceReexec {
+		t.Errorf("expected %d, got %d", defaultTraceReexec, reexec)
+	}
+	if reexec := traceReexec(&TraceConfig{Reexec: &small}); reexec != small {
+		t.Errorf("expected %d, got %d", small, reexec)
+	}
+	if reexec := traceReexec(&TraceConfig{Reexec: &large}); reexec != maxTraceReexec {
+		t.Errorf("expected %d, got %d", maxTraceReexec, reexec)
+	}
+}
@@ -0,0 +1,54 @@
+package hmyapi
+
+import (
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/api/service/syncing"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/hmyapi/filters"
+)
+
+// filterBackend serves the log filters and subscriptions from the chain and the
+// transaction pool.
+type filterBackend struct {
+	*core.BlockChain
+	txPool *core.TxPool
+}
+
+// SubscribeNewTxsEvent subscribes to the transactions entering the pool.
+func (b *filterBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txPool.SubscribeNewTxsEvent(ch)
+}
+
+// GetAPIs returns the JSON-RPC APIs served from the given chain, transaction
+// pool and sync status, in the eth namespace so that web3 tools can use them,
+// and the tracing APIs in the debug namespace.
+func GetAPIs(chain *core.BlockChain, txPool *core.TxPool, syncStatus func() syncing.Status) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicBlockChainAPI(chain),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicTransactionPoolAPI(chain, txPool),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   filters.NewPublicFilterAPI(&filterBackend{chain, txPool}),
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicSyncingAPI(syncStatus),
+			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(chain),
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package hmyapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+var errBlockNotFound = errors.New("block not found")
+
+// CallArgs represents the arguments for a call.
+type CallArgs struct {
+	From     *common.Address `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     *hexutil.Bytes  `json:"data"`
+}
+
+// toMessage converts the call arguments to a message with the given default gas.
+func (args *CallArgs) toMessage(gas uint64) types.Message {
+	var from common.Address
+	if args.From != nil {
+		from = *args.From
+	}
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	gasPrice := new(big.Int)
+	if args.GasPrice != nil {
+		gasPrice = args.GasPrice.ToInt()
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	var data []byte
+	if args.Data != nil {
+		data = []byte(*args.Data)
+	}
+	return types.NewMessage(from, args.To, 0, value, gas, gasPrice, data, false)
+}
+
+// PublicBlockChainAPI provides an API to access the blocks and the state of the
+// shard chain.
+type PublicBlockChainAPI struct {
+	chain *core.BlockChain
+}
+
+// NewPublicBlockChainAPI creates a new blockchain API.
+func NewPublicBlockChainAPI(chain *core.BlockChain) *PublicBlockChainAPI {
+	return &PublicBlockChainAPI{chain}
+}
+
+// ChainId returns the chain ID, which is the shard ID of the node.
+func (s *PublicBlockChainAPI) ChainId() hexutil.Uint64 {
+	return hexutil.Uint64(s.chain.ShardID())
+}
+
+// BlockNumber returns the block number of the chain head.
+func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.chain.CurrentBlock().NumberU64())
+}
+
+// GetBalance returns the amount of wei for the given address in the state of the
+// given block number.
+func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	statedb, _, err := s.stateAndHeaderByNumber(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(statedb.GetBalance(address)), nil
+}
+
+// GetCode returns the code stored at the given address in the state for the given
+// block number.
+func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	statedb, _, err := s.stateAndHeaderByNumber(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetCode(address), nil
+}
+
+// GetBlockByNumber returns the requested block. When fullTx is true all
+// transactions in the block are returned in full detail, otherwise only the
+// transaction hash is returned.
+func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	header := s.headerByNumber(blockNr)
+	if header == nil {
+		return nil, nil
+	}
+	return s.marshalBlock(s.chain.GetBlock(header.Hash(), header.Number.Uint64()), fullTx), nil
+}
+
+// GetBlockByHash returns the requested block. When fullTx is true all
+// transactions in the block are returned in full detail, otherwise only the
+// transaction hash is returned.
+func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	return s.marshalBlock(s.chain.GetBlockByHash(blockHash), fullTx), nil
+}
+
+// Call executes the given message call on the state of the given block number
+// without creating a transaction, and returns the output data. The gas of the
+// call is capped at core.DefaultCallGasCap.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	statedb, header, err := s.stateAndHeaderByNumber(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	result, _, _, err := core.DoCall(s.chain, s.chain.Config(), statedb, header, args.toMessage(0), core.DefaultCallGasCap)
+	return result, err
+}
+
+// EstimateGas returns the lowest gas limit with which the given message call
+// succeeds on the state of the latest block. The estimation is bounded by the gas
+// given in the arguments, the block gas limit and core.DefaultCallGasCap.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+	statedb, header, err := s.stateAndHeaderByNumber(rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	gas, err := core.EstimateGas(s.chain, s.chain.Config(), statedb, header, args.toMessage(0), core.DefaultCallGasCap)
+	return hexutil.Uint64(gas), err
+}
+
+// headerByNumber returns the header of the given block number, where the latest
+// and pending block numbers both mean the chain head.
+func (s *PublicBlockChainAPI) headerByNumber(blockNr rpc.BlockNumber) *types.Header {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return s.chain.CurrentBlock().Header()
+	}
+	return s.chain.GetHeaderByNumber(uint64(blockNr))
+}
+
+// stateAndHeaderByNumber returns the state and the header of the given block number.
+func (s *PublicBlockChainAPI) stateAndHeaderByNumber(blockNr rpc.BlockNumber) (*state.DB, *types.Header, error) {
+	header := s.headerByNumber(blockNr)
+	if header == nil {
+		return nil, nil, errBlockNotFound
+	}
+	statedb, err := s.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (s *PublicBlockChainAPI) marshalBlock(block *types.Block, fullTx bool) map[string]interface{} {
+	if block == nil {
+		return nil
+	}
+	return rpcMarshalBlock(block, types.MakeSigner(s.chain.Config(), block.Number()), fullTx)
+}
@@ -0,0 +1,255 @@
+package filters
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/bloombits"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// Backend is the chain and transaction pool the filters are served from.
+type Backend interface {
+	CurrentHeader() *types.Header
+	GetHeaderByNumber(number uint64) *types.Header
+	GetHeaderByHash(hash common.Hash) *types.Header
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	BloomIndexer() *core.BloomIndexer
+
+	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
+	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+}
+
+// Filter can be used to retrieve and filter logs.
+type Filter struct {
+	backend Backend
+
+	addresses []common.Address
+	topics    [][]common.Hash
+
+	block      common.Hash // Block hash if filtering a single block
+	begin, end int64       // Range interval if filtering multiple blocks
+
+	matcher *bloombits.Matcher
+}
+
+// NewRangeFilter creates a new filter which uses the bloom index to figure out
+// whether a particular block is interesting or not. A negative block number
+// means the chain head.
+func NewRangeFilter(backend Backend, begin, end int64, addresses []common.Address, topics [][]common.Hash) *Filter {
+	// Flatten the address and topic filter clauses into a single bloombits filter
+	// system. Since the bloombits are not positional, nil topics are permitted,
+	// which get flattened into a nil byte slice.
+	var filters [][][]byte
+	if len(addresses) > 0 {
+		filter := make([][]byte, len(addresses))
+		for i, address := range addresses {
+			filter[i] = address.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+	for _, topicList := range topics {
+		filter := make([][]byte, len(topicList))
+		for i, topic := range topicList {
+			filter[i] = topic.Bytes()
+		}
+		filters = append(filters, filter)
+	}
+
+	filter := newFilter(backend, addresses, topics)
+	filter.matcher = bloombits.NewMatcher(filters)
+	filter.begin = begin
+	filter.end = end
+	return filter
+}
+
+// NewBlockFilter creates a new filter which directly inspects the contents of
+// a block to figure out whether it is interesting or not.
+func NewBlockFilter(backend Backend, block common.Hash, addresses []common.Address, topics [][]common.Hash) *Filter {
+	filter := newFilter(backend, addresses, topics)
+	filter.block = block
+	return filter
+}
+
+// newFilter creates a generic filter that can either filter based on a block hash,
+// or based on range queries. The search criteria needs to be explicitly set.
+func newFilter(backend Backend, addresses []common.Address, topics [][]common.Hash) *Filter {
+	return &Filter{
+		backend:   backend,
+		addresses: addresses,
+		topics:    topics,
+	}
+}
+
+// Logs searches the blockchain for matching log entries, returning all from the
+// first block that contains matches, updating the start of the filter accordingly.
+func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	// If we're doing singleton block filtering, execute and return
+	if f.block != (common.Hash{}) {
+		header := f.backend.GetHeaderByHash(f.block)
+		if header == nil {
+			return nil, errBlockNotFound
+		}
+		return f.blockLogs(header), nil
+	}
+	// Figure out the limits of the filter range
+	head := f.backend.CurrentHeader().Number.Uint64()
+	begin, end := uint64(f.begin), uint64(f.end)
+	if f.begin < 0 {
+		begin = head
+	}
+	if f.end < 0 || end > head {
+		end = head
+	}
+	var logs []*types.Log
+	if begin > end {
+		return logs, nil
+	}
+	if end-begin >= maxLogsBlockRange {
+		return nil, errRangeTooLarge
+	}
+
+	// Gather all indexed logs, and finish with non indexed ones
+	indexer := f.backend.BloomIndexer()
+	size, sections := indexer.SectionSize(), indexer.Sections()
+	for section := begin / size; section < sections && begin <= end; section++ {
+		last := (section+1)*size - 1
+		if last > end {
+			last = end
+		}
+		numbers, err := indexer.Match(section, f.matcher)
+		if err != nil {
+			// The section is not indexed for this chain, check its headers.
+			utils.GetLogInstance().Debug("Bloom bits section unavailable", "section", section, "err", err)
+			found, err := f.unindexedLogs(ctx, begin, last)
+			if err != nil {
+				return logs, err
+			}
+			logs = append(logs, found...)
+		} else {
+			for _, number := range numbers {
+				if number < begin || number > last {
+					continue
+				}
+				if err := ctx.Err(); err != nil {
+					return logs, err
+				}
+				if header := f.backend.GetHeaderByNumber(number); header != nil {
+					logs = append(logs, f.blockLogs(header)...)
+				}
+			}
+		}
+		begin = last + 1
+	}
+	if begin <= end {
+		found, err := f.unindexedLogs(ctx, begin, end)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, found...)
+	}
+	return logs, nil
+}
+
+// unindexedLogs returns the logs matching the filter criteria in the given block
+// range by checking the bloom of each header.
+func (f *Filter) unindexedLogs(ctx context.Context, begin, end uint64) ([]*types.Log, error) {
+	var logs []*types.Log
+	for number := begin; number <= end; number++ {
+		if err := ctx.Err(); err != nil {
+			return logs, err
+		}
+		header := f.backend.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		logs = append(logs, f.blockLogs(header)...)
+	}
+	return logs, nil
+}
+
+// blockLogs returns the logs matching the filter criteria within a single block.
+func (f *Filter) blockLogs(header *types.Header) []*types.Log {
+	if !bloomFilter(header.Bloom, f.addresses, f.topics) {
+		return nil
+	}
+	var logs []*types.Log
+	for _, receipt := range f.backend.GetReceiptsByHash(header.Hash()) {
+		logs = append(logs, filterLogs(receipt.Logs, f.addresses, f.topics)...)
+	}
+	return logs
+}
+
+// bloomFilter returns whether the bloom may contain logs matching the addresses
+// and topics.
+func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		var included bool
+		for _, addr := range addresses {
+			if types.BloomLookup(bloom, addr) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, sub := range topics {
+		included := len(sub) == 0 // empty rule set == wildcard
+		for _, topic := range sub {
+			if types.BloomLookup(bloom, topic) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+	return true
+}
+
+// filterLogs returns the logs matching the addresses and topics.
+func filterLogs(logs []*types.Log, addresses []common.Address, topics [][]common.Hash) []*types.Log {
+	var ret []*types.Log
+Logs:
+	for _, log := range logs {
+		if len(addresses) > 0 && !includes(addresses, log.Address) {
+			continue
+		}
+		// If the to filtered topics is greater than the amount of topics in logs, skip.
+		if len(topics) > len(log.Topics) {
+			continue Logs
+		}
+		for i, sub := range topics {
+			match := len(sub) == 0 // empty rule set == wildcard
+			for _, topic := range sub {
+				if log.Topics[i] == topic {
+					match = true
+					break
+				}
+			}
+			if !match {
+				continue Logs
+			}
+		}
+		ret = append(ret, log)
+	}
+	return ret
+}
+
+func includes(addresses []common.Address, a common.Address) bool {
+	for _, addr := range addresses {
+		if addr == a {
+			return true
+		}
+	}
+	return false
+}
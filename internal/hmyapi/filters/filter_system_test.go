@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestEventSystem(t *testing.T) {
+	backend, _ := newTestBackend(t, 1)
+	defer backend.Stop()
+	defer backend.txPool.Stop()
+	events := NewEventSystem(backend)
+
+	headers := make(chan *types.Header)
+	headersSub := events.SubscribeNewHeads(headers)
+	defer headersSub.Unsubscribe()
+	logs := make(chan []*types.Log)
+	logsSub := events.SubscribeLogs(FilterCriteria{Topics: [][]common.Hash{{testTopic}}}, logs)
+	defer logsSub.Unsubscribe()
+	hashes := make(chan []common.Hash)
+	txsSub := events.SubscribePendingTxs(hashes)
+	defer txsSub.Unsubscribe()
+
+	tx, _ := types.SignTx(types.NewContractCreation(0, backend.ShardID(), big.NewInt(0), 100000, big.NewInt(1), testLogCode), testSigner, testKey)
+	if err := backend.txPool.AddRemote(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	select {
+	case pending := <-hashes:
+		if len(pending) != 1 || pending[0] != tx.Hash() {
+			t.Errorf("unexpected pending transactions %v", pending)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no pending transaction event")
+	}
+
+	// The chain is generated again on a separate database, the first block is
+	// the same as the one of the backend.
+	genDB := ethdb.NewMemDatabase()
+	genesis := testSpec.MustCommit(genDB)
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, 2, func(i int, gen *core.BlockGen) {
+		if i == 1 {
+			gen.AddTx(tx)
+		}
+	})
+	go backend.InsertChain(blocks[1:])
+
+	// The logs and the head are sent in any order.
+	var gotHead, gotLogs bool
+	for !gotHead || !gotLogs {
+		select {
+		case header := <-headers:
+			if header.Hash() != blocks[1].Hash() {
+				t.Errorf("expected head %x, got %x", blocks[1].Hash(), header.Hash())
+			}
+			gotHead = true
+		case matched := <-logs:
+			if len(matched) != 1 || matched[0].TxHash != tx.Hash() {
+				t.Errorf("unexpected logs %v", matched)
+			}
+			gotLogs = true
+		case <-time.After(time.Second):
+			t.Fatalf("missing events: head %v, logs %v", gotHead, gotLogs)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/internal/configs"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testSpec    = core.Genesis{
+		Config:        params.TestChainConfig,
+		HarmonyConfig: testHarmonyConfig(),
+		GasLimit:      10000000,
+		Alloc:         core.GenesisAlloc{testAddress: {Balance: big.NewInt(1000000000000000)}},
+	}
+	testSigner = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+
+	// testLogCode is the init code of a contract emitting a log with topic 1.
+	testLogCode = common.Hex2Bytes("6001600060006000a100")
+	testTopic   = common.BigToHash(big.NewInt(1))
+)
+
+// testHarmonyConfig returns the default configuration with the receipt logs kept
+// from the genesis on.
+func testHarmonyConfig() *configs.ChainConfig {
+	config := *configs.DefaultChainConfig
+	config.ReceiptLogsBlock = big.NewInt(0)
+	return &config
+}
+
+// testBackend serves the filters from a chain with a bloom indexer of small
+// sections.
+type testBackend struct {
+	*core.BlockChain
+	txPool  *core.TxPool
+	indexer *core.BloomIndexer
+}
+
+func (b *testBackend) BloomIndexer() *core.BloomIndexer { return b.indexer }
+
+func (b *testBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.txPool.SubscribeNewTxsEvent(ch)
+}
+
+// newTestBackend creates a chain of n blocks, where the blocks with the given
+// numbers contain a contract creation emitting a log. It returns the backend
+// and the addresses of the contracts.
+func newTestBackend(t *testing.T, n int, logBlocks ...int) (*testBackend, []common.Address) {
+	db := ethdb.NewMemDatabase()
+	testSpec.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, consensus.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+
+	var contracts []common.Address
+	nonce := uint64(0)
+	genDB := ethdb.NewMemDatabase()
+	genesis := testSpec.MustCommit(genDB)
+	blocks, _ := core.GenerateChain(params.TestChainConfig, genesis, consensus.NewFaker(), genDB, n, func(i int, gen *core.BlockGen) {
+		for _, number := range logBlocks {
+			if number == i+1 {
+				tx, _ := types.SignTx(types.NewContractCreation(nonce, chain.ShardID(), big.NewInt(0), 100000, big.NewInt(0), testLogCode), testSigner, testKey)
+				gen.AddTx(tx)
+				contracts = append(contracts, crypto.CreateAddress(testAddress, nonce))
+				nonce++
+			}
+		}
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	// The chain indexes sections of 4096 blocks, so none is indexed yet.
+	indexer := core.NewBloomIndexer(db, 8)
+	return &testBackend{chain, core.NewTxPool(txPoolConfig, params.TestChainConfig, chain), indexer}, contracts
+}
+
+func TestRangeFilter(t *testing.T) {
+	backend, contracts := newTestBackend(t, 20, 3, 10, 17)
+	defer backend.Stop()
+	defer backend.txPool.Stop()
+	ctx := context.Background()
+
+	tests := []struct {
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		count      int
+	}{
+		{0, -1, nil, nil, 3},
+		{0, -1, []common.Address{contracts[1]}, nil, 1},
+		{0, -1, nil, [][]common.Hash{{testTopic}}, 3},
+		{0, -1, nil, [][]common.Hash{{common.Hash{2}}}, 0},
+		{5, 12, nil, [][]common.Hash{{testTopic}}, 1},
+		{17, 17, []common.Address{contracts[2]}, nil, 1},
+		{18, -1, nil, nil, 0},
+	}
+	check := func(stage string) {
+		for i, test := range tests {
+			logs, err := NewRangeFilter(backend, test.begin, test.end, test.addresses, test.topics).Logs(ctx)
+			if err != nil {
+				t.Fatalf("%s test %d: failed to filter logs: %v", stage, i, err)
+			}
+			if len(logs) != test.count {
+				t.Errorf("%s test %d: expected %d logs, got %d", stage, i, test.count, len(logs))
+			}
+		}
+	}
+	check("unindexed")
+	// Two sections of 8 blocks are indexed, the last blocks are checked one by one.
+	if err := backend.indexer.Index(backend.CurrentHeader().Number.Uint64()); err != nil {
+		t.Fatalf("failed to index bloom bits: %v", err)
+	}
+	if sections := backend.indexer.Sections(); sections != 2 {
+		t.Fatalf("expected 2 indexed sections, got %d", sections)
+	}
+	check("indexed")
+
+	header := backend.GetHeaderByNumber(10)
+	logs, err := NewBlockFilter(backend, header.Hash(), nil, nil).Logs(ctx)
+	if err != nil || len(logs) != 1 || logs[0].Address != contracts[1] || logs[0].Topics[0] != testTopic {
+		t.Errorf("unexpected block logs %v (%v)", logs, err)
+	}
+}
+
+func TestFilterCriteriaUnmarshal(t *testing.T) {
+	var crit FilterCriteria
+	input := `{"fromBlock":"0x1","toBlock":"latest","address":"0x0100000000000000000000000000000000000000","topics":[null,["0x0200000000000000000000000000000000000000000000000000000000000000"]]}`
+	if err := json.Unmarshal([]byte(input), &crit); err != nil {
+		t.Fatalf("failed to unmarshal criteria: %v", err)
+	}
+	if crit.FromBlock.Int64() != 1 || crit.ToBlock.Int64() != -1 {
+		t.Errorf("unexpected range %v - %v", crit.FromBlock, crit.ToBlock)
+	}
+	if len(crit.Addresses) != 1 || crit.Addresses[0] != (common.Address{1}) {
+		t.Errorf("unexpected addresses %v", crit.Addresses)
+	}
+	if len(crit.Topics) != 2 || crit.Topics[0] != nil || crit.Topics[1][0] != (common.Hash{2}) {
+		t.Errorf("unexpected topics %v", crit.Topics)
+	}
+
+	if err := json.Unmarshal([]byte(`{"blockHash":"0x0100000000000000000000000000000000000000000000000000000000000000","fromBlock":"0x1"}`), &crit); err == nil {
+		t.Errorf("expected error for block hash with range")
+	}
+}
+
+func TestFilterLogs(t *testing.T) {
+	logs := []*types.Log{
+		{Address: common.Address{1}, Topics: []common.Hash{{1}, {2}}},
+		{Address: common.Address{1}, Topics: []common.Hash{{3}}},
+		{Address: common.Address{2}, Topics: []common.Hash{{1}}},
+	}
+	tests := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		count     int
+	}{
+		{nil, nil, 3},
+		{[]common.Address{{1}}, nil, 2},
+		{nil, [][]common.Hash{{{1}}}, 2},
+		{nil, [][]common.Hash{nil, {{2}}}, 1},
+		{[]common.Address{{2}}, [][]common.Hash{{{1}, {3}}}, 1},
+		{[]common.Address{{3}}, nil, 0},
+	}
+	for i, test := range tests {
+		if matched := filterLogs(logs, test.addresses, test.topics); len(matched) != test.count {
+			t.Errorf("test %d: expected %d logs, got %d", i, test.count, len(matched))
+		}
+	}
+}
+
+// headBackend reports a chain head far beyond the blocks of its backend.
+type headBackend struct {
+	Backend
+	head *types.Header
+}
+
+func (b *headBackend) CurrentHeader() *types.Header { return b.head }
+
+func TestRangeFilterLimit(t *testing.T) {
+	backend, _ := newTestBackend(t, 1)
+	defer backend.Stop()
+	defer backend.txPool.Stop()
+
+	head := &headBackend{backend, &types.Header{Number: big.NewInt(2 * maxLogsBlockRange)}}
+	if _, err := NewRangeFilter(head, 0, -1, nil, nil).Logs(context.Background()); err != errRangeTooLarge {
+		t.Errorf("expected %v, got %v", errRangeTooLarge, err)
+	}
+	if _, err := NewRangeFilter(head, 2*maxLogsBlockRange-1, -1, nil, nil).Logs(context.Background()); err != nil {
+		t.Errorf("failed to filter logs: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package hmyapi
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/harmony-one/harmony/api/service/syncing"
+)
+
+// PublicSyncingAPI provides an API to follow the state syncing of the node.
+type PublicSyncingAPI struct {
+	status func() syncing.Status
+}
+
+// NewPublicSyncingAPI creates a new syncing API reporting the given sync status.
+func NewPublicSyncingAPI(status func() syncing.Status) *PublicSyncingAPI {
+	return &PublicSyncingAPI{status}
+}
+
+// Syncing returns false when the node is in sync, otherwise the block the sync
+// started from, the current block, the highest block known from the peers and
+// the estimated number of seconds to reach it.
+func (s *PublicSyncingAPI) Syncing() (interface{}, error) {
+	status := s.status()
+	if !status.Syncing {
+		return false, nil
+	}
+	return map[string]interface{}{
+		"startingBlock": hexutil.Uint64(status.StartingBlock),
+		"currentBlock":  hexutil.Uint64(status.CurrentBlock),
+		"highestBlock":  hexutil.Uint64(status.HighestBlock),
+		"eta":           hexutil.Uint64(status.ETA.Seconds()),
+	}, nil
+}
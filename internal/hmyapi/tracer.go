@@ -0,0 +1,324 @@
+package hmyapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/core/vm"
+	"github.com/harmony-one/harmony/core/vm/tracers"
+)
+
+const (
+	// defaultTraceTimeout is the time a transaction may be traced for.
+	defaultTraceTimeout = 5 * time.Second
+
+	// defaultTraceReexec is the number of blocks re-executed to regenerate a
+	// state which is no longer available.
+	defaultTraceReexec = uint64(128)
+
+	// maxTraceReexec is the largest number of blocks a trace request may
+	// re-execute, so that a single request cannot replay the whole chain.
+	maxTraceReexec = uint64(1024)
+)
+
+var errTraceTimeout = errors.New("execution timeout")
+
+// TraceConfig holds the extra parameters of the trace functions. The named
+// tracer, callTracer or prestateTracer, replaces the structured logger if set.
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer  *string
+	Timeout *string
+	Reexec  *uint64
+}
+
+// ExecutionResult groups the structured logs emitted by the EVM while replaying
+// a transaction in debug mode, with the result of the execution.
+type ExecutionResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []StructLogRes `json:"structLogs"`
+}
+
+// StructLogRes is a structured log emitted by the EVM while replaying a
+// transaction in debug mode.
+type StructLogRes struct {
+	Pc      uint64             `json:"pc"`
+	Op      string             `json:"op"`
+	Gas     uint64             `json:"gas"`
+	GasCost uint64             `json:"gasCost"`
+	Depth   int                `json:"depth"`
+	Error   string             `json:"error,omitempty"`
+	Stack   *[]string          `json:"stack,omitempty"`
+	Memory  *[]string          `json:"memory,omitempty"`
+	Storage *map[string]string `json:"storage,omitempty"`
+}
+
+// txTraceResult is the trace of a transaction of a traced block.
+type txTraceResult struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// PrivateDebugAPI replays the transactions of the chain with a tracer.
+type PrivateDebugAPI struct {
+	chain *core.BlockChain
+}
+
+// NewPrivateDebugAPI creates a new debug API.
+func NewPrivateDebugAPI(chain *core.BlockChain) *PrivateDebugAPI {
+	return &PrivateDebugAPI{chain}
+}
+
+// TraceTransaction replays the transaction with the given hash on the state it
+// was executed on, and returns the structured logs of the execution or the
+// result of the configured tracer.
+func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, blockHash, blockNumber, index := api.chain.GetTransaction(hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	block := api.chain.GetBlock(blockHash, blockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	msg, vmctx, statedb, err := api.computeTxEnv(block, int(index), traceReexec(config))
+	if err != nil {
+		return nil, err
+	}
+	return api.traceTx(ctx, msg, vmctx, statedb, config)
+}
+
+// TraceBlockByNumber returns the traces of all the transactions of the block with
+// the given number.
+func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	var block *types.Block
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		block = api.chain.CurrentBlock()
+	} else {
+		block = api.chain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// TraceBlockByHash returns the traces of all the transactions of the block with
+// the given hash.
+func (api *PrivateDebugAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	block := api.chain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// traceBlock replays the transactions of the block one after the other on the
+// state of its parent, tracing each of them.
+func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent := api.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, traceReexec(config))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		signer  = types.MakeSigner(api.chain.Config(), block.Number())
+		results = make([]*txTraceResult, len(block.Transactions()))
+	)
+	for i, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer)
+		vmctx := core.NewEVMContext(msg, block.Header(), api.chain, nil)
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		result, err := api.traceTx(ctx, msg, vmctx, statedb, config)
+		if err != nil {
+			results[i] = &txTraceResult{Error: err.Error()}
+		} else {
+			results[i] = &txTraceResult{Result: result}
+		}
+		statedb.Finalise(api.chain.Config().IsEIP158(block.Number()))
+	}
+	return results, nil
+}
+
+// traceTx executes the message on the state with the tracer of the config and
+// returns its result.
+func (api *PrivateDebugAPI) traceTx(ctx context.Context, msg types.Message, vmctx vm.Context, statedb *state.DB, config *TraceConfig) (interface{}, error) {
+	var (
+		tracer  vm.Tracer = vm.NewStructLogger(nil)
+		timeout           = defaultTraceTimeout
+		err     error
+	)
+	if config != nil {
+		if config.Timeout != nil {
+			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+				return nil, err
+			}
+		}
+		if config.Tracer != nil {
+			// The tracers reporting the state before the transaction read it
+			// from a copy, which the execution does not modify.
+			namedTracer, err := tracers.New(*config.Tracer, statedb.Copy())
+			if err != nil {
+				return nil, err
+			}
+			tracer = namedTracer
+		} else {
+			tracer = vm.NewStructLogger(config.LogConfig)
+		}
+	}
+	vmenv := vm.NewEVM(vmctx, statedb, api.chain.Config(), vm.Config{Debug: true, Tracer: tracer})
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	go func() {
+		<-deadlineCtx.Done()
+		vmenv.Cancel()
+	}()
+
+	result, gas, failed, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	if deadlineCtx.Err() == context.DeadlineExceeded {
+		return nil, errTraceTimeout
+	}
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		return &ExecutionResult{
+			Gas:         gas,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", result),
+			StructLogs:  formatLogs(tracer.StructLogs()),
+		}, nil
+	case tracers.Tracer:
+		return tracer.GetResult()
+	}
+	return nil, fmt.Errorf("unsupported tracer %T", tracer)
+}
+
+// computeTxEnv returns the message, the EVM context and the state with which the
+// transaction at the given index of the block was executed.
+func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (types.Message, vm.Context, *state.DB, error) {
+	parent := api.chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return types.Message{}, vm.Context{}, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, reexec)
+	if err != nil {
+		return types.Message{}, vm.Context{}, nil, err
+	}
+	signer := types.MakeSigner(api.chain.Config(), block.Number())
+	for i, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer)
+		vmctx := core.NewEVMContext(msg, block.Header(), api.chain, nil)
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		if i == txIndex {
+			return msg, vmctx, statedb, nil
+		}
+		vmenv := vm.NewEVM(vmctx, statedb, api.chain.Config(), vm.Config{})
+		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+			return types.Message{}, vm.Context{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		statedb.Finalise(api.chain.Config().IsEIP158(block.Number()))
+	}
+	return types.Message{}, vm.Context{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
+}
+
+// computeStateDB returns the state after the given block. A state which is no
+// longer available is regenerated by re-executing up to reexec blocks on top of
+// the most recent available state.
+func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*state.DB, error) {
+	statedb, err := api.chain.StateAt(block.Root())
+	if err == nil {
+		return statedb, nil
+	}
+	var blocks []*types.Block
+	for i := uint64(0); i < reexec && err != nil; i++ {
+		blocks = append(blocks, block)
+		if block.NumberU64() == 0 {
+			break
+		}
+		if block = api.chain.GetBlock(block.ParentHash(), block.NumberU64()-1); block == nil {
+			break
+		}
+		statedb, err = api.chain.StateAt(block.Root())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("required historical state unavailable (reexec=%d)", reexec)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := blocks[i]
+		if _, _, _, _, err := api.chain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+			return nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
+		}
+		if root := statedb.IntermediateRoot(api.chain.Config().IsEIP158(block.Number())); root != block.Root() {
+			return nil, fmt.Errorf("state root mismatch at block %d: %x != %x", block.NumberU64(), root, block.Root())
+		}
+	}
+	return statedb, nil
+}
+
+// traceReexec returns the number of blocks which may be re-executed to
+// regenerate a state for the config, at most maxTraceReexec.
+func traceReexec(config *TraceConfig) uint64 {
+	if config == nil || config.Reexec == nil {
+		return defaultTraceReexec
+	}
+	if *config.Reexec > maxTraceReexec {
+		return maxTraceReexec
+	}
+	return *config.Reexec
+}
+
+// formatLogs formats the structured logs for the RPC output.
+func formatLogs(logs []vm.StructLog) []StructLogRes {
+	formatted := make([]StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = fmt.Sprintf("%x", math.PaddedBigBytes(stackValue, 32))
+			}
+			formatted[index].Stack = &stack
+		}
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, fmt.Sprintf("%x", trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = &memory
+		}
+		if trace.Storage != nil {
+			storage := make(map[string]string)
+			for key, value := range trace.Storage {
+				storage[fmt.Sprintf("%x", key)] = fmt.Sprintf("%x", value)
+			}
+			formatted[index].Storage = &storage
+		}
+	}
+	return formatted
+}
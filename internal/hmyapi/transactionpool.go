@@ -0,0 +1,85 @@
+package hmyapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// PublicTransactionPoolAPI exposes methods for the RPC interface to look up and
+// send transactions.
+type PublicTransactionPoolAPI struct {
+	chain  *core.BlockChain
+	txPool *core.TxPool
+}
+
+// NewPublicTransactionPoolAPI creates a new RPC service with methods specific for
+// the transaction pool.
+func NewPublicTransactionPoolAPI(chain *core.BlockChain, txPool *core.TxPool) *PublicTransactionPoolAPI {
+	return &PublicTransactionPoolAPI{chain, txPool}
+}
+
+// GetTransactionCount returns the number of transactions the given address has
+// sent for the given block number. The pending block number includes the
+// transactions in the pool.
+func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		nonce := s.txPool.State().GetNonce(address)
+		return (*hexutil.Uint64)(&nonce), nil
+	}
+	statedb, _, err := NewPublicBlockChainAPI(s.chain).stateAndHeaderByNumber(blockNr)
+	if err != nil {
+		return nil, err
+	}
+	nonce := statedb.GetNonce(address)
+	return (*hexutil.Uint64)(&nonce), nil
+}
+
+// GetTransactionByHash returns the transaction for the given hash, looking it up
+// in the chain first and in the transaction pool next.
+func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) *RPCTransaction {
+	if tx, blockHash, blockNumber, index := s.chain.GetTransaction(hash); tx != nil {
+		return newRPCTransaction(tx, s.signer(blockNumber), blockHash, blockNumber, index)
+	}
+	if tx := s.txPool.Get(hash); tx != nil {
+		return newRPCTransaction(tx, s.signer(s.chain.CurrentBlock().NumberU64()), common.Hash{}, 0, 0)
+	}
+	return nil
+}
+
+// GetTransactionReceipt returns the receipt of the transaction for the given hash,
+// or nil if the transaction is not included in the chain.
+func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, blockHash, blockNumber, index := s.chain.GetTransaction(hash)
+	if tx == nil {
+		return nil, nil
+	}
+	receipt, _, _, _ := s.chain.GetReceipt(hash)
+	if receipt == nil {
+		return nil, nil
+	}
+	return rpcMarshalReceipt(receipt, tx, s.signer(blockNumber), blockHash, blockNumber, index), nil
+}
+
+// SendRawTransaction adds the signed transaction to the transaction pool and
+// returns its hash.
+func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.txPool.AddRemote(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+func (s *PublicTransactionPoolAPI) signer(blockNumber uint64) types.Signer {
+	return types.MakeSigner(s.chain.Config(), new(big.Int).SetUint64(blockNumber))
+}
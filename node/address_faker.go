@@ -6,9 +6,11 @@ import (
 	"math/rand"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/internal/configs"
 )
 
 // CreateGenesisAllocWithTestingAddresses create the genesis block allocation that contains deterministically
@@ -33,3 +35,32 @@ func (node *Node) CreateGenesisAllocWithTestingAddresses(numAddress int) core.Ge
 	}
 	return genesisAloc
 }
+
+// CreateTestGenesis creates the genesis used by test networks that were not initialized
+// from a genesis file. It funds the testing addresses and a deterministic contract key.
+// TODO: Remove it later when moving to production.
+func (node *Node) CreateTestGenesis(chainConfig *configs.ChainConfig) *core.Genesis {
+	genesisAlloc := node.CreateGenesisAllocWithTestingAddresses(100)
+	contractKey, _ := ecdsa.GenerateKey(crypto.S256(), strings.NewReader("Test contract key string stream that is fixed so that generated test key are deterministic every time"))
+	contractAddress := crypto.PubkeyToAddress(contractKey.PublicKey)
+	contractFunds := big.NewInt(TotalInitFund)
+	contractFunds = contractFunds.Mul(contractFunds, big.NewInt(params.Ether))
+	genesisAlloc[contractAddress] = core.GenesisAccount{Balance: contractFunds}
+	node.ContractKeys = append(node.ContractKeys, contractKey)
+
+	return &core.Genesis{
+		Config:        chainConfig.EVMConfig(node.Consensus.ShardID), // Use ChainID as piggybacked ShardID
+		HarmonyConfig: chainConfig,
+		Alloc:         genesisAlloc,
+		ShardID:       uint32(node.Consensus.ShardID),
+	}
+}
+
+// testBeaconGenesisHash returns the hash of the genesis block of the beacon chain
+// of a test network, created like the given test genesis of another shard.
+func testBeaconGenesisHash(testGenesis *core.Genesis, chainConfig *configs.ChainConfig) common.Hash {
+	beaconGenesis := *testGenesis
+	beaconGenesis.ShardID = core.BeaconShardID
+	beaconGenesis.Config = chainConfig.EVMConfig(core.BeaconShardID)
+	return beaconGenesis.ToBlock(nil).Hash()
+}
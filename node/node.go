@@ -3,32 +3,33 @@ package node
 import (
 	"bytes"
 	"crypto/ecdsa"
-	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/harmony-one/bls/ffi/go/bls"
 	"github.com/harmony-one/harmony/drand"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/api/client"
 	clientService "github.com/harmony-one/harmony/api/client/service"
 	proto_discovery "github.com/harmony-one/harmony/api/proto/discovery"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	service_manager "github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/beaconfollower"
 	blockproposal "github.com/harmony-one/harmony/api/service/blockproposal"
 	"github.com/harmony-one/harmony/api/service/clientsupport"
 	consensus_service "github.com/harmony-one/harmony/api/service/consensus"
 	"github.com/harmony-one/harmony/api/service/discovery"
 	"github.com/harmony-one/harmony/api/service/explorer"
+	"github.com/harmony-one/harmony/api/service/jsonrpc"
 	"github.com/harmony-one/harmony/api/service/networkinfo"
 	randomness_service "github.com/harmony-one/harmony/api/service/randomness"
 	"github.com/harmony-one/harmony/api/service/staking"
@@ -37,9 +38,13 @@ import (
 	downloader_pb "github.com/harmony-one/harmony/api/service/syncing/downloader/proto"
 	bft "github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	core_staking "github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
 	"github.com/harmony-one/harmony/crypto/pki"
+	"github.com/harmony-one/harmony/internal/configs"
+	"github.com/harmony-one/harmony/internal/hmyapi"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
 	"github.com/harmony-one/harmony/p2p"
@@ -105,25 +110,17 @@ const (
 	waitBeforeJoinShard = time.Second * 3
 	timeOutToJoinShard  = time.Minute * 10
 	// ClientServicePortDiff is the positive port diff for client service
-	ClientServicePortDiff       = 5555
-	maxBroadcastNodes           = 10                  // broadcast at most maxBroadcastNodes peers that need in sync
-	broadcastTimeout      int64 = 3 * 60 * 1000000000 // 3 mins
+	ClientServicePortDiff = 5555
 )
 
-// use to push new block to outofsync node
-type syncConfig struct {
-	timestamp int64
-	client    *downloader.Client
-}
-
 // Node represents a protocol-participating node in the network
 type Node struct {
-	Consensus              *bft.Consensus       // Consensus object containing all Consensus related data (e.g. committee members, signatures, commits)
-	BlockChannel           chan *types.Block    // The channel to send newly proposed blocks
-	ConfirmedBlockChannel  chan *types.Block    // The channel to send confirmed blocks
-	pendingTransactions    types.Transactions   // All the transactions received but not yet processed for Consensus
-	transactionInConsensus []*types.Transaction // The transactions selected into the new block and under Consensus process
-	pendingTxMutex         sync.Mutex
+	Consensus              *bft.Consensus         // Consensus object containing all Consensus related data (e.g. committee members, signatures, commits)
+	BlockChannel           chan *types.Block      // The channel to send newly proposed blocks
+	ConfirmedBlockChannel  chan *types.Block      // The channel to send confirmed blocks
+	transactionInConsensus []*types.Transaction   // The transactions selected into the new block and under Consensus process
+	pendingCXReceipts      types.CXReceiptsProofs // Cross-shard receipts proofs received but not yet included in a block
+	pendingCXMutex         sync.Mutex
 	DRand                  *drand.DRand // The instance for distributed randomness protocol
 
 	blockchain *core.BlockChain   // The blockchain for the shard where this node belongs
@@ -139,16 +136,24 @@ type Node struct {
 	State      State      // State of the Node
 	stateMutex sync.Mutex // mutex for change node state
 
-	TxPool *core.TxPool
+	TxPool *core.TxPool // All the transactions received but not yet processed for Consensus
 	Worker *worker.Worker
 
+	// ProposalPolicy decides when the leader proposes a new block
+	ProposalPolicy BlockProposalPolicy
+
 	// Client server (for wallet requests)
 	clientServer *clientService.Server
 
+	// SyncDownloadConfig configures the block download when the node is out of sync
+	SyncDownloadConfig syncing.DownloadConfig
+
+	// RPCConfig configures the HTTP and WebSocket JSON-RPC endpoints
+	RPCConfig jsonrpc.Config
+
 	// Syncing component.
-	downloaderServer       *downloader.Server
-	stateSync              *syncing.StateSync
-	peerRegistrationRecord map[uint32]*syncConfig // record registration time (unixtime) of peers begin in syncing
+	downloaderServer *downloader.Server
+	stateSync        *syncing.StateSync // only set by DoSyncing, under stateMutex
 
 	// The p2p host used to send/receive p2p messages
 	host p2p.Host
@@ -165,11 +170,6 @@ type Node struct {
 	// Service manager.
 	serviceManager *service_manager.Manager
 
-	//Staked Accounts and Contract
-	CurrentStakes          map[common.Address]int64 //This will save the latest information about staked nodes.
-	StakingContractAddress common.Address
-	WithdrawStakeFunc      []byte
-
 	//Node Account
 	AccountKey *ecdsa.PrivateKey
 	Address    common.Address
@@ -191,25 +191,123 @@ func (node *Node) Blockchain() *core.BlockChain {
 	return node.blockchain
 }
 
-// Add new transactions to the pending transaction list
+// Add new transactions created by this node to the transaction pool as local
+// transactions, which are journaled and exempt from the gas price limit.
 func (node *Node) addPendingTransactions(newTxs types.Transactions) {
-	node.pendingTxMutex.Lock()
-	node.pendingTransactions = append(node.pendingTransactions, newTxs...)
-	node.pendingTxMutex.Unlock()
-	utils.GetLogInstance().Debug("Got more transactions", "num", len(newTxs), "totalPending", len(node.pendingTransactions))
+	errs := node.TxPool.AddLocals(newTxs)
+	logPoolErrors(newTxs, errs)
+	pending, queued := node.TxPool.Stats()
+	utils.GetLogInstance().Debug("Got more transactions", "num", len(newTxs), "totalPending", pending, "totalQueued", queued)
+}
+
+// Add new transactions received from the network to the transaction pool, and
+// return the errors of the rejected ones.
+func (node *Node) addRemoteTransactions(newTxs types.Transactions) []error {
+	errs := node.TxPool.AddRemotes(newTxs)
+	logPoolErrors(newTxs, errs)
+	pending, queued := node.TxPool.Stats()
+	utils.GetLogInstance().Debug("Got more transactions", "num", len(newTxs), "totalPending", pending, "totalQueued", queued)
+	return errs
+}
+
+// logPoolErrors logs the transactions rejected by the transaction pool.
+func logPoolErrors(txs types.Transactions, errs []error) {
+	for i, err := range errs {
+		if err != nil {
+			utils.GetLogInstance().Debug("Transaction rejected by pool", "hash", txs[i].Hash(), "error", err)
+		}
+	}
+}
+
+// pendingTransactionsByPriceAndNonce returns the executable transactions of the
+// pool, ordered by gas price and nonce.
+func (node *Node) pendingTransactionsByPriceAndNonce() types.Transactions {
+	// The pool is reset to the current head first, the new head event may not
+	// have been handled yet right after a block is committed.
+	pending, err := node.TxPool.PendingAt(node.blockchain.CurrentBlock().Header())
+	if err != nil {
+		utils.GetLogInstance().Error("Failed to fetch pending transactions", "error", err)
+		return nil
+	}
+	signer := types.MakeSigner(node.blockchain.Config(), node.blockchain.CurrentBlock().Number())
+	sorted := types.NewTransactionsByPriceAndNonce(signer, pending)
+	txs := types.Transactions{}
+	for tx := sorted.Peek(); tx != nil; tx = sorted.Peek() {
+		txs = append(txs, tx)
+		sorted.Shift()
+	}
+	return txs
 }
 
-// Take out a subset of valid transactions from the pending transaction list
-// Note the pending transaction list will then contain the rest of the txs
+// Take out a subset of valid transactions from the executable transactions of
+// the pool, ordered by gas price and nonce. The selected transactions stay in
+// the pool until the block including them becomes the chain head.
 func (node *Node) getTransactionsForNewBlock(maxNumTxs int) types.Transactions {
-	node.pendingTxMutex.Lock()
-	selected, unselected, invalid := node.Worker.SelectTransactionsForNewBlock(node.pendingTransactions, maxNumTxs)
-	_ = invalid // invalid txs are discard
-
-	utils.GetLogInstance().Debug("Invalid transactions discarded", "number", len(invalid))
-	node.pendingTransactions = unselected
-	utils.GetLogInstance().Debug("Remaining pending transactions", "number", len(node.pendingTransactions))
-	node.pendingTxMutex.Unlock()
+	return node.selectTransactionsForNewBlock(node.pendingTransactionsByPriceAndNonce(), maxNumTxs)
+}
+
+// selectTransactionsForNewBlock returns the transactions among the given ones
+// that can be applied on top of the current block.
+func (node *Node) selectTransactionsForNewBlock(txs types.Transactions, maxNumTxs int) types.Transactions {
+	selected, unselected, invalid := node.Worker.SelectTransactionsForNewBlock(txs, maxNumTxs)
+
+	utils.GetLogInstance().Debug("Invalid transactions skipped", "number", len(invalid))
+	utils.GetLogInstance().Debug("Remaining pending transactions", "number", len(unselected))
+	return selected
+}
+
+// getPoolTransaction returns a transaction of the transaction pool with its status.
+func (node *Node) getPoolTransaction(hash common.Hash) (*types.Transaction, core.TxStatus) {
+	return node.TxPool.Get(hash), node.TxPool.Status([]common.Hash{hash})[0]
+}
+
+// pendingTransactionCount returns the number of executable transactions in the pool.
+func (node *Node) pendingTransactionCount() int {
+	pending, _ := node.TxPool.Stats()
+	return pending
+}
+
+// pendingCXReceiptCount returns the number of cross-shard receipts proofs not yet
+// included in a block.
+func (node *Node) pendingCXReceiptCount() int {
+	node.pendingCXMutex.Lock()
+	defer node.pendingCXMutex.Unlock()
+	return len(node.pendingCXReceipts)
+}
+
+// Add a new cross-shard receipts proof to the pending list
+func (node *Node) addPendingCXReceipts(cxp *types.CXReceiptsProof) {
+	node.pendingCXMutex.Lock()
+	node.pendingCXReceipts = append(node.pendingCXReceipts, cxp)
+	node.pendingCXMutex.Unlock()
+	utils.GetLogInstance().Debug("Got more cross-shard receipts", "fromShard", cxp.ShardID(), "blockNum", cxp.BlockNumber(), "totalPending", len(node.pendingCXReceipts))
+}
+
+// Take out the cross-shard receipts proofs that can still be credited by a new
+// block. Spent or otherwise invalid proofs are dropped from the pending list.
+func (node *Node) getIncomingReceiptsForNewBlock() types.CXReceiptsProofs {
+	node.pendingCXMutex.Lock()
+	defer node.pendingCXMutex.Unlock()
+
+	type source struct {
+		shardID uint32
+		number  uint64
+	}
+	seen := make(map[source]bool)
+	selected := types.CXReceiptsProofs{}
+	for _, cxp := range node.pendingCXReceipts {
+		key := source{cxp.ShardID(), cxp.BlockNumber()}
+		if seen[key] {
+			continue
+		}
+		if err := node.blockchain.ValidateCXReceiptsProof(cxp); err != nil {
+			utils.GetLogInstance().Debug("Dropping cross-shard receipts", "fromShard", cxp.ShardID(), "blockNum", cxp.BlockNumber(), "Error", err)
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, cxp)
+	}
+	node.pendingCXReceipts = selected
 	return selected
 }
 
@@ -232,8 +330,11 @@ func (node *Node) countNumTransactionsInBlockchain() int {
 	return count
 }
 
-// New creates a new node.
-func New(host p2p.Host, consensus *bft.Consensus, db ethdb.Database) *Node {
+// New creates a new node. The chain configuration is used when a new genesis
+// block is created; nil means the default configuration. The cache
+// configuration chooses between a pruned and an archive state; nil means a
+// pruned state.
+func New(host p2p.Host, consensus *bft.Consensus, db ethdb.Database, chainConfig *configs.ChainConfig, cacheConfig *core.CacheConfig) *Node {
 	node := Node{}
 
 	if host != nil {
@@ -245,42 +346,63 @@ func New(host p2p.Host, consensus *bft.Consensus, db ethdb.Database) *Node {
 		// Consensus and associated channel to communicate blocks
 		node.Consensus = consensus
 
-		// Initialize genesis block and blockchain
-		genesisAlloc := node.CreateGenesisAllocWithTestingAddresses(100)
-		contractKey, _ := ecdsa.GenerateKey(crypto.S256(), strings.NewReader("Test contract key string stream that is fixed so that generated test key are deterministic every time"))
-		contractAddress := crypto.PubkeyToAddress(contractKey.PublicKey)
-		contractFunds := big.NewInt(TotalInitFund)
-		contractFunds = contractFunds.Mul(contractFunds, big.NewInt(params.Ether))
-		genesisAlloc[contractAddress] = core.GenesisAccount{Balance: contractFunds}
-		node.ContractKeys = append(node.ContractKeys, contractKey)
-
 		database := db
 		if database == nil {
 			database = ethdb.NewMemDatabase()
 		}
 
-		chainConfig := params.TestChainConfig
-		chainConfig.ChainID = big.NewInt(int64(node.Consensus.ShardID)) // Use ChainID as piggybacked ShardID
-		gspec := core.Genesis{
-			Config:  chainConfig,
-			Alloc:   genesisAlloc,
-			ShardID: uint32(node.Consensus.ShardID),
+		if chainConfig == nil {
+			chainConfig = configs.DefaultChainConfig
 		}
 
-		_ = gspec.MustCommit(database)
-		chain, _ := core.NewBlockChain(database, nil, gspec.Config, node.Consensus, vm.Config{}, nil)
+		// Initialize genesis block and blockchain. A database initialized by
+		// "harmony init" keeps its genesis, otherwise the test genesis is used.
+		testGenesis := node.CreateTestGenesis(chainConfig)
+		genesisHash := rawdb.ReadCanonicalHash(database, 0)
+		isTestGenesis := true
+		if (genesisHash == common.Hash{}) {
+			genesisHash = testGenesis.MustCommit(database).Hash()
+		} else if genesisHash != testGenesis.ToBlock(nil).Hash() {
+			utils.GetLogInstance().Info("Using stored genesis block", "hash", genesisHash)
+			// The testing contract key is not funded by a custom genesis.
+			node.ContractKeys = nil
+			isTestGenesis = false
+		}
+		evmConfig := rawdb.ReadChainConfig(database, genesisHash)
+		if evmConfig == nil {
+			evmConfig = testGenesis.Config
+		}
+
+		chain, err := core.NewBlockChain(database, cacheConfig, evmConfig, node.Consensus, vm.Config{}, nil)
+		if err != nil {
+			utils.GetLogInstance().Error("Failed to create blockchain", "error", err)
+			os.Exit(1)
+		}
 		node.blockchain = chain
+		if node.Consensus.ShardID != core.BeaconShardID {
+			// The nodes of the other shards follow the beacon chain for its shard states.
+			beaconGenesis := chain.HarmonyConfig().BeaconGenesisHash
+			if (beaconGenesis == common.Hash{}) && isTestGenesis {
+				beaconGenesis = testBeaconGenesisHash(testGenesis, chainConfig)
+			}
+			if (beaconGenesis == common.Hash{}) {
+				utils.GetLogInstance().Warn("No beacon genesis hash in the chain configuration, the beacon chain is not followed")
+			} else {
+				chain.SetBeaconHeaders(core.NewBeaconHeaders(database, beaconGenesis, evmConfig, chain.HarmonyConfig(), node.Consensus))
+			}
+		}
 		node.BlockChannel = make(chan *types.Block)
 		node.ConfirmedBlockChannel = make(chan *types.Block)
-		node.TxPool = core.NewTxPool(core.DefaultTxPoolConfig, params.TestChainConfig, chain)
-		node.Worker = worker.New(params.TestChainConfig, chain, node.Consensus, pki.GetAddressFromPublicKey(node.SelfPeer.PubKey), node.Consensus.ShardID)
-		node.AddFaucetContractToPendingTransactions()
-		if node.Role == BeaconLeader {
-			node.AddStakingContractToPendingTransactions() //This will save the latest information about staked nodes in current staked
-			node.DepositToFakeAccounts()
+		node.TxPool = core.NewTxPool(newTxPoolConfig(database), evmConfig, chain)
+		// The transactions of the current clients carry no gas price.
+		node.TxPool.SetGasPrice(big.NewInt(0))
+		node.Worker = worker.New(evmConfig, chain, node.Consensus, pki.GetAddressFromPublicKey(node.SelfPeer.PubKey), node.Consensus.ShardID)
+		SetupGenesisCommittees(node.Consensus, database, genesisHash)
+		if len(node.ContractKeys) > 0 {
+			node.AddFaucetContractToPendingTransactions()
 		}
-		if node.Role == BeaconLeader || node.Role == BeaconValidator {
-			node.CurrentStakes = make(map[common.Address]int64)
+		if node.Role == BeaconLeader && len(node.ContractKeys) > 0 {
+			node.DepositToFakeAccounts()
 		}
 		node.Consensus.ConsensusBlock = make(chan *bft.BFTBlockInfo)
 		node.Consensus.VerifiedNewBlock = make(chan *types.Block)
@@ -292,9 +414,12 @@ func New(host p2p.Host, consensus *bft.Consensus, db ethdb.Database) *Node {
 		node.State = NodeInit
 	}
 
+	node.ProposalPolicy = DefaultBlockProposalPolicy
+	node.SyncDownloadConfig = syncing.DefaultDownloadConfig
+	node.RPCConfig = jsonrpc.DefaultConfig
+
 	// Setup initial state of syncing.
 	node.StopPing = make(chan struct{})
-	node.peerRegistrationRecord = make(map[uint32]*syncConfig)
 
 	node.OfflinePeers = make(chan p2p.Peer)
 	go node.RemovePeersHandler()
@@ -307,28 +432,104 @@ func New(host p2p.Host, consensus *bft.Consensus, db ethdb.Database) *Node {
 	return &node
 }
 
-func (node *Node) getDeployedStakingContract() common.Address {
-	return node.StakingContractAddress
+// newTxPoolConfig returns the transaction pool configuration of a node. The local
+// transactions are journaled next to the database, or not at all for an
+// in-memory database.
+func newTxPoolConfig(db ethdb.Database) core.TxPoolConfig {
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+	if ldb, ok := db.(*ethdb.LDBDatabase); ok {
+		config.Journal = filepath.Join(ldb.Path(), "transactions.rlp")
+	}
+	return config
+}
+
+// SetupGenesisCommittees loads the committees stored with the genesis block into
+// the given consensus, so that the signatures of blocks and cross-shard receipts
+// can be verified.
+func SetupGenesisCommittees(consensus *bft.Consensus, db ethdb.Database, genesisHash common.Hash) {
+	setupGenesisCommittee(consensus, rawdb.ReadGenesisCommittee(db, genesisHash))
+	shardState := rawdb.ReadShardState(db, genesisHash, 0)
+	setupShardCommittees(consensus, shardState)
+	for shardID, keys := range rawdb.ReadGenesisShardCommittees(db, genesisHash) {
+		setupShardCommittee(consensus, shardID, keys)
+	}
+	for _, committee := range shardState {
+		if committee.ShardID != consensus.ShardID && !consensus.HasShardPublicKeys(committee.ShardID) {
+			utils.GetLogInstance().Warn("No genesis committee of shard, its cross-shard receipts can't be verified", "shardID", committee.ShardID)
+		}
+	}
 }
 
-//In order to get the deployed contract address of a contract, we need to find the nonce of the address that created it.
-//(Refer: https://solidity.readthedocs.io/en/v0.5.3/introduction-to-smart-contracts.html#index-8)
-// Then we can (re)create the deployed address. Trivially, this is 0 for us.
-// The deployed contract address can also be obtained via the receipt of the contract creating transaction.
-func (node *Node) generateDeployedStakingContractAddress(mycontracttx *types.Transaction, contractAddress common.Address) common.Address {
-	//Ideally we send the transaction to
+// setupGenesisCommittee uses the initial committee from the genesis, if any, as the
+// consensus public keys.
+func setupGenesisCommittee(consensus *bft.Consensus, keys [][]byte) {
+	if len(keys) == 0 {
+		return
+	}
+	pubKeys := make([]*bls.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		pubKey := &bls.PublicKey{}
+		if err := pubKey.Deserialize(key); err != nil {
+			utils.GetLogInstance().Warn("Invalid genesis committee key", "key", hex.EncodeToString(key), "error", err)
+			continue
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	count := consensus.UpdatePublicKeys(pubKeys)
+	utils.GetLogInstance().Info("Loaded genesis committee", "#keys", count)
+}
 
-	//Correct Way 1:
-	//node.SendTx(mycontracttx)
-	//receipts := node.worker.GetCurrentReceipts()
-	//deployedcontractaddress = recepits[len(receipts)-1].ContractAddress //get the address from the receipt
+// setupShardCommittee registers the committee of another shard given by the
+// genesis as serialized BLS public keys.
+func setupShardCommittee(consensus *bft.Consensus, shardID uint32, keys [][]byte) {
+	if shardID == consensus.ShardID {
+		return
+	}
+	pubKeys := make([]*bls.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		pubKey := &bls.PublicKey{}
+		if err := pubKey.Deserialize(key); err != nil {
+			utils.GetLogInstance().Warn("Invalid genesis shard committee key", "shardID", shardID, "key", hex.EncodeToString(key), "error", err)
+			continue
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	if len(pubKeys) > 0 {
+		consensus.SetShardPublicKeys(shardID, pubKeys)
+		utils.GetLogInstance().Info("Loaded shard committee", "shardID", shardID, "#keys", len(pubKeys))
+	}
+}
 
-	//Correct Way 2:
-	//nonce := GetNonce(contractAddress)
-	//deployedAddress := crypto.CreateAddress(contractAddress, uint64(nonce))
-	//deployedcontractaddress = recepits[len(receipts)-1].ContractAddress //get the address from the receipt
-	nonce := 0
-	return crypto.CreateAddress(contractAddress, uint64(nonce))
+// setupShardCommittees registers the committees of the other shards from the
+// genesis shard state, so that their cross-shard receipts can be verified. Only
+// node IDs given as hex encoded BLS public keys are used.
+func setupShardCommittees(consensus *bft.Consensus, shardState types.ShardState) {
+	for _, committee := range shardState {
+		if committee.ShardID == consensus.ShardID {
+			continue
+		}
+		pubKeys := []*bls.PublicKey{}
+		for _, nodeID := range committee.NodeList {
+			key, err := hex.DecodeString(string(nodeID))
+			if err != nil {
+				continue
+			}
+			pubKey := &bls.PublicKey{}
+			if err := pubKey.Deserialize(key); err != nil {
+				continue
+			}
+			pubKeys = append(pubKeys, pubKey)
+		}
+		if len(pubKeys) > 0 {
+			consensus.SetShardPublicKeys(committee.ShardID, pubKeys)
+			utils.GetLogInstance().Info("Loaded shard committee", "shardID", committee.ShardID, "#keys", len(pubKeys))
+		}
+	}
+}
+
+func (node *Node) currentBlockNumber() uint64 {
+	return node.blockchain.CurrentBlock().NumberU64()
 }
 
 // IsOutOfSync checks whether the node is out of sync by comparing latest block with consensus block
@@ -365,16 +566,15 @@ func (node *Node) DoSyncing() {
 			continue
 		case consensusBlockInfo := <-node.Consensus.ConsensusBlock:
 			if !node.IsOutOfSync(consensusBlockInfo) {
-				startHash := node.blockchain.CurrentBlock().Hash()
-				node.stateSync.StartStateSync(startHash[:], node.blockchain, node.Worker)
+				if err := node.stateSync.StartStateSync(node.blockchain, node.Worker); err != nil {
+					utils.GetLogInstance().Warn("[SYNC] node is not in sync", "error", err)
+					continue
+				}
 				if node.State == NodeNotInSync {
 					utils.GetLogInstance().Info("[SYNC] Node is now IN SYNC!")
 				}
-				node.stateMutex.Lock()
-				node.State = NodeReadyForConsensus
-				node.stateMutex.Unlock()
 				node.stateSync.CloseConnections()
-				node.stateSync = nil
+				node.setSyncState(NodeReadyForConsensus, nil)
 				continue
 			} else {
 				utils.GetLogInstance().Debug("[SYNC] node is out of sync")
@@ -384,16 +584,38 @@ func (node *Node) DoSyncing() {
 			}
 
 			if node.stateSync == nil {
-				node.stateSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port)
-				node.stateSync.CreateSyncConfig(node.GetSyncingPeers())
-				node.stateSync.MakeConnectionToPeers()
+				stateSync := syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port)
+				stateSync.SetDownloadConfig(node.SyncDownloadConfig)
+				stateSync.CreateSyncConfig(node.GetSyncingPeers())
+				stateSync.MakeConnectionToPeers()
+				node.setSyncState(NodeNotInSync, stateSync)
 			}
-			startHash := node.blockchain.CurrentBlock().Hash()
-			node.stateSync.StartStateSync(startHash[:], node.blockchain, node.Worker)
+			node.stateSync.StartStateSync(node.blockchain, node.Worker)
 		}
 	}
 }
 
+// setSyncState sets the state of the node and its state syncing, which are read
+// together by SyncStatus.
+func (node *Node) setSyncState(state State, stateSync *syncing.StateSync) {
+	node.stateMutex.Lock()
+	defer node.stateMutex.Unlock()
+	node.State = state
+	node.stateSync = stateSync
+}
+
+// SyncStatus returns the progress of the state syncing of the node.
+func (node *Node) SyncStatus() syncing.Status {
+	node.stateMutex.Lock()
+	state, stateSync := node.State, node.stateSync
+	node.stateMutex.Unlock()
+	if stateSync == nil || state != NodeNotInSync {
+		current := node.currentBlockNumber()
+		return syncing.Status{StartingBlock: current, CurrentBlock: current, HighestBlock: current}
+	}
+	return stateSync.Status(node.blockchain)
+}
+
 // AddPeers adds neighbors nodes
 func (node *Node) AddPeers(peers []*p2p.Peer) int {
 	count := 0
@@ -519,99 +741,99 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest) (*
 			}
 			response.Payload = append(response.Payload, blockHash[:])
 		}
+	case downloader_pb.DownloaderRequest_HEADERS:
+		response.BlockNumber = node.blockchain.CurrentBlock().NumberU64()
+		size := uint64(request.Size)
+		if size > syncing.MaxHeadersPerRequest {
+			size = syncing.MaxHeadersPerRequest
+		}
+		for number := request.StartNumber; number < request.StartNumber+size && number <= response.BlockNumber; number++ {
+			header := node.blockchain.GetHeaderByNumber(number)
+			if header == nil {
+				break
+			}
+			encodedHeader, err := rlp.EncodeToBytes(header)
+			if err != nil {
+				break
+			}
+			response.Payload = append(response.Payload, encodedHeader)
+		}
 	case downloader_pb.DownloaderRequest_BLOCK:
-		for _, bytes := range request.Hashes {
+		hashes := request.Hashes
+		if len(hashes) > syncing.MaxBlocksPerRequest {
+			hashes = hashes[:syncing.MaxBlocksPerRequest]
+		}
+		for _, bytes := range hashes {
 			var hash common.Hash
 			hash.SetBytes(bytes)
 			block := node.blockchain.GetBlockByHash(hash)
+			if block == nil {
+				break
+			}
 			encodedBlock, err := rlp.EncodeToBytes(block)
 			if err == nil {
 				response.Payload = append(response.Payload, encodedBlock)
 			}
 		}
-	case downloader_pb.DownloaderRequest_NEWBLOCK:
-		if node.State != NodeNotInSync {
-			utils.GetLogInstance().Debug("[SYNC] new block received, but state is", "state", node.State.String())
-			response.Type = downloader_pb.DownloaderResponse_INSYNC
-			return response, nil
+	case downloader_pb.DownloaderRequest_TRIENODES:
+		hashes := request.Hashes
+		if len(hashes) > syncing.MaxTrieNodesPerRequest {
+			hashes = hashes[:syncing.MaxTrieNodesPerRequest]
 		}
-		var blockObj types.Block
-		err := rlp.DecodeBytes(request.BlockHash, &blockObj)
-		if err != nil {
-			utils.GetLogInstance().Warn("[SYNC] unable to decode received new block")
-			return response, err
-		}
-		node.stateSync.AddNewBlock(request.PeerHash, &blockObj)
-
-	case downloader_pb.DownloaderRequest_REGISTER:
-		peerID := binary.BigEndian.Uint32(request.PeerHash)
-		if _, ok := node.peerRegistrationRecord[peerID]; ok {
-			response.Type = downloader_pb.DownloaderResponse_FAIL
-			return response, nil
-		} else if len(node.peerRegistrationRecord) >= maxBroadcastNodes {
-			response.Type = downloader_pb.DownloaderResponse_FAIL
-			return response, nil
-		} else {
-			peer, ok := node.Consensus.GetPeerFromID(peerID)
-			if !ok {
-				utils.GetLogInstance().Warn("[SYNC] unable to get peer from peerID", "peerID", peerID)
+		for _, bytes := range hashes {
+			data, err := node.blockchain.TrieNode(common.BytesToHash(bytes))
+			if err == nil && len(data) > 0 {
+				response.Payload = append(response.Payload, data)
 			}
-			client := downloader.ClientSetup(peer.IP, GetSyncingPort(peer.Port))
-			if client == nil {
-				utils.GetLogInstance().Warn("[SYNC] unable to setup client")
-				return response, nil
+		}
+	case downloader_pb.DownloaderRequest_RECEIPTS:
+		hashes := request.Hashes
+		if len(hashes) > syncing.MaxBlocksPerRequest {
+			hashes = hashes[:syncing.MaxBlocksPerRequest]
+		}
+		for _, bytes := range hashes {
+			hash := common.BytesToHash(bytes)
+			if node.blockchain.GetHeaderByHash(hash) == nil {
+				break
 			}
-			utils.GetLogInstance().Debug("[SYNC] client setup correctly", "client", client)
-			config := &syncConfig{timestamp: time.Now().UnixNano(), client: client}
-			node.stateMutex.Lock()
-			node.peerRegistrationRecord[peerID] = config
-			node.stateMutex.Unlock()
-			utils.GetLogInstance().Debug("[SYNC] register peerID success", "peerID", peerID)
-			response.Type = downloader_pb.DownloaderResponse_SUCCESS
+			encodedReceipts, err := rlp.EncodeToBytes(node.blockchain.GetReceiptsByHash(hash))
+			if err != nil {
+				break
+			}
+			response.Payload = append(response.Payload, encodedReceipts)
 		}
-	case downloader_pb.DownloaderRequest_REGISTERTIMEOUT:
-		if node.State == NodeNotInSync {
-			count := node.stateSync.RegisterNodeInfo()
-			utils.GetLogInstance().Debug("[SYNC] extra node registered", "number", count)
+	case downloader_pb.DownloaderRequest_SHARDSTATES:
+		hashes := request.Hashes
+		if len(hashes) > syncing.MaxBlocksPerRequest {
+			hashes = hashes[:syncing.MaxBlocksPerRequest]
+		}
+		for _, bytes := range hashes {
+			shardState := node.blockchain.GetShardStateByHash(common.BytesToHash(bytes))
+			if shardState == nil {
+				break
+			}
+			encodedShardState, err := rlp.EncodeToBytes(shardState)
+			if err != nil {
+				break
+			}
+			response.Payload = append(response.Payload, encodedShardState)
 		}
 	}
 	return response, nil
 }
 
-// SendNewBlockToUnsync send latest verified block to unsync, registered nodes
+// SendNewBlockToUnsync sends the latest verified block to the syncing peers
+// subscribed to the blocks.
 func (node *Node) SendNewBlockToUnsync() {
 	for {
 		block := <-node.Consensus.VerifiedNewBlock
-		blockHash, err := rlp.EncodeToBytes(block)
+		encodedBlock, err := rlp.EncodeToBytes(block)
 		if err != nil {
 			utils.GetLogInstance().Warn("[SYNC] unable to encode block to hashes")
 			continue
 		}
-
-		// really need to have a unique id independent of ip/port
-		selfPeerID := utils.GetUniqueIDFromIPPort(node.SelfPeer.IP, node.SelfPeer.Port)
-		utils.GetLogInstance().Debug("[SYNC] peerRegistration Record", "peerID", selfPeerID, "number", len(node.peerRegistrationRecord))
-
-		for peerID, config := range node.peerRegistrationRecord {
-			elapseTime := time.Now().UnixNano() - config.timestamp
-			if elapseTime > broadcastTimeout {
-				utils.GetLogInstance().Warn("[SYNC] SendNewBlockToUnsync to peer timeout", "peerID", peerID)
-				// send last time and delete
-				config.client.PushNewBlock(selfPeerID, blockHash, true)
-				node.stateMutex.Lock()
-				node.peerRegistrationRecord[peerID].client.Close()
-				delete(node.peerRegistrationRecord, peerID)
-				node.stateMutex.Unlock()
-				continue
-			}
-			response := config.client.PushNewBlock(selfPeerID, blockHash, false)
-			if response != nil && response.Type == downloader_pb.DownloaderResponse_INSYNC {
-				node.stateMutex.Lock()
-				node.peerRegistrationRecord[peerID].client.Close()
-				delete(node.peerRegistrationRecord, peerID)
-				node.stateMutex.Unlock()
-			}
-		}
+		utils.GetLogInstance().Debug("[SYNC] broadcasting new block", "blockHeight", block.NumberU64(), "subscribers", node.downloaderServer.SubscriberCount())
+		node.downloaderServer.BroadcastBlock(encodedBlock)
 	}
 }
 
@@ -626,53 +848,15 @@ func (node *Node) RemovePeersHandler() {
 	}
 }
 
-//UpdateStakingList updates the stakes of every node.
-func (node *Node) UpdateStakingList(block *types.Block) error {
-	signerType := types.HomesteadSigner{}
-	txns := block.Transactions()
-	for i := range txns {
-		txn := txns[i]
-		value := txn.Value().Int64()
-		currentSender, _ := types.Sender(signerType, txn)
-		_, isPresent := node.CurrentStakes[currentSender]
-		toAddress := txn.To()
-		if *toAddress != node.StakingContractAddress { //Not a address aimed at the staking contract.
-			continue
-		}
-		//This should be based on a switch case on function signature.
-		//TODO (ak) https://github.com/harmony-one/harmony/issues/430
-		if value > int64(0) { //If value >0 means its a staking deposit transaction
-			if isPresent {
-				//This means this node has increaserd its stake
-				node.CurrentStakes[currentSender] += value
-			} else {
-				node.CurrentStakes[currentSender] = value
-			}
-		} else { //This means node has withdrawn stake.
-			getData := txn.Data()
-			value := decodeStakeCall(getData) //Value being withdrawn
-			if isPresent {
-				//This means this node has increaserd its stake
-				if node.CurrentStakes[currentSender] > value {
-					node.CurrentStakes[currentSender] -= value
-				} else if node.CurrentStakes[currentSender] == value {
-					delete(node.CurrentStakes, currentSender)
-				} else {
-					continue //Overdraft protection.
-				}
-			} else {
-				node.CurrentStakes[currentSender] = value
-			}
-		}
+// CurrentStakes returns the stake of every validator in the current state.
+func (node *Node) CurrentStakes() (map[common.Address]*big.Int, error) {
+	state, err := node.blockchain.State()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return core_staking.Stakes(state), nil
 }
 
-func decodeStakeCall(getData []byte) int64 {
-	value := new(big.Int)
-	value.SetBytes(getData[4:]) //Escape the method call.
-	return value.Int64()
-}
 func (node *Node) setupForShardLeader() {
 	// Register explorer service.
 	node.serviceManager.RegisterService(service_manager.SupportExplorer, explorer.New(&node.SelfPeer))
@@ -681,12 +865,54 @@ func (node *Node) setupForShardLeader() {
 	// Register new block service.
 	node.serviceManager.RegisterService(service_manager.BlockProposal, blockproposal.New(node.Consensus.ReadySignal, node.WaitForConsensusReady))
 	// Register client support service.
-	node.serviceManager.RegisterService(service_manager.ClientSupport, clientsupport.New(node.blockchain.State, node.CallFaucetContract, node.getDeployedStakingContract, node.SelfPeer.IP, node.SelfPeer.Port))
+	node.serviceManager.RegisterService(service_manager.ClientSupport, clientsupport.New(node.blockchain.State, node.CallFaucetContract, node.blockchain.GetProof, node.currentBlockNumber, node.TxPool.AddRemote, node.blockchain.GetTransaction, node.blockchain.GetReceipt, node.getPoolTransaction, node.blockchain.Call, node.blockchain.EstimateGas, node.blockchain.HarmonyConfig().UnbondingReleaseBlock, node.SelfPeer.IP, node.SelfPeer.Port))
+	// Register JSON-RPC service.
+	node.setupJSONRPC()
 	// Register randomness service
 	node.serviceManager.RegisterService(service_manager.Randomness, randomness_service.New(node.DRand))
+	// Register beacon header follower service.
+	node.setupBeaconFollower()
 }
 
 func (node *Node) setupForShardValidator() {
+	// Register JSON-RPC service.
+	node.setupJSONRPC()
+	// Register beacon header follower service.
+	node.setupBeaconFollower()
+}
+
+// setupJSONRPC registers the JSON-RPC service when its HTTP or WebSocket
+// endpoint is enabled.
+func (node *Node) setupJSONRPC() {
+	if !node.RPCConfig.HTTP && !node.RPCConfig.WS {
+		return
+	}
+	node.serviceManager.RegisterService(service_manager.JSONRPC, jsonrpc.New(hmyapi.GetAPIs(node.blockchain, node.TxPool, node.SyncStatus), node.RPCConfig, node.SelfPeer.Port))
+}
+
+// setupBeaconFollower registers the beacon header follower service on the nodes
+// of the shards other than the beacon chain. The beacon headers are downloaded
+// from the syncing servers of the beacon chain peers. The beacon chain is not
+// followed if the genesis doesn't give the beacon committee, which signs the
+// first beacon headers.
+func (node *Node) setupBeaconFollower() {
+	beacon := node.blockchain.BeaconHeaders()
+	if beacon == nil {
+		return
+	}
+	if !node.Consensus.HasShardPublicKeys(core.BeaconShardID) {
+		utils.GetLogInstance().Warn("No genesis committee of the beacon chain, the beacon chain is not followed")
+		return
+	}
+	if len(node.BCPeers) == 0 {
+		utils.GetLogInstance().Warn("No beacon peers, the missing beacon headers can't be downloaded")
+	}
+	peers := make([]p2p.Peer, len(node.BCPeers))
+	for i, peer := range node.BCPeers {
+		peers[i] = peer
+		peers[i].Port = GetSyncingPort(peer.Port)
+	}
+	node.serviceManager.RegisterService(service_manager.BeaconFollower, beaconfollower.New(node.host, beacon, peers))
 }
 
 func (node *Node) setupForBeaconLeader() {
@@ -709,7 +935,9 @@ func (node *Node) setupForBeaconLeader() {
 	// Register new block service.
 	node.serviceManager.RegisterService(service_manager.BlockProposal, blockproposal.New(node.Consensus.ReadySignal, node.WaitForConsensusReady))
 	// Register client support service.
-	node.serviceManager.RegisterService(service_manager.ClientSupport, clientsupport.New(node.blockchain.State, node.CallFaucetContract, node.getDeployedStakingContract, node.SelfPeer.IP, node.SelfPeer.Port))
+	node.serviceManager.RegisterService(service_manager.ClientSupport, clientsupport.New(node.blockchain.State, node.CallFaucetContract, node.blockchain.GetProof, node.currentBlockNumber, node.TxPool.AddRemote, node.blockchain.GetTransaction, node.blockchain.GetReceipt, node.getPoolTransaction, node.blockchain.Call, node.blockchain.EstimateGas, node.blockchain.HarmonyConfig().UnbondingReleaseBlock, node.SelfPeer.IP, node.SelfPeer.Port))
+	// Register JSON-RPC service.
+	node.setupJSONRPC()
 	// Register randomness service
 	node.serviceManager.RegisterService(service_manager.Randomness, randomness_service.New(node.DRand))
 
@@ -729,6 +957,8 @@ func (node *Node) setupForBeaconValidator() {
 	node.serviceManager.RegisterService(service_manager.PeerDiscovery, discovery.New(node.host, "0", chanPeer, nil))
 	// Register networkinfo service. "0" is the beacon shard ID
 	node.serviceManager.RegisterService(service_manager.NetworkInfo, networkinfo.New(node.host, "0", chanPeer))
+	// Register JSON-RPC service.
+	node.setupJSONRPC()
 	// Register randomness service
 	node.serviceManager.RegisterService(service_manager.Randomness, randomness_service.New(node.DRand))
 }
@@ -745,7 +975,7 @@ func (node *Node) setupForNewNode() {
 	}
 
 	// Register staking service.
-	node.serviceManager.RegisterService(service_manager.Staking, staking.New(node.AccountKey, 0, stakingPeer))
+	node.serviceManager.RegisterService(service_manager.Staking, staking.New(node.AccountKey, node.SelfPeer.PubKey, 0, stakingPeer))
 	// Register peer discovery service. "0" is the beacon shard ID
 	node.serviceManager.RegisterService(service_manager.PeerDiscovery, discovery.New(node.host, "0", chanPeer, stakingPeer))
 	// Register networkinfo service. "0" is the beacon shard ID
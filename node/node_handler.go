@@ -10,16 +10,21 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/bls/ffi/go/bls"
+	clientService "github.com/harmony-one/harmony/api/client/service"
+	client "github.com/harmony-one/harmony/api/client/service/proto"
 	"github.com/harmony-one/harmony/api/proto"
 	proto_discovery "github.com/harmony-one/harmony/api/proto/discovery"
 	proto_identity "github.com/harmony-one/harmony/api/proto/identity"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/crypto/pki"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/host"
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
 )
 
 const (
@@ -47,7 +52,11 @@ func (node *Node) StreamHandler(s p2p.Stream) {
 		return
 	}
 
-	node.messageHandler(content, "")
+	var streamSender peer.ID
+	if stream, ok := s.(net.Stream); ok {
+		streamSender = stream.Conn().RemotePeer()
+	}
+	node.messageHandler(content, "", streamSender)
 }
 
 // ReceiveGroupMessage use libp2p pubsub mechanism to receive broadcast messages
@@ -63,14 +72,16 @@ func (node *Node) ReceiveGroupMessage() {
 			//			utils.GetLogInstance().Info("[PUBSUB]", "received group msg", len(msg), "sender", sender)
 			if err == nil {
 				// skip the first 5 bytes, 1 byte is p2p type, 4 bytes are message size
-				node.messageHandler(msg[5:], string(sender))
+				node.messageHandler(msg[5:], string(sender), "")
 			}
 		}
 	}
 }
 
-// messageHandler parses the message and dispatch the actions
-func (node *Node) messageHandler(content []byte, sender string) {
+// messageHandler parses the message and dispatch the actions. The sender is the
+// publisher of a group message, and the stream sender the peer which sent a
+// direct message.
+func (node *Node) messageHandler(content []byte, sender string, streamSender peer.ID) {
 	//	node.MaybeBroadcastAsValidator(content)
 
 	consensusObj := node.Consensus
@@ -134,7 +145,7 @@ func (node *Node) messageHandler(content []byte, sender string) {
 		switch actionType {
 		case proto_node.Transaction:
 			utils.GetLogInstance().Info("NET: received message: Node/Transaction")
-			node.transactionMessageHandler(msgPayload)
+			node.transactionMessageHandler(msgPayload, streamSender)
 		case proto_node.Block:
 			utils.GetLogInstance().Info("NET: received message: Node/Block")
 			blockMsgType := proto_node.BlockMessageType(msgPayload[0])
@@ -176,19 +187,25 @@ func (node *Node) messageHandler(content []byte, sender string) {
 
 				utils.GetLogInstance().Debug("Blockchain Report", "totalNumBlocks", blockCount, "avgBlockSizeInCurrentEpoch", avgBlockSizeInBytes, "totalNumTxs", txCount, "avgTxSzieInCurrentEpoch", avgTxSize)
 
+				// Flush the transaction journal and the recent states kept in memory before exiting.
+				node.TxPool.Stop()
+				node.blockchain.Stop()
 				os.Exit(0)
 			}
 		case proto_node.PING:
 			node.pingMessageHandler(msgPayload, sender)
 		case proto_node.PONG:
 			node.pongMessageHandler(msgPayload)
+		case proto_node.Receipt:
+			utils.GetLogInstance().Info("NET: received message: Node/Receipt")
+			node.receiptMessageHandler(msgPayload)
 		}
 	default:
 		utils.GetLogInstance().Error("Unknown", "MsgCategory", msgCategory)
 	}
 }
 
-func (node *Node) transactionMessageHandler(msgPayload []byte) {
+func (node *Node) transactionMessageHandler(msgPayload []byte, streamSender peer.ID) {
 	txMessageType := proto_node.TransactionMessageType(msgPayload[0])
 
 	switch txMessageType {
@@ -198,7 +215,22 @@ func (node *Node) transactionMessageHandler(msgPayload []byte) {
 		if err != nil {
 			utils.GetLogInstance().Error("Failed to deserialize transaction list", "error", err)
 		}
-		node.addPendingTransactions(txs)
+		errs := node.addRemoteTransactions(txs)
+		// Only the direct senders are answered, so that a gossiped batch doesn't
+		// make every receiving node reply to its publisher.
+		if rejected := rejectedTransactions(txs, errs); len(rejected) > 0 && streamSender != "" {
+			go node.sendRejectedTransactions(streamSender, rejected)
+		}
+
+	case proto_node.Rejected:
+		rejected := []proto_node.RejectedTransaction{}
+		if err := rlp.DecodeBytes(msgPayload[1:], &rejected); err != nil {
+			utils.GetLogInstance().Error("Failed to deserialize rejected transactions", "error", err)
+			return
+		}
+		for _, tx := range rejected {
+			utils.GetLogInstance().Warn("Transaction rejected by peer", "hash", tx.TxHash, "code", client.TransactionErrorCode(tx.ErrorCode), "error", tx.ErrorMessage)
+		}
 
 	case proto_node.Request:
 		reader := bytes.NewBuffer(msgPayload[1:])
@@ -216,24 +248,85 @@ func (node *Node) transactionMessageHandler(msgPayload []byte) {
 		}
 
 		var txToReturn []*types.Transaction
-		for _, tx := range node.pendingTransactions {
-			if txIDs[tx.Hash()] {
+		for txID := range txIDs {
+			if tx := node.TxPool.Get(txID); tx != nil {
 				txToReturn = append(txToReturn, tx)
 			}
 		}
 	}
 }
 
+// rejectedTransactions returns the transactions rejected by the transaction pool,
+// with the error codes the client service reports for them. The transactions
+// already in the pool are not reported.
+func rejectedTransactions(txs types.Transactions, errs []error) []proto_node.RejectedTransaction {
+	rejected := []proto_node.RejectedTransaction{}
+	for i, err := range errs {
+		if err != nil && err != core.ErrKnownTransaction {
+			rejected = append(rejected, proto_node.RejectedTransaction{
+				TxHash:       txs[i].Hash(),
+				ErrorCode:    uint32(clientService.TransactionErrorCode(err)),
+				ErrorMessage: err.Error(),
+			})
+		}
+	}
+	return rejected
+}
+
+// sendRejectedTransactions sends the error codes of rejected transactions back
+// to the peer which sent them.
+func (node *Node) sendRejectedTransactions(peerID peer.ID, rejected []proto_node.RejectedTransaction) {
+	msg := host.ConstructP2pMessage(byte(0), proto_node.ConstructRejectedTransactionsMessage(rejected))
+	if err := node.host.SendMessage(p2p.Peer{PeerID: peerID}, msg); err != nil {
+		utils.GetLogInstance().Debug("Failed to report rejected transactions", "peer", peerID, "error", err)
+	}
+}
+
 // BroadcastNewBlock is called by consensus leader to sync new blocks with other clients/nodes.
-// NOTE: For now, just send to the client (basically not broadcasting)
+// The blocks of the beacon chain are sent to the beacon group, where the nodes of
+// the other shards follow them.
 // TODO (lc): broadcast the new blocks to new nodes doing state sync
 func (node *Node) BroadcastNewBlock(newBlock *types.Block) {
+	msg := proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock})
+	if utils.UseLibP2P {
+		if node.ClientPeer != nil || node.Consensus.ShardID == core.BeaconShardID {
+			utils.GetLogInstance().Debug("Sending new block to the beacon group", "blockNum", newBlock.NumberU64())
+			node.host.SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, host.ConstructP2pMessage(byte(0), msg))
+		}
+		return
+	}
 	if node.ClientPeer != nil {
 		utils.GetLogInstance().Debug("Sending new block to client", "client", node.ClientPeer)
+		node.SendMessage(*node.ClientPeer, msg)
+	}
+}
+
+// receiptMessageHandler keeps the cross-shard receipts proofs sent to this shard
+// until they are included in a block.
+func (node *Node) receiptMessageHandler(msgPayload []byte) {
+	cxp := &types.CXReceiptsProof{}
+	if err := rlp.DecodeBytes(msgPayload, cxp); err != nil {
+		utils.GetLogInstance().Error("Failed to deserialize cross-shard receipts proof", "error", err)
+		return
+	}
+	if err := node.blockchain.ValidateCXReceiptsProof(cxp); err != nil {
+		utils.GetLogInstance().Debug("Invalid cross-shard receipts proof", "error", err)
+		return
+	}
+	node.addPendingCXReceipts(cxp)
+}
+
+// BroadcastCXReceipts is called by consensus leader to send the cross-shard
+// receipts of a new block, with their proof, to the destination shards.
+func (node *Node) BroadcastCXReceipts(newBlock *types.Block) {
+	cxReceipts := node.blockchain.ReadCXReceipts(newBlock.Hash())
+	for _, toShardID := range cxReceipts.ToShardIDs() {
+		cxp := types.NewCXReceiptsProof(newBlock.Header(), cxReceipts, toShardID)
+		utils.GetLogInstance().Debug("Sending cross-shard receipts", "toShard", toShardID, "blockNum", newBlock.NumberU64(), "numReceipts", len(cxp.Receipts))
 		if utils.UseLibP2P {
-			node.host.SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock}))
+			node.host.SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, host.ConstructP2pMessage(byte(0), proto_node.ConstructCXReceiptsProofMessage(cxp)))
 		} else {
-			node.SendMessage(*node.ClientPeer, proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock}))
+			utils.GetLogInstance().Warn("Cross-shard receipts need libp2p to reach other shards", "toShard", toShardID)
 		}
 	}
 }
@@ -242,7 +335,7 @@ func (node *Node) BroadcastNewBlock(newBlock *types.Block) {
 func (node *Node) VerifyNewBlock(newBlock *types.Block) bool {
 	err := node.blockchain.ValidateNewBlock(newBlock, pki.GetAddressFromPublicKey(node.SelfPeer.PubKey))
 	if err != nil {
-		utils.GetLogInstance().Debug("Failed verifying new block", "Error", err, "tx", newBlock.Transactions()[0])
+		utils.GetLogInstance().Debug("Failed verifying new block", "Error", err, "numTx", len(newBlock.Transactions()))
 		return false
 	}
 
@@ -256,14 +349,15 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) bool {
 // PostConsensusProcessing is called by consensus participants, after consensus is done, to:
 // 1. add the new block to blockchain
 // 2. [leader] send new block to the client
+// 3. [leader] send the cross-shard receipts of the block to their destination shards
 func (node *Node) PostConsensusProcessing(newBlock *types.Block) {
-	if node.Role == BeaconLeader || node.Role == BeaconValidator {
-		node.UpdateStakingList(newBlock)
-	}
 	if node.Consensus.IsLeader {
 		node.BroadcastNewBlock(newBlock)
 	}
 	node.AddNewBlock(newBlock)
+	if node.Consensus.IsLeader {
+		node.BroadcastCXReceipts(newBlock)
+	}
 
 	// TODO: enable drand only for beacon chain
 	if node.DRand != nil {
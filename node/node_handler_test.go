@@ -1,10 +1,17 @@
 package node
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/golang/mock/gomock"
+	client "github.com/harmony-one/harmony/api/client/service/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/p2pimpl"
@@ -20,7 +27,7 @@ func TestNodeStreamHandler(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -44,7 +51,7 @@ func TestAddNewBlock(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 
 	selectedTxs := node.getTransactionsForNewBlock(MaxNumberOfTransactionsPerBlock)
 	node.Worker.CommitTransactions(selectedTxs)
@@ -67,7 +74,7 @@ func TestVerifyNewBlock(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 
 	selectedTxs := node.getTransactionsForNewBlock(MaxNumberOfTransactionsPerBlock)
 	node.Worker.CommitTransactions(selectedTxs)
@@ -77,3 +84,58 @@ func TestVerifyNewBlock(t *testing.T) {
 		t.Error("New block is not verified successfully")
 	}
 }
+
+func TestTransactionMessageHandler(t *testing.T) {
+	_, pubKey := utils.GenKey("1", "2")
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "8882", PubKey: pubKey}
+	validator := p2p.Peer{IP: "127.0.0.1", Port: "8885"}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9902")
+	host, err := p2pimpl.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
+	node := New(host, consensus, nil, nil, nil)
+
+	// Transactions arriving out of nonce order are reordered by the pool.
+	txs := types.Transactions{}
+	for _, nonce := range []uint64{1, 0} {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.BytesToAddress([]byte{1}), 0, big.NewInt(1), params.TxGas, nil, nil), types.HomesteadSigner{}, node.TestBankKeys[0])
+		txs = append(txs, tx)
+	}
+	pendingBefore := node.pendingTransactionCount()
+	node.transactionMessageHandler(proto_node.ConstructTransactionListMessageAccount(txs)[2:], "")
+	if pending := node.pendingTransactionCount(); pending != pendingBefore+2 {
+		t.Fatalf("expected %d pending transactions, got %d", pendingBefore+2, pending)
+	}
+
+	selectedTxs := node.getTransactionsForNewBlock(MaxNumberOfTransactionsPerBlock)
+	nonces := []uint64{}
+	for _, tx := range selectedTxs {
+		if tx.Hash() == txs[0].Hash() || tx.Hash() == txs[1].Hash() {
+			nonces = append(nonces, tx.Nonce())
+		}
+	}
+	if len(nonces) != 2 || nonces[0] != 0 || nonces[1] != 1 {
+		t.Errorf("expected both transactions selected in nonce order, got nonces %v", nonces)
+	}
+}
+
+func TestRejectedTransactions(t *testing.T) {
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{}, 0, big.NewInt(0), 21000, big.NewInt(1), nil),
+		types.NewTransaction(1, common.Address{}, 1, big.NewInt(0), 21000, big.NewInt(1), nil),
+		types.NewTransaction(2, common.Address{}, 0, big.NewInt(0), 21000, big.NewInt(1), nil),
+	}
+	// Transactions already in the pool are not reported.
+	rejected := rejectedTransactions(txs, []error{nil, core.ErrInvalidShard, core.ErrKnownTransaction})
+	if len(rejected) != 1 {
+		t.Fatalf("Expected 1 rejected transaction, got %v", len(rejected))
+	}
+	if rejected[0].TxHash != txs[1].Hash() {
+		t.Errorf("Expected hash %v, got %v", txs[1].Hash().Hex(), rejected[0].TxHash.Hex())
+	}
+	if code := client.TransactionErrorCode(rejected[0].ErrorCode); code != client.TransactionErrorCode_TX_INVALID_SHARD {
+		t.Errorf("Expected %v, got %v", client.TransactionErrorCode_TX_INVALID_SHARD, code)
+	}
+}
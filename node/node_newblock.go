@@ -8,6 +8,59 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
+// BlockProposalPolicy decides when the leader proposes a new block and how many
+// transactions go into it.
+type BlockProposalPolicy struct {
+	BlockTime          time.Duration // Target time between two blocks with pending transactions
+	EmptyBlockInterval time.Duration // Time after which an empty block is proposed, 0 means no empty blocks
+	MaxTransactions    int           // Maximum number of transactions in a block
+	MaxGas             uint64        // Maximum total gas limit of the transactions in a block, 0 means the block gas limit
+	MaxBytes           uint64        // Maximum total size of the transactions in a block, 0 means no limit
+}
+
+// DefaultBlockProposalPolicy is the block proposal policy used by default.
+var DefaultBlockProposalPolicy = BlockProposalPolicy{
+	BlockTime:          5 * time.Second,
+	EmptyBlockInterval: time.Minute,
+	MaxTransactions:    MaxNumberOfTransactionsPerBlock,
+}
+
+// proposalCheckInterval is how often the leader checks whether to propose.
+const proposalCheckInterval = 100 * time.Millisecond
+
+// fill returns the transactions, in the given order, that fit in a block, and
+// whether they fill the block so that it should be proposed right away.
+func (policy *BlockProposalPolicy) fill(txs types.Transactions) (types.Transactions, bool) {
+	var gas, size uint64
+	for i, tx := range txs {
+		if policy.MaxTransactions > 0 && i >= policy.MaxTransactions {
+			return txs[:i], true
+		}
+		gas += tx.Gas()
+		size += uint64(tx.Size())
+		if (policy.MaxGas > 0 && gas > policy.MaxGas) || (policy.MaxBytes > 0 && size > policy.MaxBytes) {
+			return txs[:i], true
+		}
+	}
+	full := policy.MaxTransactions > 0 && len(txs) >= policy.MaxTransactions
+	return txs, full
+}
+
+// shouldPropose decides whether to propose a block, given the time since the
+// last block, whether the block would be full and whether there is anything to
+// include in it.
+func (policy *BlockProposalPolicy) shouldPropose(sinceLastBlock time.Duration, full bool, hasPending bool) bool {
+	switch {
+	case full:
+		return true
+	case hasPending && sinceLastBlock >= policy.BlockTime:
+		return true
+	case policy.EmptyBlockInterval > 0 && sinceLastBlock >= policy.EmptyBlockInterval:
+		return true
+	}
+	return false
+}
+
 // WaitForConsensusReady listen for the readiness signal from consensus and generate new block for consensus.
 func (node *Node) WaitForConsensusReady(readySignal chan struct{}, stopChan chan struct{}, stoppedChan chan struct{}) {
 	go func() {
@@ -17,7 +70,6 @@ func (node *Node) WaitForConsensusReady(readySignal chan struct{}, stopChan chan
 		utils.GetLogInstance().Debug("Waiting for Consensus ready")
 		time.Sleep(15 * time.Second) // Wait for other nodes to be ready (test-only)
 
-		firstTime := true
 		var newBlock *types.Block
 		timeoutCount := 0
 		for {
@@ -33,42 +85,61 @@ func (node *Node) WaitForConsensusReady(readySignal chan struct{}, stopChan chan
 				return
 			}
 
-			for {
-				// threshold and firstTime are for the test-only built-in smart contract tx. TODO: remove in production
-				threshold := 1
-				if firstTime {
-					threshold = 2
-					firstTime = false
+			newBlock = nil
+			for newBlock == nil {
+				select {
+				case <-stopChan:
+					return
+				default:
 				}
-				utils.GetLogInstance().Debug("STARTING BLOCK", "threshold", threshold, "pendingTransactions", len(node.pendingTransactions))
-				if len(node.pendingTransactions) >= threshold {
-					// Normal tx block consensus
-					selectedTxs := node.getTransactionsForNewBlock(MaxNumberOfTransactionsPerBlock)
-					if len(selectedTxs) != 0 {
-						node.Worker.CommitTransactions(selectedTxs)
-						block, err := node.Worker.Commit()
-						if err != nil {
-							utils.GetLogInstance().Debug("Failed commiting new block", "Error", err)
-						} else {
-							// add new shard state if it's epoch block
-							node.addNewShardState(block)
-							newBlock = block
-							break
-						}
-					}
+				newBlock = node.maybeProposeNewBlock()
+				if newBlock == nil {
+					time.Sleep(proposalCheckInterval)
 				}
-				// If not enough transactions to run Consensus,
-				// periodically check whether we have enough transactions to package into block.
-				time.Sleep(1 * time.Second)
 			}
 			// Send the new block to Consensus so it can be confirmed.
-			if newBlock != nil {
-				node.BlockChannel <- newBlock
-			}
+			node.BlockChannel <- newBlock
 		}
 	}()
 }
 
+// maybeProposeNewBlock commits a new block on top of the current block when the
+// proposal policy says so, or returns nil.
+func (node *Node) maybeProposeNewBlock() *types.Block {
+	policy := node.ProposalPolicy
+	if policy.MaxGas == 0 {
+		policy.MaxGas = node.blockchain.GasLimit()
+	}
+	// The schedule follows the timestamp of the last block, so that block times
+	// stay regular regardless of how long consensus took.
+	lastBlockTime := time.Unix(node.blockchain.CurrentBlock().Time().Int64(), 0)
+	sinceLastBlock := time.Since(lastBlockTime)
+
+	txs, full := policy.fill(node.pendingTransactionsByPriceAndNonce())
+	hasPending := len(txs) > 0 || node.pendingCXReceiptCount() > 0
+	if !policy.shouldPropose(sinceLastBlock, full, hasPending) {
+		return nil
+	}
+	utils.GetLogInstance().Debug("STARTING BLOCK", "sinceLastBlock", sinceLastBlock, "full", full, "pendingTransactions", len(txs), "pendingCXReceipts", node.pendingCXReceiptCount())
+
+	selectedTxs := node.selectTransactionsForNewBlock(txs, len(txs))
+	incomingReceipts := node.getIncomingReceiptsForNewBlock()
+	if len(selectedTxs) == 0 && len(incomingReceipts) == 0 && (policy.EmptyBlockInterval == 0 || sinceLastBlock < policy.EmptyBlockInterval) {
+		// Nothing valid to include and no empty block due yet.
+		return nil
+	}
+	node.Worker.CommitTransactions(selectedTxs)
+	node.Worker.CommitReceipts(incomingReceipts)
+	block, err := node.Worker.Commit()
+	if err != nil {
+		utils.GetLogInstance().Debug("Failed commiting new block", "Error", err)
+		return nil
+	}
+	// add new shard state if it's epoch block
+	node.addNewShardState(block)
+	return block
+}
+
 func (node *Node) addNewShardState(block *types.Block) {
 	shardState := node.blockchain.GetNewShardState(block)
 	if shardState != nil {
@@ -82,17 +153,18 @@ func (node *Node) addNewShardState(block *types.Block) {
 }
 
 func (node *Node) addNewRandSeed(block *types.Block) {
+	config := node.blockchain.HarmonyConfig()
 	blockNumber := block.NumberU64()
-	if !core.CheckEpochBlock(blockNumber) {
+	if !config.IsEpochBlock(blockNumber) {
 		return
 	}
 
 	var rnd int64
-	epoch := core.GetEpochFromBlockNumber(blockNumber)
+	epoch := config.EpochOfBlock(blockNumber)
 	if epoch == 1 {
 		rnd = core.InitialSeed
 	} else {
-		number := core.GetPreviousEpochBlockNumber(blockNumber)
+		number := config.PreviousEpochBlock(blockNumber)
 		oldrnd := node.blockchain.GetRandSeedByNumber(number)
 		rnd = core.FakeGenRandSeed(oldrnd)
 	}
@@ -0,0 +1,69 @@
+package node
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func testTransactions(n int) types.Transactions {
+	txs := types.Transactions{}
+	for i := 0; i < n; i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, 0, big.NewInt(1), params.TxGas, nil, nil))
+	}
+	return txs
+}
+
+func TestBlockProposalPolicyFill(t *testing.T) {
+	txs := testTransactions(10)
+	tests := []struct {
+		policy BlockProposalPolicy
+		count  int
+		full   bool
+	}{
+		{BlockProposalPolicy{}, 10, false},
+		{BlockProposalPolicy{MaxTransactions: 20}, 10, false},
+		{BlockProposalPolicy{MaxTransactions: 10}, 10, true},
+		{BlockProposalPolicy{MaxTransactions: 4}, 4, true},
+		{BlockProposalPolicy{MaxGas: 3 * params.TxGas}, 3, true},
+		{BlockProposalPolicy{MaxGas: 3*params.TxGas - 1}, 2, true},
+		{BlockProposalPolicy{MaxBytes: uint64(txs[0].Size()) * 5}, 5, true},
+	}
+	for i, test := range tests {
+		selected, full := test.policy.fill(txs)
+		if len(selected) != test.count || full != test.full {
+			t.Errorf("test %d: expected %d transactions and full %v, got %d and %v", i, test.count, test.full, len(selected), full)
+		}
+	}
+}
+
+func TestBlockProposalPolicyShouldPropose(t *testing.T) {
+	policy := BlockProposalPolicy{BlockTime: 5 * time.Second, EmptyBlockInterval: time.Minute}
+	tests := []struct {
+		sinceLastBlock time.Duration
+		full           bool
+		hasPending     bool
+		propose        bool
+	}{
+		{time.Second, false, false, false},
+		{time.Second, false, true, false},
+		{time.Second, true, true, true},
+		{5 * time.Second, false, true, true},
+		{5 * time.Second, false, false, false},
+		{time.Minute, false, false, true},
+	}
+	for i, test := range tests {
+		if propose := policy.shouldPropose(test.sinceLastBlock, test.full, test.hasPending); propose != test.propose {
+			t.Errorf("test %d: expected propose %v, got %v", i, test.propose, propose)
+		}
+	}
+
+	policy.EmptyBlockInterval = 0
+	if policy.shouldPropose(time.Hour, false, false) {
+		t.Errorf("no empty block should be proposed when disabled")
+	}
+}
@@ -1,25 +1,27 @@
 package node
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
+
 	"github.com/harmony-one/harmony/drand"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/params"
 	proto_discovery "github.com/harmony-one/harmony/api/proto/discovery"
+	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/consensus"
+	"github.com/harmony-one/harmony/core"
+	core_staking "github.com/harmony-one/harmony/core/staking"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/crypto/pki"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/p2pimpl"
-	"golang.org/x/crypto/sha3"
 )
 
 func TestNewNode(t *testing.T) {
@@ -32,7 +34,7 @@ func TestNewNode(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 	if node.Consensus == nil {
 		t.Error("Consensus is not initialized for the node")
 	}
@@ -58,7 +60,7 @@ func TestGetSyncingPeers(t *testing.T) {
 
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
 
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 	peer := p2p.Peer{IP: "127.0.0.1", Port: "8000"}
 	peer2 := p2p.Peer{IP: "127.0.0.1", Port: "8001"}
 	node.Neighbors.Store("minh", peer)
@@ -101,7 +103,7 @@ func TestAddPeers(t *testing.T) {
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
 	dRand := drand.New(host, "0", []p2p.Peer{leader, validator}, leader, nil)
 
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 	node.DRand = dRand
 	r1 := node.AddPeers(peers1)
 	e1 := 2
@@ -175,14 +177,14 @@ func TestPingPongHandler(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader}, leader)
-	node := New(host, consensus, nil)
+	node := New(host, consensus, nil, nil, nil)
 	//go sendPingMessage(leader)
 	go sendPongMessage(node, leader)
 	go exitServer()
 	node.StartServer()
 }
 
-func TestUpdateStakingDeposit(t *testing.T) {
+func TestCurrentStakes(t *testing.T) {
 	_, pubKey := utils.GenKey("1", "2")
 	leader := p2p.Peer{IP: "127.0.0.1", Port: "8882", PubKey: pubKey}
 	validator := p2p.Peer{IP: "127.0.0.1", Port: "8885"}
@@ -193,38 +195,17 @@ func TestUpdateStakingDeposit(t *testing.T) {
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
 
-	node := New(host, consensus, nil)
-	node.CurrentStakes = make(map[common.Address]int64)
-
-	DepositContractPriKey, _ := crypto.GenerateKey()                                  //DepositContractPriKey is pk for contract
-	DepositContractAddress := crypto.PubkeyToAddress(DepositContractPriKey.PublicKey) //DepositContractAddress is the address for the contract
-	node.StakingContractAddress = DepositContractAddress
-	node.AccountKey, _ = crypto.GenerateKey()
-	Address := crypto.PubkeyToAddress(node.AccountKey.PublicKey)
-	callingFunction := "0xd0e30db0"
-	amount := new(big.Int)
-	amount.SetString("10", 10)
-	dataEnc := common.FromHex(callingFunction) //Deposit Does not take a argument, stake is transferred via amount.
-	tx1, err := types.SignTx(types.NewTransaction(0, DepositContractAddress, node.Consensus.ShardID, amount, params.TxGasContractCreation*10, nil, dataEnc), types.HomesteadSigner{}, node.AccountKey)
-
-	var txs []*types.Transaction
-	txs = append(txs, tx1)
-	header := &types.Header{Extra: []byte("hello")}
-	block := types.NewBlock(header, txs, nil)
-	node.UpdateStakingList(block)
-	if len(node.CurrentStakes) == 0 {
-		t.Error("New node's stake was not added")
-	}
-	value, ok := node.CurrentStakes[Address]
-	if !ok {
-		t.Error("The correct address was not added")
+	node := New(host, consensus, nil, nil, nil)
+	stakes, err := node.CurrentStakes()
+	if err != nil {
+		t.Fatalf("Failed to get the current stakes: %v", err)
 	}
-	if value != 10 {
-		t.Error("The correct stake value was not added")
+	if len(stakes) != 0 {
+		t.Errorf("Expected no stake in the genesis state, got %v", stakes)
 	}
 }
 
-func TestUpdateStakingWithdrawal(t *testing.T) {
+func TestCurrentStakesAfterStakingTransactions(t *testing.T) {
 	_, pubKey := utils.GenKey("1", "2")
 	leader := p2p.Peer{IP: "127.0.0.1", Port: "8882", PubKey: pubKey}
 	validator := p2p.Peer{IP: "127.0.0.1", Port: "8885"}
@@ -234,43 +215,109 @@ func TestUpdateStakingWithdrawal(t *testing.T) {
 		t.Fatalf("newhost failure: %v", err)
 	}
 	consensus := consensus.New(host, "0", []p2p.Peer{leader, validator}, leader)
+	node := New(host, consensus, nil, nil, nil)
+
+	validatorKey, delegatorKey := node.TestBankKeys[0], node.TestBankKeys[1]
+	validatorAddress := crypto.PubkeyToAddress(validatorKey.PublicKey)
+	stakingTx := func(key *ecdsa.PrivateKey, nonce uint64, value int64, msg *core_staking.Message) *types.Transaction {
+		tx, err := core_staking.NewTransaction(nonce, 0, big.NewInt(value), 100000, big.NewInt(0), msg)
+		if err != nil {
+			t.Fatalf("Failed to create staking transaction: %v", err)
+		}
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, key)
+		return tx
+	}
+	// addBlock applies the transactions with the worker and inserts the block.
+	addBlock := func(txs ...*types.Transaction) {
+		if err := node.Worker.UpdateCurrent(); err != nil {
+			t.Fatalf("Failed to update the worker: %v", err)
+		}
+		if err := node.Worker.CommitTransactions(txs); err != nil {
+			t.Fatalf("Failed to apply the staking transactions: %v", err)
+		}
+		block, err := node.Worker.Commit()
+		if err != nil {
+			t.Fatalf("Failed to commit the block: %v", err)
+		}
+		if _, err := node.blockchain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("Failed to insert the block: %v", err)
+		}
+	}
+	checkStake := func(expected int64) {
+		stakes, err := node.CurrentStakes()
+		if err != nil {
+			t.Fatalf("Failed to get the current stakes: %v", err)
+		}
+		if stake := stakes[validatorAddress]; stake == nil || stake.Int64() != expected {
+			t.Errorf("Expected stake %d, got %v", expected, stake)
+		}
+	}
 
-	node := New(host, consensus, nil)
-	node.CurrentStakes = make(map[common.Address]int64)
-
-	DepositContractPriKey, _ := crypto.GenerateKey()                                  //DepositContractPriKey is pk for contract
-	DepositContractAddress := crypto.PubkeyToAddress(DepositContractPriKey.PublicKey) //DepositContractAddress is the address for the contract
-	node.StakingContractAddress = DepositContractAddress
-	node.AccountKey, _ = crypto.GenerateKey()
-	Address := crypto.PubkeyToAddress(node.AccountKey.PublicKey)
-	node.CurrentStakes[Address] = int64(1010)
-
-	withdrawFnSignature := []byte("withdraw(uint)")
-	hash := sha3.NewLegacyKeccak256()
-	hash.Write(withdrawFnSignature)
-	methodID := hash.Sum(nil)[:4]
-
-	stake := "1000"
-	amount := new(big.Int)
-	amount.SetString(stake, 10)
-	paddedAmount := common.LeftPadBytes(amount.Bytes(), 32)
-
-	var dataEnc []byte
-	dataEnc = append(dataEnc, methodID...)
-	dataEnc = append(dataEnc, paddedAmount...)
-	tx, err := types.SignTx(types.NewTransaction(0, DepositContractAddress, node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, dataEnc), types.HomesteadSigner{}, node.AccountKey)
-
-	var txs []*types.Transaction
-	txs = append(txs, tx)
-	header := &types.Header{Extra: []byte("hello")}
-	block := types.NewBlock(header, txs, nil)
-	node.UpdateStakingList(block)
-	value, ok := node.CurrentStakes[Address]
-	if !ok {
-		t.Error("The correct address was not present")
+	addBlock(
+		stakingTx(validatorKey, 0, 1000, &core_staking.Message{
+			Directive:    core_staking.CreateValidator,
+			BLSPublicKey: pki.GetBLSPrivateKeyFromInt(1).GetPublicKey().Serialize(),
+		}),
+		stakingTx(delegatorKey, 0, 500, &core_staking.Message{Directive: core_staking.Delegate, Validator: validatorAddress}),
+	)
+	checkStake(1500)
+
+	addBlock(stakingTx(delegatorKey, 1, 0, &core_staking.Message{Directive: core_staking.Undelegate, Validator: validatorAddress, Amount: big.NewInt(200)}))
+	checkStake(1300)
+}
+
+func TestSyncStatus(t *testing.T) {
+	_, pubKey := utils.GenKey("1", "2")
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "8882", PubKey: pubKey}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9902")
+	host, err := p2pimpl.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
 	}
-	if value != 10 {
-		t.Error("The correct stake value was not subtracted")
+	consensus := consensus.New(host, "0", []p2p.Peer{leader}, leader)
+	node := New(host, consensus, nil, nil, nil)
+
+	// The state syncing is replaced while the status is served.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			node.setSyncState(NodeNotInSync, syncing.CreateStateSync("127.0.0.1", "9000"))
+			node.setSyncState(NodeReadyForConsensus, nil)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if status := node.SyncStatus(); status.Syncing {
+			t.Errorf("unexpected syncing status without target: %+v", status)
+		}
 	}
+	<-done
+}
 
+func TestBeaconGenesisOfTestNetwork(t *testing.T) {
+	_, pubKey := utils.GenKey("1", "2")
+	leader := p2p.Peer{IP: "127.0.0.1", Port: "8882", PubKey: pubKey}
+	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "9902")
+	host, err := p2pimpl.NewHost(&leader, priKey)
+	if err != nil {
+		t.Fatalf("newhost failure: %v", err)
+	}
+	beaconNode := New(host, consensus.New(host, "0", []p2p.Peer{leader}, leader), nil, nil, nil)
+	if beaconNode.Blockchain().BeaconHeaders() != nil {
+		t.Error("beacon node following the beacon chain")
+	}
+	shardNode := New(host, consensus.New(host, "1", []p2p.Peer{leader}, leader), nil, nil, nil)
+	beacon := shardNode.Blockchain().BeaconHeaders()
+	if beacon == nil {
+		t.Fatal("shard node not following the beacon chain")
+	}
+	// The first beacon header must follow the genesis of the beacon node.
+	header := &types.Header{Number: big.NewInt(1)}
+	if err := beacon.InsertHeader(header, nil); err != core.ErrBeaconGenesisMismatch {
+		t.Errorf("expected %v, got %v", core.ErrBeaconGenesisMismatch, err)
+	}
+	header.ParentHash = beaconNode.Blockchain().Genesis().Hash()
+	if err := beacon.InsertHeader(header, nil); err == core.ErrBeaconGenesisMismatch {
+		t.Error("beacon header following the beacon genesis rejected")
+	}
 }
@@ -23,6 +23,8 @@ type environment struct {
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
+	outcxs   types.CXReceipts       // cross-shard receipts sent by the block
+	incxs    types.CXReceiptsProofs // cross-shard receipts credited by the block
 }
 
 // Worker is the main object which takes care of submitting new work to consensus engine
@@ -52,6 +54,7 @@ func (w *Worker) SelectTransactionsForNewBlock(txs types.Transactions, maxNumTxs
 	for _, tx := range txs {
 		if tx.ShardID() != w.shardID {
 			invalid = append(invalid, tx)
+			continue
 		}
 		snap := w.current.state.Snapshot()
 		_, err := w.commitTransaction(tx, w.coinbase)
@@ -77,13 +80,18 @@ func (w *Worker) SelectTransactionsForNewBlock(txs types.Transactions, maxNumTxs
 func (w *Worker) commitTransaction(tx *types.Transaction, coinbase common.Address) ([]*types.Log, error) {
 	snap := w.current.state.Snapshot()
 
-	receipt, _, err := core.ApplyTransaction(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
+	// The logs are keyed by the transaction hash; the block hash is not known yet.
+	w.current.state.Prepare(tx.Hash(), common.Hash{}, len(w.current.txs))
+	receipt, cxReceipt, _, err := core.ApplyTransaction(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
 	if err != nil {
 		w.current.state.RevertToSnapshot(snap)
 		return nil, err
 	}
 	w.current.txs = append(w.current.txs, tx)
 	w.current.receipts = append(w.current.receipts, receipt)
+	if cxReceipt != nil {
+		w.current.outcxs = append(w.current.outcxs, cxReceipt)
+	}
 
 	return receipt.Logs, nil
 }
@@ -105,6 +113,15 @@ func (w *Worker) CommitTransactions(txs types.Transactions) error {
 	return nil
 }
 
+// CommitReceipts credits the given cross-shard receipts proofs to the current
+// state. It must be called after the transactions are committed.
+func (w *Worker) CommitReceipts(proofs types.CXReceiptsProofs) {
+	for _, cxp := range proofs {
+		core.ApplyIncomingReceipt(w.current.state, cxp)
+		w.current.incxs = append(w.current.incxs, cxp)
+	}
+}
+
 // UpdateCurrent updates the current environment with the current state and header.
 func (w *Worker) UpdateCurrent() error {
 	parent := w.chain.CurrentBlock()
@@ -148,7 +165,7 @@ func (w *Worker) GetCurrentReceipts() []*types.Receipt {
 // Commit generate a new block for the new txs.
 func (w *Worker) Commit() (*types.Block, error) {
 	s := w.current.state.Copy()
-	block, err := w.engine.Finalize(w.chain, w.current.header, s, w.current.txs, w.current.receipts)
+	block, err := w.engine.Finalize(w.chain, w.current.header, s, w.current.txs, w.current.receipts, w.current.outcxs, w.current.incxs)
 	if err != nil {
 		return nil, err
 	}
@@ -162,8 +179,8 @@ func New(config *params.ChainConfig, chain *core.BlockChain, engine consensus.En
 		chain:  chain,
 		engine: engine,
 	}
-	worker.gasFloor = 500000000000000000
-	worker.gasCeil = 1000000000000000000
+	worker.gasFloor = chain.HarmonyConfig().GasFloor
+	worker.gasCeil = chain.HarmonyConfig().GasCeil
 	worker.coinbase = coinbase
 	worker.shardID = shardID
 